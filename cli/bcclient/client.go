@@ -7,17 +7,22 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"net/url"
 )
 
 type joinPartyRequest struct {
 	Name string `json:"name"`
 }
 
+type joinPartyResponse struct {
+	Token string `json:"token"`
+}
+
 type leaderSelectionRequest struct {
 	Member string `json:"member"`
 }
 
-func makePartyRequest(path string, body interface{}, responseValue interface{}) (session string) {
+func makePartyRequest(path string, body interface{}, responseValue interface{}) {
 	bodyJson, err := json.Marshal(body)
 	if err != nil {
 		log.Fatalf("can't marshall body: %+v\n", err)
@@ -41,18 +46,15 @@ func makePartyRequest(path string, body interface{}, responseValue interface{})
 			log.Fatalf("can't unmarshall response: %+v\n", err)
 		}
 	}
-	cookies := response.Cookies()
-	for _, c := range cookies {
-		if c.Name == "session" {
-			return c.Value
-		}
-	}
-	panic("should have session")
 }
 
 func JoinGame(name string) (session string) {
-	session = makePartyRequest("party/join", joinPartyRequest{Name: name}, nil)
-	return
+	var response joinPartyResponse
+	makePartyRequest("party/join", joinPartyRequest{Name: name}, &response)
+	if response.Token == "" {
+		panic("should have session")
+	}
+	return response.Token
 }
 
 func makePlayerActionRequest(url string, body interface{}, session string) {
@@ -66,7 +68,7 @@ func makePlayerActionRequest(url string, body interface{}, session string) {
 	}
 	client := http.Client{}
 	request, err := http.NewRequest("POST", fmt.Sprintf("http://localhost:44324/%s", url), bytes.NewReader(bodyJson))
-	request.AddCookie(&http.Cookie{Name: "session", Value: session})
+	request.Header.Set("Authorization", "Bearer "+session)
 	if err != nil {
 		log.Fatalf("can't create request: %+v\n", err)
 	}
@@ -76,10 +78,22 @@ func makePlayerActionRequest(url string, body interface{}, session string) {
 	}
 }
 
+func MarkReady(session string) {
+	makePlayerActionRequest("actions/ready", nil, session)
+}
+
+func MarkNotReady(session string) {
+	makePlayerActionRequest("actions/not-ready", nil, session)
+}
+
 func StartGame(session string) {
 	makePlayerActionRequest("actions/start-game", nil, session)
 }
 
+func ForceStartGame(session string) {
+	makePlayerActionRequest("actions/force-start-game", nil, session)
+}
+
 func LeaderSelectsMember(session, name string) {
 	makePlayerActionRequest("actions/leader-selects-member", leaderSelectionRequest{Member: name}, session)
 }
@@ -107,3 +121,66 @@ func SucceedMission(session string) {
 func FailMission(session string) {
 	makePlayerActionRequest("actions/fail-mission", nil, session)
 }
+
+type MissionResult struct {
+	Success bool `json:"success"`
+	NbFails int  `json:"nbFails"`
+}
+
+type GameState struct {
+	Phase                     string          `json:"phase"`
+	Players                   []string        `json:"players"`
+	CurrentMission            int             `json:"currentMission"`
+	Leader                    string          `json:"leader"`
+	CurrentTeam               []string        `json:"currentTeam"`
+	PlayersWhoVoted           []string        `json:"playersWhoVoted"`
+	PlayersWhoWorkedOnMission []string        `json:"playersWhoWorkedOnMission"`
+	MissionResults            []MissionResult `json:"missionResults"`
+	VoteFailures              int             `json:"voteFailures"`
+	Allegiance                string          `json:"allegiance"`
+	KnownSpies                []string        `json:"knownSpies"`
+	Winner                    string          `json:"winner"`
+}
+
+func GetState(session string) (state GameState) {
+	client := http.Client{}
+	request, err := http.NewRequest("GET", "http://localhost:44324/game/state", nil)
+	if err != nil {
+		log.Fatalf("can't create request: %+v\n", err)
+	}
+	request.Header.Set("Authorization", "Bearer "+session)
+	response, err := client.Do(request)
+	if err != nil {
+		log.Fatalf("can't do request: %+v\n", err)
+	}
+	responseBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		log.Fatalf("can't read response body: %+v\n", err)
+	}
+	err = json.Unmarshal(responseBody, &state)
+	if err != nil {
+		log.Fatalf("can't unmarshall response: %+v\n", err)
+	}
+	return
+}
+
+func ExportTranscript(session, format string) string {
+	client := http.Client{}
+	request, err := http.NewRequest("GET", fmt.Sprintf("http://localhost:44324/game/export?format=%s", url.QueryEscape(format)), nil)
+	if err != nil {
+		log.Fatalf("can't create request: %+v\n", err)
+	}
+	request.Header.Set("Authorization", "Bearer "+session)
+	response, err := client.Do(request)
+	if err != nil {
+		log.Fatalf("can't do request: %+v\n", err)
+	}
+	responseBody, err := io.ReadAll(response.Body)
+	if err != nil {
+		log.Fatalf("can't read response body: %+v\n", err)
+	}
+	if response.StatusCode != http.StatusOK {
+		log.Fatalf("can't export transcript: %s %s\n", response.Status, responseBody)
+	}
+	return string(responseBody)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"image"
 	"strconv"
+	"strings"
 
 	"github.com/damien-springuel/bomb-canary/cli/bcclient"
 	"github.com/gizak/termui/v3"
@@ -18,6 +19,7 @@ const (
 	presets pageType = "presets"
 	actions pageType = "actions"
 	people  pageType = "people"
+	state   pageType = "state"
 )
 
 type Emulator struct {
@@ -117,6 +119,40 @@ func setLeaderToContext(ctx context.Context, leader string) context.Context {
 	return setValueToContext(ctx, "leader", leader)
 }
 
+func getStateLinesFromContext(ctx context.Context) []string {
+	lines, ok := getValueFromContext(ctx, "stateLines").([]string)
+	if !ok {
+		return nil
+	}
+	return lines
+}
+
+func setStateLinesToContext(ctx context.Context, lines []string) context.Context {
+	return setValueToContext(ctx, "stateLines", lines)
+}
+
+func formatState(s bcclient.GameState) []string {
+	lines := []string{
+		fmt.Sprintf("Phase: %s", s.Phase),
+		fmt.Sprintf("Players: %s", strings.Join(s.Players, ", ")),
+		fmt.Sprintf("Mission: %d", s.CurrentMission),
+		fmt.Sprintf("Leader: %s", s.Leader),
+		fmt.Sprintf("Team: %s", strings.Join(s.CurrentTeam, ", ")),
+		fmt.Sprintf("Voted: %s", strings.Join(s.PlayersWhoVoted, ", ")),
+		fmt.Sprintf("Worked on mission: %s", strings.Join(s.PlayersWhoWorkedOnMission, ", ")),
+		fmt.Sprintf("Vote failures: %d", s.VoteFailures),
+		fmt.Sprintf("Allegiance: %s", s.Allegiance),
+		fmt.Sprintf("Known spies: %s", strings.Join(s.KnownSpies, ", ")),
+	}
+	for i, result := range s.MissionResults {
+		lines = append(lines, fmt.Sprintf("Mission %d: success=%t, fails=%d", i+1, result.Success, result.NbFails))
+	}
+	if s.Winner != "" {
+		lines = append(lines, fmt.Sprintf("Winner: %s", s.Winner))
+	}
+	return lines
+}
+
 func createSetNameAction(name string) func(ctx context.Context) context.Context {
 	return func(ctx context.Context) context.Context {
 		ctx = setNameToContext(ctx, name)
@@ -186,14 +222,14 @@ func createdAndJoined10Players(ctx context.Context) context.Context {
 func started5PlayerGame(ctx context.Context) context.Context {
 	ctx = createdAndJoined5Players(ctx)
 	session := getSessionFromContext(ctx, "Alice")
-	bcclient.StartGame(session)
+	bcclient.ForceStartGame(session)
 	return ctx
 }
 
 func started10PlayerGame(ctx context.Context) context.Context {
 	ctx = createdAndJoined10Players(ctx)
 	session := getSessionFromContext(ctx, "Alice")
-	bcclient.StartGame(session)
+	bcclient.ForceStartGame(session)
 	return ctx
 }
 
@@ -485,6 +521,24 @@ func New() *Emulator {
 	actionPage := page{
 		title: "Action",
 		rows: []choice{
+			{
+				description: "Ready",
+				action: createActionWithName(func(ctx context.Context) context.Context {
+					name := getNameFromContext(ctx)
+					session := getSessionFromContext(ctx, name)
+					bcclient.MarkReady(session)
+					return ctx
+				}, "is ready?"),
+			},
+			{
+				description: "Not Ready",
+				action: createActionWithName(func(ctx context.Context) context.Context {
+					name := getNameFromContext(ctx)
+					session := getSessionFromContext(ctx, name)
+					bcclient.MarkNotReady(session)
+					return ctx
+				}, "is not ready?"),
+			},
 			{
 				description: "Start Game",
 				action: createActionWithName(func(ctx context.Context) context.Context {
@@ -494,6 +548,15 @@ func New() *Emulator {
 					return ctx
 				}, "is starting the game?"),
 			},
+			{
+				description: "Force Start Game",
+				action: createActionWithName(func(ctx context.Context) context.Context {
+					name := getNameFromContext(ctx)
+					session := getSessionFromContext(ctx, name)
+					bcclient.ForceStartGame(session)
+					return ctx
+				}, "is forcing the game to start?"),
+			},
 			{
 				description: "Leader Selects Member",
 				action: createActionWithName(func(ctx context.Context) context.Context {
@@ -573,6 +636,16 @@ func New() *Emulator {
 					return ctx
 				}, "is failing the mission?"),
 			},
+			{
+				description: "Show State",
+				action: createActionWithName(func(ctx context.Context) context.Context {
+					name := getNameFromContext(ctx)
+					session := getSessionFromContext(ctx, name)
+					ctx = setStateLinesToContext(ctx, formatState(bcclient.GetState(session)))
+					ctx = setActionDescToContext(ctx, name)
+					return setNextPageToContext(ctx, state)
+				}, "is looking at the state?"),
+			},
 		},
 	}
 
@@ -600,7 +673,25 @@ func (e *Emulator) updateList() {
 }
 
 func (e *Emulator) getCurrentPage() page {
-	return e.pages[getCurrentPageFromContext(e.ctx)]
+	currentPage := getCurrentPageFromContext(e.ctx)
+	if currentPage == state {
+		return e.statePage()
+	}
+	return e.pages[currentPage]
+}
+
+func (e *Emulator) statePage() page {
+	backToActions := func(ctx context.Context) context.Context {
+		ctx = setNilNextPageToContext(ctx)
+		ctx = setEmptyActionDescToContext(ctx)
+		return setCurrentPageToContext(ctx, actions)
+	}
+	lines := getStateLinesFromContext(e.ctx)
+	rows := make([]choice, len(lines))
+	for i, line := range lines {
+		rows[i] = choice{description: line, action: backToActions}
+	}
+	return page{title: "State of ", rows: rows}
 }
 
 func (e *Emulator) HandleUiEvent(event termui.Event) {
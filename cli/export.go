@@ -0,0 +1,22 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+
+	"github.com/damien-springuel/bomb-canary/cli/bcclient"
+)
+
+func export(args []string) {
+	flags := flag.NewFlagSet("export", flag.ExitOnError)
+	session := flags.String("session", "", "session token of a player of the game")
+	format := flags.String("format", "json", "transcript format, json or md")
+	flags.Parse(args)
+
+	if *session == "" {
+		log.Fatalf("a session token is required, use -session\n")
+	}
+
+	fmt.Print(bcclient.ExportTranscript(*session, *format))
+}
@@ -11,3 +11,11 @@ test("App mounted", ()=> {
   expect(dispatcher.receivedMessage).to.be.instanceof(AppLoaded);
   expect(dispatcher.receivedMessage).to.deep.equal(new AppLoaded());
 });
+
+test("Is watching replay", ()=> {
+  const watching = new AppService(null, {pageToShow: Page.Game, replayProgress: {position: 1, nbEvents: 10, playing: false}});
+  const playing = new AppService(null, {pageToShow: Page.Game, replayProgress: null});
+
+  expect(watching.isWatchingReplay).to.be.true;
+  expect(playing.isWatchingReplay).to.be.false;
+});
@@ -1,9 +1,10 @@
 import type { Dispatcher } from "./messages/dispatcher";
 import { AppLoaded } from "./messages/events";
-import { Page } from "./types/types";
+import { Page, type ReplayProgress } from "./types/types";
 
 export interface AppValues {
   readonly pageToShow: Page,
+  readonly replayProgress: ReplayProgress | null,
 }
 
 export class AppService {
@@ -27,4 +28,8 @@ export class AppService {
   get isPageGame(): boolean {
     return this.isPage(Page.Game);
   }
-}
\ No newline at end of file
+
+  get isWatchingReplay(): boolean {
+    return !!this.values.replayProgress;
+  }
+}
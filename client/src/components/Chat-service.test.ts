@@ -0,0 +1,60 @@
+import {expect, test} from "vitest";
+import { SendChatMessage } from "../messages/commands";
+import { DispatcherMock } from "../messages/dispatcher.test-utils";
+import { ChatChannel } from "../types/types";
+import { ChatService, type ChatValues } from "./Chat-service";
+
+test("Messages and error", ()=> {
+  const sentAt = new Date("2021-03-04T05:06:07Z");
+  const service = new ChatService({
+    chatMessages: [{player: "a", channel: ChatChannel.Public, message: "it's Bob", sentAt: sentAt}],
+    chatError: "too many messages",
+    spyChannelOpen: false,
+  }, null);
+
+  expect(service.messages).to.deep.equal([{player: "a", channel: ChatChannel.Public, message: "it's Bob", sentAt: sentAt}]);
+  expect(service.error).to.equal("too many messages");
+});
+
+test("Send", ()=> {
+  const dispatcher = new DispatcherMock();
+  const service = new ChatService({} as ChatValues, dispatcher);
+
+  service.send("  it's Bob ");
+  expect(dispatcher.receivedMessage).to.deep.equal(new SendChatMessage("it's Bob"));
+});
+
+test("Send ignores blank messages", ()=> {
+  const dispatcher = new DispatcherMock();
+  const service = new ChatService({} as ChatValues, dispatcher);
+
+  expect(service.canSend("   ")).to.be.false;
+  service.send("   ");
+  expect(dispatcher.receivedMessage).to.be.undefined;
+});
+
+test("Send to spies", ()=> {
+  const dispatcher = new DispatcherMock();
+  const service = new ChatService({spyChannelOpen: true} as ChatValues, dispatcher);
+
+  expect(service.canUseSpyChannel).to.be.true;
+  service.sendToSpies(" fail it ");
+  expect(dispatcher.receivedMessage).to.deep.equal(new SendChatMessage("fail it", ChatChannel.Spies));
+});
+
+test("Send to spies ignored when spy channel isn't open", ()=> {
+  const dispatcher = new DispatcherMock();
+  const service = new ChatService({spyChannelOpen: false} as ChatValues, dispatcher);
+
+  expect(service.canUseSpyChannel).to.be.false;
+  service.sendToSpies("fail it");
+  expect(dispatcher.receivedMessage).to.be.undefined;
+});
+
+test("Is spy message", ()=> {
+  const service = new ChatService({} as ChatValues, null);
+  const sentAt = new Date("2021-03-04T05:06:07Z");
+
+  expect(service.isSpyMessage({player: "a", channel: ChatChannel.Spies, message: "fail it", sentAt: sentAt})).to.be.true;
+  expect(service.isSpyMessage({player: "a", channel: ChatChannel.Public, message: "hi", sentAt: sentAt})).to.be.false;
+});
@@ -0,0 +1,52 @@
+import { SendChatMessage } from "../messages/commands";
+import type { Dispatcher } from "../messages/dispatcher";
+import { ChatChannel, type ChatMessage } from "../types/types";
+
+export interface ChatValues {
+  readonly chatMessages: ChatMessage[],
+  readonly chatError: string,
+  readonly spyChannelOpen: boolean,
+}
+
+export class ChatService {
+  constructor(
+    private readonly values: ChatValues,
+    private readonly dispatcher: Dispatcher
+  ) {}
+
+  get messages(): ChatMessage[] {
+    return this.values.chatMessages;
+  }
+
+  get error(): string {
+    return this.values.chatError;
+  }
+
+  get canUseSpyChannel(): boolean {
+    return this.values.spyChannelOpen;
+  }
+
+  canSend(message: string): boolean {
+    return !!message && message.trim().length > 0;
+  }
+
+  send(message: string) {
+    if (this.canSend(message)) {
+      this.dispatcher.dispatch(new SendChatMessage(message.trim()));
+    }
+  }
+
+  sendToSpies(message: string) {
+    if (this.canUseSpyChannel && this.canSend(message)) {
+      this.dispatcher.dispatch(new SendChatMessage(message.trim(), ChatChannel.Spies));
+    }
+  }
+
+  isSpyMessage(chatMessage: ChatMessage): boolean {
+    return chatMessage.channel === ChatChannel.Spies;
+  }
+
+  timeOf(chatMessage: ChatMessage): string {
+    return chatMessage.sentAt.toLocaleTimeString([], {hour: "2-digit", minute: "2-digit"});
+  }
+}
\ No newline at end of file
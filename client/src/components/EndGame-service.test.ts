@@ -1,6 +1,6 @@
 import {expect, test} from "vitest";
 import { EndGameService, type EndGameValues } from "./EndGame-service";
-import { Allegiance } from "../types/types";
+import { Allegiance, type GameSummary } from "../types/types";
 
 test("Spies as String", () => {
   const service = new EndGameService({
@@ -45,4 +45,41 @@ test("Player has won", () => {
     player: "d"
   } as EndGameValues);
   expect(service.playerHasWon).to.be.true;
-});
\ No newline at end of file
+});
+
+function summary(): GameSummary {
+  return {
+    winner: Allegiance.Spies,
+    allegiances: new Map<string, Allegiance>([["b", Allegiance.Resistance], ["a", Allegiance.Spies], ["c", Allegiance.Resistance]]),
+    teamProposals: [
+      {mission: 1, leader: "a", team: ["a", "b"], playerVotes: new Map<string, boolean>([["c", true], ["a", true], ["b", false]]), approved: true},
+    ],
+    missions: [
+      {mission: 1, team: ["a", "b"], success: false, nbFails: 1, failedBy: ["a"]},
+      {mission: 2, team: ["b", "c"], success: true, nbFails: 0, failedBy: []},
+    ],
+  };
+}
+
+test("Summary", () => {
+  let service = new EndGameService({gameSummary: null} as EndGameValues);
+  expect(service.hasSummary).to.be.false;
+
+  service = new EndGameService({gameSummary: summary()} as EndGameValues);
+  expect(service.hasSummary).to.be.true;
+  expect(service.players).to.deep.equal(["a", "b", "c"]);
+  expect(service.isSpy("a")).to.be.true;
+  expect(service.isSpy("b")).to.be.false;
+  expect(service.teamProposals).to.deep.equal(summary().teamProposals);
+  expect(service.missions).to.deep.equal(summary().missions);
+});
+
+test("Summary voters and fail cards", () => {
+  const service = new EndGameService({gameSummary: summary()} as EndGameValues);
+  const proposal = summary().teamProposals[0];
+  expect(service.votersAsString(proposal, true)).to.equal("a, c");
+  expect(service.votersAsString(proposal, false)).to.equal("b");
+  expect(service.teamAsString(["a", "b"])).to.equal("a, b");
+  expect(service.failCardsRevealed(summary().missions[0])).to.be.true;
+  expect(service.failCardsRevealed(summary().missions[1])).to.be.false;
+});
@@ -1,9 +1,10 @@
-import { Allegiance } from "../types/types";
+import { Allegiance, type GameSummary, type MissionSummary, type TeamProposal } from "../types/types";
 
 export interface EndGameValues {
   readonly player: string,
   readonly winner: Allegiance,
   readonly spies: Set<string>,
+  readonly gameSummary: GameSummary | null,
 }
 
 export class EndGameService {
@@ -21,6 +22,42 @@ export class EndGameService {
     return this.playerAllegiance === this.endGameValues.winner;
   }
 
+  get hasSummary(): boolean {
+    return !!this.endGameValues.gameSummary;
+  }
+
+  get players(): string[] {
+    return Array.from(this.endGameValues.gameSummary.allegiances.keys()).sort();
+  }
+
+  isSpy(player: string): boolean {
+    return this.endGameValues.gameSummary.allegiances.get(player) === Allegiance.Spies;
+  }
+
+  get teamProposals(): TeamProposal[] {
+    return this.endGameValues.gameSummary.teamProposals;
+  }
+
+  get missions(): MissionSummary[] {
+    return this.endGameValues.gameSummary.missions;
+  }
+
+  teamAsString(team: string[]): string {
+    return team.join(", ");
+  }
+
+  votersAsString(proposal: TeamProposal, approved: boolean): string {
+    return Array.from(proposal.playerVotes.entries())
+      .filter(([_, vote]) => vote === approved)
+      .map(([player]) => player)
+      .sort()
+      .join(", ");
+  }
+
+  failCardsRevealed(mission: MissionSummary): boolean {
+    return mission.failedBy.length > 0;
+  }
+
   private get playerAllegiance(): Allegiance {
     return this.endGameValues.spies.has(this.endGameValues.player) ? 
       Allegiance.Spies : 
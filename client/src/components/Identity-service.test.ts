@@ -3,19 +3,19 @@ import { IdentityService } from "./Identity-service";
 
 
 test(`Identity Service - isPlayerIsASpy`, () => {
-  let service = new IdentityService({revealedSpies: new Set<string>(["a", "b"]), player: "b"});
+  let service = new IdentityService({revealedSpies: new Set<string>(["a", "b"]), player: "b", rejoinCode: ""});
   service.isPlayerIsASpy();
   expect(service.isPlayerIsASpy()).to.be.true;
   
-  service = new IdentityService({revealedSpies: new Set<string>(), player: "b"});
+  service = new IdentityService({revealedSpies: new Set<string>(), player: "b", rejoinCode: ""});
   expect(service.isPlayerIsASpy()).to.be.false;
 });
 
 test(`Identity Service - otherSpies`, () => {
-  let service = new IdentityService({revealedSpies: new Set<string>(["a", "b", "c"]), player: "b"});
+  let service = new IdentityService({revealedSpies: new Set<string>(["a", "b", "c"]), player: "b", rejoinCode: ""});
   service.isPlayerIsASpy();
   expect(service.otherSpies()).to.equal("a, c");
   
-  service = new IdentityService({revealedSpies: new Set<string>(), player: "b"});
+  service = new IdentityService({revealedSpies: new Set<string>(), player: "b", rejoinCode: ""});
   expect(service.otherSpies()).to.equal("");
 });
@@ -1,6 +1,7 @@
 export interface IdentityValues {
   readonly player:string,
   readonly revealedSpies: Set<string>,
+  readonly rejoinCode: string,
 }
 
 export class IdentityService{
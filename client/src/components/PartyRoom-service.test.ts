@@ -1,5 +1,5 @@
 import {expect, test} from "vitest";
-import { JoinParty, StartGame } from "../messages/commands";
+import { ForceStartGame, JoinParty, MarkNotReady, MarkReady, RejoinParty, StartGame } from "../messages/commands";
 import { DispatcherMock } from "../messages/dispatcher.test-utils";
 import { PartyRoomService, type PartyRoomValues } from "./PartyRoom-service";
 
@@ -19,6 +19,14 @@ test("Has Player joined", ()=> {
   expect(service.hasPlayerJoined).to.be.true;
 });
 
+test("Join error", ()=> {
+  const service = new PartyRoomService({
+    joinError: "player already in group"
+  } as PartyRoomValues, null);
+
+  expect(service.joinError).to.equal("player already in group");
+});
+
 test("Start Game", ()=> {
   const dispatcher = new DispatcherMock();
   const service = new PartyRoomService({} as PartyRoomValues, dispatcher);
@@ -35,17 +43,91 @@ test("Join Game", ()=> {
   expect(dispatcher.receivedMessage).to.deep.equal(new JoinParty("name"));
 });
 
+test("Rejoin Game", ()=> {
+  const dispatcher = new DispatcherMock();
+  const service = new PartyRoomService({} as PartyRoomValues, dispatcher);
+
+  service.rejoinParty("CODE01");
+  expect(dispatcher.receivedMessage).to.deep.equal(new RejoinParty("CODE01"));
+});
+
 test("Can start game", ()=> {
   const dispatcher = new DispatcherMock();
   let service = new PartyRoomService({
-    players: ["1", "2", "3", "4"]
+    players: ["1", "2", "3", "4"],
+    readyPlayers: new Set<string>(["1", "2", "3", "4"]),
+  } as PartyRoomValues, dispatcher);
+
+  expect(service.canStartGame).to.be.false;
+  
+  service = new PartyRoomService({
+    players: ["1", "2", "3", "4", "5"],
+    readyPlayers: new Set<string>(["1", "2", "3", "4"]),
   } as PartyRoomValues, dispatcher);
 
   expect(service.canStartGame).to.be.false;
   
   service = new PartyRoomService({
-    players: ["1", "2", "3", "4", "5"]
+    players: ["1", "2", "3", "4", "5"],
+    readyPlayers: new Set<string>(["1", "2", "3", "4", "5"]),
   } as PartyRoomValues, dispatcher);
 
   expect(service.canStartGame).to.be.true;
+});
+
+test("Can force start game", ()=> {
+  let service = new PartyRoomService({
+    players: ["1", "2", "3", "4", "5"],
+    player: "1",
+    readyPlayers: new Set<string>(["1"]),
+  } as PartyRoomValues, null);
+
+  expect(service.isPlayerHost).to.be.true;
+  expect(service.canForceStartGame).to.be.true;
+  
+  service = new PartyRoomService({
+    players: ["1", "2", "3", "4", "5"],
+    player: "2",
+    readyPlayers: new Set<string>(["1"]),
+  } as PartyRoomValues, null);
+
+  expect(service.isPlayerHost).to.be.false;
+  expect(service.canForceStartGame).to.be.false;
+  
+  service = new PartyRoomService({
+    players: ["1", "2", "3", "4"],
+    player: "1",
+    readyPlayers: new Set<string>(),
+  } as PartyRoomValues, null);
+
+  expect(service.canForceStartGame).to.be.false;
+});
+
+test("Force Start Game", ()=> {
+  const dispatcher = new DispatcherMock();
+  const service = new PartyRoomService({} as PartyRoomValues, dispatcher);
+
+  service.forceStartGame();
+  expect(dispatcher.receivedMessage).to.deep.equal(new ForceStartGame());
+});
+
+test("Toggle ready", ()=> {
+  const dispatcher = new DispatcherMock();
+  let service = new PartyRoomService({
+    player: "a",
+    readyPlayers: new Set<string>(),
+  } as PartyRoomValues, dispatcher);
+
+  expect(service.isPlayerReady).to.be.false;
+  service.toggleReady();
+  expect(dispatcher.receivedMessage).to.deep.equal(new MarkReady());
+
+  service = new PartyRoomService({
+    player: "a",
+    readyPlayers: new Set<string>(["a"]),
+  } as PartyRoomValues, dispatcher);
+
+  expect(service.isPlayerReady).to.be.true;
+  service.toggleReady();
+  expect(dispatcher.receivedMessage).to.deep.equal(new MarkNotReady());
 });
\ No newline at end of file
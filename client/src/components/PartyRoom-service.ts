@@ -1,9 +1,12 @@
-import { JoinParty, StartGame } from "../messages/commands";
+import { ForceStartGame, JoinParty, MarkNotReady, MarkReady, RejoinParty, StartGame } from "../messages/commands";
 import type { Dispatcher } from "../messages/dispatcher";
 
 export interface PartyRoomValues{
   readonly players: string[],
   readonly hasPlayerJoined: boolean,
+  readonly player: string,
+  readonly readyPlayers: Set<string>,
+  readonly joinError: string,
 }
 
 export class PartyRoomService {
@@ -20,15 +23,52 @@ export class PartyRoomService {
     return this.values.hasPlayerJoined;
   }
 
+  get joinError(): string {
+    return this.values.joinError;
+  }
+
   joinParty(name: string) {
     this.dispatcher.dispatch(new JoinParty(name));
   }
 
+  rejoinParty(code: string) {
+    this.dispatcher.dispatch(new RejoinParty(code));
+  }
+
+  isReady(player: string): boolean {
+    return this.values.readyPlayers.has(player);
+  }
+
+  get isPlayerReady(): boolean {
+    return this.isReady(this.values.player);
+  }
+
+  get isPlayerHost(): boolean {
+    return this.values.players[0] === this.values.player;
+  }
+
+  toggleReady() {
+    if (this.isPlayerReady) {
+      this.dispatcher.dispatch(new MarkNotReady());
+    }
+    else {
+      this.dispatcher.dispatch(new MarkReady());
+    }
+  }
+
   startGame() {
     this.dispatcher.dispatch(new StartGame());
   }
 
+  forceStartGame() {
+    this.dispatcher.dispatch(new ForceStartGame());
+  }
+
   get canStartGame(): boolean {
-    return this.values.players.length >= 5;
+    return this.values.players.length >= 5 && this.values.players.every(p => this.isReady(p));
+  }
+
+  get canForceStartGame(): boolean {
+    return this.isPlayerHost && this.values.players.length >= 5 && !this.canStartGame;
   }
 }
\ No newline at end of file
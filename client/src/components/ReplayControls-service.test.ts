@@ -0,0 +1,56 @@
+import {expect, test} from "vitest";
+import { PauseReplay, PlayReplay, StepReplayBack, StepReplayForward } from "../messages/commands";
+import { DispatcherMock } from "../messages/dispatcher.test-utils";
+import { ReplayControlsService } from "./ReplayControls-service";
+
+test("Progress", ()=> {
+  const service = new ReplayControlsService({replayProgress: {position: 3, nbEvents: 10, playing: true}}, null);
+
+  expect(service.progress).to.equal("3 / 10");
+  expect(service.isPlaying).to.be.true;
+  expect(service.canStepBack).to.be.true;
+  expect(service.canStepForward).to.be.true;
+});
+
+test("Can't step outside of the events", ()=> {
+  const atStart = new ReplayControlsService({replayProgress: {position: 0, nbEvents: 10, playing: false}}, null);
+  const atEnd = new ReplayControlsService({replayProgress: {position: 10, nbEvents: 10, playing: false}}, null);
+
+  expect(atStart.canStepBack).to.be.false;
+  expect(atEnd.canStepForward).to.be.false;
+});
+
+test("Step back", ()=> {
+  const dispatcher = new DispatcherMock();
+  const service = new ReplayControlsService({replayProgress: {position: 3, nbEvents: 10, playing: false}}, dispatcher);
+
+  service.stepBack();
+  expect(dispatcher.receivedMessage).to.deep.equal(new StepReplayBack());
+});
+
+test("Step back ignored at the start", ()=> {
+  const dispatcher = new DispatcherMock();
+  const service = new ReplayControlsService({replayProgress: {position: 0, nbEvents: 10, playing: false}}, dispatcher);
+
+  service.stepBack();
+  expect(dispatcher.receivedMessage).to.be.undefined;
+});
+
+test("Step forward", ()=> {
+  const dispatcher = new DispatcherMock();
+  const service = new ReplayControlsService({replayProgress: {position: 3, nbEvents: 10, playing: false}}, dispatcher);
+
+  service.stepForward();
+  expect(dispatcher.receivedMessage).to.deep.equal(new StepReplayForward());
+});
+
+test("Toggle play", ()=> {
+  const dispatcher = new DispatcherMock();
+  const paused = new ReplayControlsService({replayProgress: {position: 3, nbEvents: 10, playing: false}}, dispatcher);
+  paused.togglePlay();
+  expect(dispatcher.receivedMessage).to.deep.equal(new PlayReplay());
+
+  const playing = new ReplayControlsService({replayProgress: {position: 3, nbEvents: 10, playing: true}}, dispatcher);
+  playing.togglePlay();
+  expect(dispatcher.receivedMessage).to.deep.equal(new PauseReplay());
+});
@@ -0,0 +1,51 @@
+import { PauseReplay, PlayReplay, StepReplayBack, StepReplayForward } from "../messages/commands";
+import type { Dispatcher } from "../messages/dispatcher";
+import type { ReplayProgress } from "../types/types";
+
+export interface ReplayControlsValues {
+  readonly replayProgress: ReplayProgress,
+}
+
+export class ReplayControlsService {
+  constructor(
+    private readonly values: ReplayControlsValues,
+    private readonly dispatcher: Dispatcher
+  ) {}
+
+  get progress(): string {
+    return `${this.values.replayProgress.position} / ${this.values.replayProgress.nbEvents}`;
+  }
+
+  get isPlaying(): boolean {
+    return this.values.replayProgress.playing;
+  }
+
+  get canStepBack(): boolean {
+    return this.values.replayProgress.position > 0;
+  }
+
+  get canStepForward(): boolean {
+    return this.values.replayProgress.position < this.values.replayProgress.nbEvents;
+  }
+
+  stepBack() {
+    if (this.canStepBack) {
+      this.dispatcher.dispatch(new StepReplayBack());
+    }
+  }
+
+  stepForward() {
+    if (this.canStepForward) {
+      this.dispatcher.dispatch(new StepReplayForward());
+    }
+  }
+
+  togglePlay() {
+    if (this.isPlaying) {
+      this.dispatcher.dispatch(new PauseReplay());
+    }
+    else if (this.canStepForward) {
+      this.dispatcher.dispatch(new PlayReplay());
+    }
+  }
+}
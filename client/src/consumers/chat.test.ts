@@ -0,0 +1,34 @@
+import { expect, test } from "vitest";
+import { ChatMessageRejected, ChatMessageSent, SpyChannelOpened, SpyChatRevealed } from "../messages/events";
+import { ChatChannel, type ChatMessage } from "../types/types";
+import { ChatConsumer, type ChatStore } from "./chat";
+
+test(`Chat Consumer - chat message sent`, () => {
+  let addedMessage: ChatMessage;
+  const chatConsumer = new ChatConsumer({addChatMessage: m => {addedMessage = m}} as ChatStore);
+  const sentAt = new Date("2021-03-04T05:06:07Z");
+  chatConsumer.consume(new ChatMessageSent({player: "a", channel: ChatChannel.Public, message: "it's Bob", sentAt: sentAt}));
+  expect(addedMessage).to.deep.equal({player: "a", channel: ChatChannel.Public, message: "it's Bob", sentAt: sentAt});
+});
+
+test(`Chat Consumer - chat message rejected`, () => {
+  let shownError: string;
+  const chatConsumer = new ChatConsumer({showChatError: r => {shownError = r}} as ChatStore);
+  chatConsumer.consume(new ChatMessageRejected("too many messages"));
+  expect(shownError).to.equal("too many messages");
+});
+
+test(`Chat Consumer - spy channel opened`, () => {
+  let opened = false;
+  const chatConsumer = new ChatConsumer({openSpyChannel: () => {opened = true}} as ChatStore);
+  chatConsumer.consume(new SpyChannelOpened());
+  expect(opened).to.be.true;
+});
+
+test(`Chat Consumer - spy chat revealed`, () => {
+  let revealedMessages: ChatMessage[];
+  const chatConsumer = new ChatConsumer({revealSpyChat: m => {revealedMessages = m}} as ChatStore);
+  const revealed = [{player: "a", channel: ChatChannel.Spies, message: "fail it", sentAt: new Date("2021-03-04T05:06:07Z")}];
+  chatConsumer.consume(new SpyChatRevealed(revealed));
+  expect(revealedMessages).to.deep.equal(revealed);
+});
@@ -0,0 +1,30 @@
+import { ChatMessageRejected, ChatMessageSent, SpyChannelOpened, SpyChatRevealed } from "../messages/events";
+import type { Message } from "../messages/message-bus";
+import type { ChatMessage } from "../types/types";
+
+export interface ChatStore {
+  addChatMessage(chatMessage: ChatMessage): void
+  showChatError(reason: string): void
+  openSpyChannel(): void
+  revealSpyChat(chatMessages: ChatMessage[]): void
+}
+
+export class ChatConsumer {
+
+  constructor(private readonly chatStore: ChatStore){}
+
+  consume(message: Message) {
+    if (message instanceof ChatMessageSent) {
+      this.chatStore.addChatMessage(message.chatMessage);
+    }
+    else if (message instanceof ChatMessageRejected) {
+      this.chatStore.showChatError(message.reason);
+    }
+    else if (message instanceof SpyChannelOpened) {
+      this.chatStore.openSpyChannel();
+    }
+    else if (message instanceof SpyChatRevealed) {
+      this.chatStore.revealSpyChat(message.chatMessages);
+    }
+  }
+}
\ No newline at end of file
@@ -3,6 +3,7 @@ import {
     AllPlayerVotedOnTeam, 
     GameEnded, 
     GameStarted, 
+    GameSummarized, 
     LeaderConfirmedTeam, 
     LeaderDeselectedMember, 
     LeaderSelectedMember, 
@@ -10,9 +11,10 @@ import {
     MissionCompleted, 
     MissionStarted, 
     PlayerVotedOnTeam, 
-    PlayerWorkedOnMission 
+    PlayerWorkedOnMission, 
+    RematchStarted, 
 } from "../messages/events";
-import { Allegiance, type MissionRequirement } from "../types/types";
+import { Allegiance, type GameSummary, type MissionRequirement } from "../types/types";
 import { GameConsumer, type GameStore } from "./game";
 
 test(`GameStarted`, () => {
@@ -124,3 +126,26 @@ test(`GameEnded`, () => {
   expect(receivedSpies).to.deep.equal(new Set<string>(["a", "b"]));
 });
 
+test(`GameSummarized`, () => {
+  let receivedSummary: GameSummary = null;
+  const gameConsumer = new GameConsumer({saveGameSummary: summary =>{
+    receivedSummary = summary;
+  }} as GameStore);
+  const summary: GameSummary = {
+    winner: Allegiance.Spies,
+    allegiances: new Map<string, Allegiance>([["a", Allegiance.Spies], ["b", Allegiance.Resistance]]),
+    teamProposals: [],
+    missions: [],
+  };
+  gameConsumer.consume(new GameSummarized(summary));
+  expect(receivedSummary).to.equal(summary);
+});
+
+test(`RematchStarted`, () => {
+  let receivedPlayers: string[] = null;
+  const gameConsumer = new GameConsumer({startRematch: players =>{
+    receivedPlayers = players;
+  }} as GameStore);
+  gameConsumer.consume(new RematchStarted(["b", "a"]));
+  expect(receivedPlayers).to.deep.equal(["b", "a"]);
+});
@@ -2,6 +2,7 @@ import {
   AllPlayerVotedOnTeam, 
   GameEnded, 
   GameStarted, 
+  GameSummarized, 
   LeaderConfirmedTeam, 
   LeaderDeselectedMember, 
   LeaderSelectedMember, 
@@ -10,9 +11,10 @@ import {
   MissionStarted, 
   PlayerVotedOnTeam, 
   PlayerWorkedOnMission, 
+  RematchStarted, 
 } from "../messages/events";
 import type { Message } from "../messages/message-bus";
-import type { Allegiance, MissionRequirement } from "../types/types";
+import type { Allegiance, GameSummary, MissionRequirement } from "../types/types";
 
 export interface GameStore {
   setMissionRequirements(requirements: MissionRequirement[]): void
@@ -28,6 +30,8 @@ export interface GameStore {
   saveMissionResult(success: boolean, nbFails: number): void
   showLastMissionResult(): void
   endGame(winner: Allegiance, spies: Set<string>): void
+  saveGameSummary(summary: GameSummary): void
+  startRematch(players: string[]): void
 }
 
 export class GameConsumer {
@@ -70,5 +74,11 @@ export class GameConsumer {
     else if(message instanceof GameEnded) {
       this.gameStore.endGame(message.winner, message.spies);
     }
+    else if(message instanceof RematchStarted) {
+      this.gameStore.startRematch(message.players);
+    }
+    else if(message instanceof GameSummarized) {
+      this.gameStore.saveGameSummary(message.summary);
+    }
   }
 }
\ No newline at end of file
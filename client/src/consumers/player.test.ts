@@ -1,5 +1,5 @@
 import { expect, test } from "vitest";
-import { EventsReplayStarted, PlayerJoined, SpiesRevealed } from "../messages/events";
+import { EventsReplayStarted, JoinPartyFailed, PlayerJoined, PlayerNotReady, PlayerReady, RejoinCodeIssued, SpiesRevealed } from "../messages/events";
 import { PlayerConsumer, type PlayerStore } from "./player";
 
 test(`PlayerManager - playerConsumer joined`, () => {
@@ -9,6 +9,20 @@ test(`PlayerManager - playerConsumer joined`, () => {
   expect(playerJoined).to.equal("testName");
 });
 
+test(`PlayerManager - player ready`, () => {
+  let readyPlayer: string
+  const playerConsumer = new PlayerConsumer({markPlayerReady: p => {readyPlayer = p}} as PlayerStore);
+  playerConsumer.consume(new PlayerReady("testName"));
+  expect(readyPlayer).to.equal("testName");
+});
+
+test(`PlayerManager - player not ready`, () => {
+  let notReadyPlayer: string
+  const playerConsumer = new PlayerConsumer({markPlayerNotReady: p => {notReadyPlayer = p}} as PlayerStore);
+  playerConsumer.consume(new PlayerNotReady("testName"));
+  expect(notReadyPlayer).to.equal("testName");
+});
+
 test(`PlayerManager - events replay started`, () => {
   let definedPlayer: string
   const playerConsumer = new PlayerConsumer({definePlayer: p => {definedPlayer = p}} as PlayerStore);
@@ -22,3 +36,17 @@ test(`PlayerManager - spies revealed`, () => {
   playerConsumer.consume(new SpiesRevealed(new Set<string>(["a", "b"])));
   expect(rememberedSpies).to.deep.equal(new Set<string>(["a", "b"]));
 });
+
+test(`PlayerManager - rejoin code issued`, () => {
+  let rememberedCode: string;
+  const playerConsumer = new PlayerConsumer({rememberRejoinCode: c => {rememberedCode = c}} as PlayerStore);
+  playerConsumer.consume(new RejoinCodeIssued("ABCDE"));
+  expect(rememberedCode).to.equal("ABCDE");
+});
+
+test(`PlayerManager - join party failed`, () => {
+  let shownError: string;
+  const playerConsumer = new PlayerConsumer({showJoinError: r => {shownError = r}} as PlayerStore);
+  playerConsumer.consume(new JoinPartyFailed("player already in group"));
+  expect(shownError).to.equal("player already in group");
+});
@@ -1,10 +1,14 @@
-import { EventsReplayStarted, PlayerJoined, SpiesRevealed } from "../messages/events";
+import { EventsReplayStarted, JoinPartyFailed, PlayerJoined, PlayerNotReady, PlayerReady, RejoinCodeIssued, SpiesRevealed } from "../messages/events";
 import type { Message } from "../messages/message-bus";
 
 export interface PlayerStore {
   definePlayer(name: string): void
   joinPlayer(name: string): void
+  markPlayerReady(name: string): void
+  markPlayerNotReady(name: string): void
   rememberSpies(spies: Set<string>): void
+  rememberRejoinCode(code: string): void
+  showJoinError(reason: string): void
 }
 
 export class PlayerConsumer {
@@ -15,11 +19,23 @@ export class PlayerConsumer {
     if (message instanceof PlayerJoined) {
       this.playerStore.joinPlayer(message.name);
     }
+    else if (message instanceof PlayerReady) {
+      this.playerStore.markPlayerReady(message.name);
+    }
+    else if (message instanceof PlayerNotReady) {
+      this.playerStore.markPlayerNotReady(message.name);
+    }
     else if (message instanceof EventsReplayStarted) {
       this.playerStore.definePlayer(message.playerName);
     }
     else if (message instanceof SpiesRevealed) {
       this.playerStore.rememberSpies(message.spies);
     }
+    else if (message instanceof RejoinCodeIssued) {
+      this.playerStore.rememberRejoinCode(message.code);
+    }
+    else if (message instanceof JoinPartyFailed) {
+      this.playerStore.showJoinError(message.reason);
+    }
   }
 }
\ No newline at end of file
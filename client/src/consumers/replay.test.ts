@@ -3,7 +3,8 @@ import {
   EventsReplayEnded, 
   EventsReplayStarted, 
   ServerConnectionClosed, 
-  ServerConnectionErrorOccured 
+  ServerConnectionErrorOccured, 
+  ServerConnectionLost 
 } from "../messages/events";
 import { ReplayConsumer, type ReplayStore } from "./replay";
 
@@ -21,6 +22,13 @@ test(`ReplayManager - EventsReplayEnded`, () => {
   expect(endReplayCalled).to.be.true;
 });
 
+test(`ReplayManager - ServerConnectionLost`, () => {
+  let endReplayCalled = false;
+  const replayConsumer = new ReplayConsumer({endReplay: () => {endReplayCalled = true;}} as ReplayStore);
+  replayConsumer.consume(new ServerConnectionLost());
+  expect(endReplayCalled).to.be.true;
+});
+
 test(`ReplayManager - ServerConnectionClosed`, () => {
   let endReplayCalled = false;
   const replayConsumer = new ReplayConsumer({endReplay: () => {endReplayCalled = true;}} as ReplayStore);
@@ -2,7 +2,8 @@ import {
   EventsReplayEnded, 
   EventsReplayStarted, 
   ServerConnectionClosed, 
-  ServerConnectionErrorOccured 
+  ServerConnectionErrorOccured, 
+  ServerConnectionLost 
 } from "../messages/events";
 import type { Message } from "../messages/message-bus";
 
@@ -17,6 +18,7 @@ export class ReplayConsumer {
   consume(message: Message) {
     if (message instanceof EventsReplayEnded ||
       message instanceof ServerConnectionClosed ||
+      message instanceof ServerConnectionLost ||
       message instanceof ServerConnectionErrorOccured) {
       this.replayStore.endReplay();
     } 
@@ -1,5 +1,5 @@
 import { expect, test } from "vitest";
-import { ServerConnectionClosed } from "../messages/events";
+import { EventsReplayStarted, ServerConnectionClosed, ServerConnectionLost } from "../messages/events";
 import { ResetConsumer } from "./reset";
 
 test(`Reset manager - ServerConnectionClosed `, () => {
@@ -8,3 +8,24 @@ test(`Reset manager - ServerConnectionClosed `, () => {
   resetConsumer.consume(new ServerConnectionClosed());
   expect(reset).to.be.true;
 });
+
+test(`Reset manager - ServerConnectionLost keeps the state`, () => {
+  let reset = false;
+  const resetConsumer = new ResetConsumer({reset: () => {reset = true;}});
+  resetConsumer.consume(new ServerConnectionLost());
+  expect(reset).to.be.false;
+});
+
+test(`Reset manager - EventsReplayStarted from the beginning`, () => {
+  let reset = false;
+  const resetConsumer = new ResetConsumer({reset: () => {reset = true;}});
+  resetConsumer.consume(new EventsReplayStarted("testName", 0));
+  expect(reset).to.be.true;
+});
+
+test(`Reset manager - EventsReplayStarted since a sequence`, () => {
+  let reset = false;
+  const resetConsumer = new ResetConsumer({reset: () => {reset = true;}});
+  resetConsumer.consume(new EventsReplayStarted("testName", 5));
+  expect(reset).to.be.false;
+});
@@ -1,4 +1,4 @@
-import { ServerConnectionClosed } from "../messages/events";
+import { EventsReplayStarted, ServerConnectionClosed } from "../messages/events";
 import type { Message } from "../messages/message-bus";
 
 export class ResetConsumer {
@@ -6,7 +6,8 @@ export class ResetConsumer {
   constructor(private readonly resetter: {reset: () => void}){}
 
   consume(message: Message): void {
-    if (message instanceof ServerConnectionClosed) {
+    if (message instanceof ServerConnectionClosed ||
+      (message instanceof EventsReplayStarted && message.since === 0)) {
       this.resetter.reset()
     }
   }
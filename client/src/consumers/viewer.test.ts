@@ -0,0 +1,18 @@
+import { expect, test } from "vitest";
+import { ReplayProgressed, ReplayRewound } from "../messages/events";
+import type { ReplayProgress } from "../types/types";
+import { ViewerConsumer, type ViewerStore } from "./viewer";
+
+test(`ReplayRewound`, () => {
+  let rewindCalled = false;
+  const viewerConsumer = new ViewerConsumer({rewindReplay: () => {rewindCalled = true;}} as ViewerStore);
+  viewerConsumer.consume(new ReplayRewound());
+  expect(rewindCalled).to.be.true;
+});
+
+test(`ReplayProgressed`, () => {
+  let receivedProgress: ReplayProgress = null;
+  const viewerConsumer = new ViewerConsumer({updateReplayProgress: progress => {receivedProgress = progress;}} as ViewerStore);
+  viewerConsumer.consume(new ReplayProgressed({position: 3, nbEvents: 10, playing: true}));
+  expect(receivedProgress).to.deep.equal({position: 3, nbEvents: 10, playing: true});
+});
@@ -0,0 +1,21 @@
+import { ReplayProgressed, ReplayRewound } from "../messages/events";
+import type { Message } from "../messages/message-bus";
+import type { ReplayProgress } from "../types/types";
+
+export interface ViewerStore {
+  rewindReplay(): void
+  updateReplayProgress(progress: ReplayProgress): void
+}
+
+export class ViewerConsumer {
+  constructor(private readonly viewerStore: ViewerStore){}
+
+  consume(message: Message) {
+    if (message instanceof ReplayRewound) {
+      this.viewerStore.rewindReplay();
+    }
+    else if (message instanceof ReplayProgressed) {
+      this.viewerStore.updateReplayProgress(message.progress);
+    }
+  }
+}
@@ -12,6 +12,8 @@ import { PlayerConsumer } from './consumers/player';
 import { PlayerActions } from './player-actions/player-actions';
 import { ResetConsumer } from './consumers/reset';
 import { GameConsumer } from './consumers/game';
+import { ChatConsumer } from './consumers/chat';
+import { ViewerConsumer } from './consumers/viewer';
 
 const axiosInstance = Axios.create({baseURL: window.location.origin, withCredentials: true});
 
@@ -31,7 +33,7 @@ const playerActions = new PlayerActions(axiosInstance);
 messageBus.subscribeConsumer(playerActions);
 
 const handler = new Handler(messageBus);
-const creator = new Creator(() => new WebSocket(`${window.location.origin.replace("http", "ws")}/events`), handler);
+const creator = new Creator(since => new WebSocket(`${window.location.origin.replace("http", "ws")}/events?since=${since}`), handler);
 const opener = new Opener(creator);
 messageBus.subscribeConsumer(opener);
 
@@ -40,6 +42,8 @@ messageBus.subscribeConsumer(new PageConsumer(store));
 messageBus.subscribeConsumer(new ReplayConsumer(store));
 messageBus.subscribeConsumer(new PlayerConsumer(store));
 messageBus.subscribeConsumer(new GameConsumer(store));
+messageBus.subscribeConsumer(new ChatConsumer(store));
+messageBus.subscribeConsumer(new ViewerConsumer(store));
 
 const app = new App({
   target: document.body,
@@ -1,11 +1,22 @@
+import { ChatChannel } from "../types/types";
 import type { Message } from "./message-bus";
 
 export class JoinParty implements Message {
   constructor(readonly name: string){}
 }
 
+export class RejoinParty implements Message {
+  constructor(readonly code: string){}
+}
+
+export class MarkReady implements Message {}
+
+export class MarkNotReady implements Message {}
+
 export class StartGame implements Message {}
 
+export class ForceStartGame implements Message {}
+
 export class LeaderSelectsMember implements Message {
   constructor(readonly member: string){}
 }
@@ -24,6 +35,10 @@ export class SucceedMission implements Message {}
 
 export class FailMission implements Message {}
 
+export class SendChatMessage implements Message {
+  constructor(readonly message: string, readonly channel: ChatChannel = ChatChannel.Public){}
+}
+
 export class ViewIdentity implements Message {}
 
 export class ViewMissionDetails implements Message {
@@ -31,3 +46,11 @@ export class ViewMissionDetails implements Message {
 }
 
 export class CloseDialog implements Message {}
+
+export class StepReplayForward implements Message {}
+
+export class StepReplayBack implements Message {}
+
+export class PlayReplay implements Message {}
+
+export class PauseReplay implements Message {}
@@ -1,15 +1,20 @@
-import type { Allegiance, MissionRequirement } from "../types/types";
+import type { Allegiance, ChatMessage, GameSummary, MissionRequirement, ReplayProgress } from "../types/types";
 import type { Message } from "./message-bus";
 
 export class AppLoaded implements Message{}
 
 export class JoinPartySucceeded implements Message{}
 
+export class JoinPartyFailed implements Message{
+  constructor(readonly reason: string){}
+}
+
 export class ServerConnectionClosed implements Message {}
+export class ServerConnectionLost implements Message {}
 export class ServerConnectionErrorOccured implements Message {}
 
 export class EventsReplayStarted implements Message {
-  constructor(readonly playerName: string){}
+  constructor(readonly playerName: string, readonly since: number = 0){}
 }
 export class EventsReplayEnded implements Message {}
 
@@ -25,6 +30,14 @@ export class PlayerJoined implements Message {
   constructor(readonly name: string) {}
 }
 
+export class PlayerReady implements Message {
+  constructor(readonly name: string) {}
+}
+
+export class PlayerNotReady implements Message {
+  constructor(readonly name: string) {}
+}
+
 export class GameStarted implements Message {
   constructor(readonly requirements: MissionRequirement[]) {}
 }
@@ -33,6 +46,10 @@ export class SpiesRevealed implements Message {
   constructor(readonly spies: Set<string>) {}
 }
 
+export class RejoinCodeIssued implements Message {
+  constructor(readonly code: string) {}
+}
+
 export class LeaderStartedToSelectMembers implements Message {
   constructor(readonly leader: string) {}
 }
@@ -68,3 +85,31 @@ export class MissionCompleted implements Message {
 export class GameEnded implements Message {
   constructor(readonly winner: Allegiance, readonly spies: Set<string>){}
 }
+
+export class RematchStarted implements Message {
+  constructor(readonly players: string[]){}
+}
+
+export class GameSummarized implements Message {
+  constructor(readonly summary: GameSummary){}
+}
+
+export class ChatMessageSent implements Message {
+  constructor(readonly chatMessage: ChatMessage) {}
+}
+
+export class ChatMessageRejected implements Message {
+  constructor(readonly reason: string) {}
+}
+
+export class SpyChannelOpened implements Message {}
+
+export class SpyChatRevealed implements Message {
+  constructor(readonly chatMessages: ChatMessage[]) {}
+}
+
+export class ReplayRewound implements Message {}
+
+export class ReplayProgressed implements Message {
+  constructor(readonly progress: ReplayProgress) {}
+}
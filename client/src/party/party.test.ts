@@ -1,9 +1,9 @@
 import { expect, test } from "vitest";
-import type { AxiosResponse } from "axios";
+import type { AxiosError, AxiosResponse } from "axios";
 import { HttpPostMock } from "../http/post.test-utils";
-import { JoinParty } from "../messages/commands";
+import { JoinParty, RejoinParty } from "../messages/commands";
 import { AsyncDispatcherMock } from "../messages/dispatcher.test-utils";
-import { JoinPartySucceeded} from "../messages/events";
+import { JoinPartyFailed, JoinPartySucceeded } from "../messages/events";
 import { Party } from "./party";
 
 
@@ -20,3 +20,54 @@ test(`Join Party`, async () => {
   expect(http.givenData).to.deep.equal({name: "testName"});
   expect(dispatcher.receivedMessage).to.deep.equal( new JoinPartySucceeded());
 });
+
+
+test(`Rejoin Party with code`, async () => {
+  const http = new HttpPostMock(Promise.resolve({data:{}} as AxiosResponse<{}>));
+  const dispatcher = new AsyncDispatcherMock();
+  
+  const party = new Party(http, dispatcher);
+  party.consume(new RejoinParty("CODE01"));
+  
+  await dispatcher.isDone;
+  
+  expect(http.givenUrl).to.equal("/party/rejoin");
+  expect(http.givenData).to.deep.equal({code: "CODE01"});
+  expect(dispatcher.receivedMessage).to.deep.equal(new JoinPartySucceeded());
+});
+
+test(`Join Party refused`, async () => {
+  const http = new HttpPostMock(Promise.reject({response: {status: 409, data: {error: "player already in group"}}} as AxiosError));
+  const dispatcher = new AsyncDispatcherMock();
+  
+  const party = new Party(http, dispatcher);
+  party.consume(new JoinParty("testName"));
+  
+  await dispatcher.isDone;
+  
+  expect(dispatcher.receivedMessage).to.deep.equal(new JoinPartyFailed("player already in group"));
+});
+
+test(`Rejoin Party with an invalid code`, async () => {
+  const http = new HttpPostMock(Promise.reject({response: {status: 403, data: {error: "invalid rejoin code"}}} as AxiosError));
+  const dispatcher = new AsyncDispatcherMock();
+  
+  const party = new Party(http, dispatcher);
+  party.consume(new RejoinParty("NOPE00"));
+  
+  await dispatcher.isDone;
+  
+  expect(dispatcher.receivedMessage).to.deep.equal(new JoinPartyFailed("invalid rejoin code"));
+});
+
+test(`Join Party without a response`, async () => {
+  const http = new HttpPostMock(Promise.reject({} as AxiosError));
+  const dispatcher = new AsyncDispatcherMock();
+  
+  const party = new Party(http, dispatcher);
+  party.consume(new JoinParty("testName"));
+  
+  await dispatcher.isDone;
+  
+  expect(dispatcher.receivedMessage).to.deep.equal(new JoinPartyFailed("can't reach the server, try again"));
+});
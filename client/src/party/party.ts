@@ -1,7 +1,8 @@
+import type { AxiosError } from "axios";
 import type { HttpPost } from "../http/post";
-import { JoinParty } from "../messages/commands";
+import { JoinParty, RejoinParty } from "../messages/commands";
 import type { Dispatcher } from "../messages/dispatcher";
-import { JoinPartySucceeded } from "../messages/events";
+import { JoinPartyFailed, JoinPartySucceeded } from "../messages/events";
 import type { Message } from "../messages/message-bus";
 
 export class Party {
@@ -14,7 +15,18 @@ export class Party {
     if(message instanceof JoinParty) {
       this.http.post('/party/join', {name: message.name}).then(
         () => this.dispatcher.dispatch(new JoinPartySucceeded()),
+        (error: AxiosError<{error?: string}>) => this.dispatcher.dispatch(new JoinPartyFailed(failureReason(error))),
+      );
+    }
+    else if(message instanceof RejoinParty) {
+      this.http.post('/party/rejoin', {code: message.code}).then(
+        () => this.dispatcher.dispatch(new JoinPartySucceeded()),
+        (error: AxiosError<{error?: string}>) => this.dispatcher.dispatch(new JoinPartyFailed(failureReason(error))),
       );
     }
   }
-}
\ No newline at end of file
+}
+
+function failureReason(error: AxiosError<{error?: string}>): string {
+  return error.response?.data?.error || "can't reach the server, try again";
+}
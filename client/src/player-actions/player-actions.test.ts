@@ -3,13 +3,22 @@ import { HttpPostMock } from "../http/post.test-utils";
 import { 
   ApproveTeam, 
   FailMission, 
+  ForceStartGame, 
   LeaderConfirmsTeam, 
   LeaderDeselectsMember, 
   LeaderSelectsMember, 
+  MarkNotReady, 
+  MarkReady, 
+  PauseReplay, 
+  PlayReplay, 
   RejectTeam, 
+  SendChatMessage, 
   StartGame, 
+  StepReplayBack, 
+  StepReplayForward, 
   SucceedMission 
 } from "../messages/commands";
+import { ChatChannel } from "../types/types";
 import { PlayerActions } from "./player-actions";
 
 test(`Player Actions - Start Game`, () => {
@@ -19,6 +28,27 @@ test(`Player Actions - Start Game`, () => {
   expect(httpPost.givenUrl).to.equal("/actions/start-game");
 });
 
+test(`Player Actions - Force Start Game`, () => {
+  const httpPost = new HttpPostMock();
+  const playerActions = new PlayerActions(httpPost);
+  playerActions.consume(new ForceStartGame());
+  expect(httpPost.givenUrl).to.equal("/actions/force-start-game");
+});
+
+test(`Player Actions - Mark Ready`, () => {
+  const httpPost = new HttpPostMock();
+  const playerActions = new PlayerActions(httpPost);
+  playerActions.consume(new MarkReady());
+  expect(httpPost.givenUrl).to.equal("/actions/ready");
+});
+
+test(`Player Actions - Mark Not Ready`, () => {
+  const httpPost = new HttpPostMock();
+  const playerActions = new PlayerActions(httpPost);
+  playerActions.consume(new MarkNotReady());
+  expect(httpPost.givenUrl).to.equal("/actions/not-ready");
+});
+
 test(`Player Actions - Leader Selects Member`, () => {
   const httpPost = new HttpPostMock();
   const playerActions = new PlayerActions(httpPost);
@@ -69,3 +99,42 @@ test(`Player Actions - Fail Mission`, () => {
   playerActions.consume(new FailMission());
   expect(httpPost.givenUrl).to.equal("/actions/fail-mission");
 });
+
+test(`Player Actions - Send Chat Message`, () => {
+  const httpPost = new HttpPostMock();
+  const playerActions = new PlayerActions(httpPost);
+  playerActions.consume(new SendChatMessage("it's Bob"));
+  expect(httpPost.givenUrl).to.equal("/actions/chat");
+  expect(httpPost.givenData).to.deep.equal({message: "it's Bob", channel: "public"});
+
+  playerActions.consume(new SendChatMessage("fail it", ChatChannel.Spies));
+  expect(httpPost.givenData).to.deep.equal({message: "fail it", channel: "spies"});
+});
+
+test(`Player Actions - Step Replay Forward`, () => {
+  const httpPost = new HttpPostMock();
+  const playerActions = new PlayerActions(httpPost);
+  playerActions.consume(new StepReplayForward());
+  expect(httpPost.givenUrl).to.equal("/replay/forward");
+});
+
+test(`Player Actions - Step Replay Back`, () => {
+  const httpPost = new HttpPostMock();
+  const playerActions = new PlayerActions(httpPost);
+  playerActions.consume(new StepReplayBack());
+  expect(httpPost.givenUrl).to.equal("/replay/back");
+});
+
+test(`Player Actions - Play Replay`, () => {
+  const httpPost = new HttpPostMock();
+  const playerActions = new PlayerActions(httpPost);
+  playerActions.consume(new PlayReplay());
+  expect(httpPost.givenUrl).to.equal("/replay/play");
+});
+
+test(`Player Actions - Pause Replay`, () => {
+  const httpPost = new HttpPostMock();
+  const playerActions = new PlayerActions(httpPost);
+  playerActions.consume(new PauseReplay());
+  expect(httpPost.givenUrl).to.equal("/replay/pause");
+});
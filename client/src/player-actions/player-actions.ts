@@ -2,11 +2,19 @@ import type { HttpPost } from "../http/post";
 import { 
   ApproveTeam, 
   FailMission, 
+  ForceStartGame, 
   LeaderConfirmsTeam, 
   LeaderDeselectsMember, 
   LeaderSelectsMember, 
+  MarkNotReady, 
+  MarkReady, 
+  PauseReplay, 
+  PlayReplay, 
   RejectTeam, 
+  SendChatMessage, 
   StartGame, 
+  StepReplayBack, 
+  StepReplayForward, 
   SucceedMission 
 } from "../messages/commands";
 import type { Message } from "../messages/message-bus";
@@ -17,9 +25,18 @@ export class PlayerActions {
   ){}
 
   consume(message: Message): void {
-    if (message instanceof StartGame) {
+    if (message instanceof MarkReady) {
+      this.http.post("/actions/ready");
+    }
+    else if (message instanceof MarkNotReady) {
+      this.http.post("/actions/not-ready");
+    }
+    else if (message instanceof StartGame) {
       this.http.post("/actions/start-game");
     }
+    else if (message instanceof ForceStartGame) {
+      this.http.post("/actions/force-start-game");
+    }
     else if (message instanceof LeaderSelectsMember) {
       this.http.post("/actions/leader-selects-member", {member: message.member});
     }
@@ -41,5 +58,20 @@ export class PlayerActions {
     else if (message instanceof FailMission) {
       this.http.post("/actions/fail-mission");
     }
+    else if (message instanceof SendChatMessage) {
+      this.http.post("/actions/chat", {message: message.message, channel: message.channel});
+    }
+    else if (message instanceof StepReplayForward) {
+      this.http.post("/replay/forward");
+    }
+    else if (message instanceof StepReplayBack) {
+      this.http.post("/replay/back");
+    }
+    else if (message instanceof PlayReplay) {
+      this.http.post("/replay/play");
+    }
+    else if (message instanceof PauseReplay) {
+      this.http.post("/replay/pause");
+    }
   }
 }
\ No newline at end of file
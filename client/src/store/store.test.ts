@@ -1,7 +1,7 @@
 import { expect, test } from "vitest";
 import { Store, type StoreValues } from "./store";
 import {get} from "svelte/store";
-import { Allegiance, Dialog, GamePhase, Page } from "../types/types";
+import { Allegiance, ChatChannel, Dialog, GamePhase, Page } from "../types/types";
 
 test(`default values`, () => {
   const store = new Store();
@@ -11,6 +11,7 @@ test(`default values`, () => {
       pageToShow: Page.Loading,
       player: "",
       players: [],
+      readyPlayers: new Set<string>(),
       missionRequirements: [],
       currentMission: 0,
       currentGamePhase: GamePhase.TeamSelection,
@@ -25,8 +26,15 @@ test(`default values`, () => {
       missionResults: [],
       dialogShown: null,
       revealedSpies: new Set<string>(),
+      rejoinCode: "",
+      joinError: "",
       missionDetailsShown: 0,
       winner: null,
+      gameSummary: null,
+      chatMessages: [],
+      chatError: "",
+      spyChannelOpen: false,
+      replayProgress: null,
     }
   );
 });
@@ -117,9 +125,19 @@ test(`showGameRoom`, () => {
 
 test(`definePlayer`, () => {
   const store = new Store();
+  store.showJoinError("player already in group");
   store.definePlayer("testName");
   let storeValues: StoreValues = get(store);
   expect(storeValues.player).to.equal("testName");
+  expect(storeValues.joinError).to.equal("");
+});
+
+test(`showJoinError`, () => {
+  const store = new Store();
+  store.showJoinError("player already in group");
+
+  let storeValues: StoreValues = get(store);
+  expect(storeValues.joinError).to.equal("player already in group");
 });
 
 test(`joinPlayer`, () => {
@@ -134,6 +152,23 @@ test(`joinPlayer`, () => {
   expect(storeValues.players).to.deep.equal(["testName1", "testName2"]);
 });
 
+test(`markPlayerReady`, () => {
+  const store = new Store();
+  store.markPlayerReady("testName1");
+  store.markPlayerReady("testName2");
+  let storeValues: StoreValues = get(store);
+  expect(storeValues.readyPlayers).to.deep.equal(new Set<string>(["testName1", "testName2"]));
+});
+
+test(`markPlayerNotReady`, () => {
+  const store = new Store();
+  store.markPlayerReady("testName1");
+  store.markPlayerReady("testName2");
+  store.markPlayerNotReady("testName1");
+  let storeValues: StoreValues = get(store);
+  expect(storeValues.readyPlayers).to.deep.equal(new Set<string>(["testName2"]));
+});
+
 test(`setMissionRequirements`, () => {
   const store = new Store();
   store.setMissionRequirements([{nbFailuresRequiredToFail: 3, nbPeopleOnMission: 4}, {nbFailuresRequiredToFail: 2, nbPeopleOnMission:4}]);
@@ -356,6 +391,13 @@ test(`rememberSpies`, () => {
   expect(storeValues.revealedSpies).to.deep.equal(new Set<string>(["spy 1", "spy 2"]));
 });
 
+test(`rememberRejoinCode`, () => {
+  const store = new Store();
+  store.rememberRejoinCode("ABCDE");
+  let storeValues: StoreValues = get(store);
+  expect(storeValues.rejoinCode).to.equal("ABCDE");
+});
+
 test(`showLastMissionResult`, () => {
   const store = new Store();
   store.showLastMissionResult();
@@ -376,10 +418,129 @@ test(`showLastMissionResult isn't replayed`, () => {
 
 test(`end game`, () => {
   const store = new Store();
+  store.openSpyChannel();
   store.endGame(Allegiance.Resistance, new Set<string>(["spy 1", "spy2"]));
 
   let storeValues: StoreValues = get(store);
+  expect(storeValues.spyChannelOpen).to.be.false;
   expect(storeValues.winner).to.equal(Allegiance.Resistance);
   expect(storeValues.revealedSpies).to.deep.equal(new Set<string>(["spy 1", "spy2"]));
   expect(storeValues.currentGamePhase).to.equal(GamePhase.GameEnded);
-});
\ No newline at end of file
+});
+
+test(`saveGameSummary`, () => {
+  const store = new Store();
+  const summary = {
+    winner: Allegiance.Resistance,
+    allegiances: new Map<string, Allegiance>([["a", Allegiance.Resistance]]),
+    teamProposals: [],
+    missions: [],
+  };
+  store.saveGameSummary(summary);
+
+  let storeValues: StoreValues = get(store);
+  expect(storeValues.gameSummary).to.deep.equal(summary);
+});
+
+test(`startRematch`, () => {
+  const store = new Store();
+  store.showGameRoom();
+  store.joinPlayer("a");
+  store.rememberRejoinCode("code");
+  store.addChatMessage({player: "a", channel: ChatChannel.Public, message: "again", sentAt: new Date("2021-03-04T05:06:07Z")});
+  store.endGame(Allegiance.Resistance, new Set<string>(["b"]));
+  store.startRematch(["b", "a"]);
+
+  let storeValues: StoreValues = get(store);
+  expect(storeValues.pageToShow).to.equal(Page.Game);
+  expect(storeValues.players).to.deep.equal(["b", "a"]);
+  expect(storeValues.readyPlayers).to.deep.equal(new Set<string>(["b", "a"]));
+  expect(storeValues.rejoinCode).to.equal("code");
+  expect(storeValues.chatMessages).to.deep.equal([{player: "a", channel: ChatChannel.Public, message: "again", sentAt: new Date("2021-03-04T05:06:07Z")}]);
+  expect(storeValues.winner).to.be.null;
+  expect(storeValues.revealedSpies).to.deep.equal(new Set<string>());
+  expect(storeValues.currentGamePhase).to.equal(GamePhase.TeamSelection);
+});
+
+test(`addChatMessage`, () => {
+  const store = new Store();
+  store.showChatError("too many messages");
+  store.addChatMessage({player: "a", channel: ChatChannel.Public, message: "it's Bob", sentAt: new Date("2021-03-04T05:06:07Z")});
+
+  let storeValues: StoreValues = get(store);
+  expect(storeValues.chatMessages).to.deep.equal([{player: "a", channel: ChatChannel.Public, message: "it's Bob", sentAt: new Date("2021-03-04T05:06:07Z")}]);
+  expect(storeValues.chatError).to.equal("");
+});
+
+test(`showChatError`, () => {
+  const store = new Store();
+  store.showChatError("too many messages");
+
+  let storeValues: StoreValues = get(store);
+  expect(storeValues.chatError).to.equal("too many messages");
+});
+
+test(`showChatError isn't replayed`, () => {
+  const store = new Store();
+
+  store.startReplay();
+  store.showChatError("too many messages");
+  store.endReplay();
+
+  let storeValues: StoreValues = get(store);
+  expect(storeValues.chatError).to.equal("");
+});
+
+test(`openSpyChannel`, () => {
+  const store = new Store();
+  store.openSpyChannel();
+
+  let storeValues: StoreValues = get(store);
+  expect(storeValues.spyChannelOpen).to.be.true;
+});
+
+test(`revealSpyChat`, () => {
+  const store = new Store();
+  store.openSpyChannel();
+  store.addChatMessage({player: "a", channel: ChatChannel.Public, message: "hi", sentAt: new Date("2021-03-04T05:06:07Z")});
+  store.addChatMessage({player: "b", channel: ChatChannel.Spies, message: "fail it", sentAt: new Date("2021-03-04T05:06:09Z")});
+  store.addChatMessage({player: "c", channel: ChatChannel.Public, message: "bye", sentAt: new Date("2021-03-04T05:06:10Z")});
+  store.revealSpyChat([
+    {player: "d", channel: ChatChannel.Spies, message: "ok", sentAt: new Date("2021-03-04T05:06:08Z")},
+    {player: "b", channel: ChatChannel.Spies, message: "fail it", sentAt: new Date("2021-03-04T05:06:09Z")},
+  ]);
+
+  let storeValues: StoreValues = get(store);
+  expect(storeValues.spyChannelOpen).to.be.false;
+  expect(storeValues.chatMessages).to.deep.equal([
+    {player: "a", channel: ChatChannel.Public, message: "hi", sentAt: new Date("2021-03-04T05:06:07Z")},
+    {player: "d", channel: ChatChannel.Spies, message: "ok", sentAt: new Date("2021-03-04T05:06:08Z")},
+    {player: "b", channel: ChatChannel.Spies, message: "fail it", sentAt: new Date("2021-03-04T05:06:09Z")},
+    {player: "c", channel: ChatChannel.Public, message: "bye", sentAt: new Date("2021-03-04T05:06:10Z")},
+  ]);
+});
+
+test(`rewindReplay`, () => {
+  const store = new Store();
+  store.definePlayer("a");
+  store.updateReplayProgress({position: 5, nbEvents: 10, playing: true});
+  store.showGameRoom();
+  store.joinPlayer("a");
+  store.addChatMessage({player: "a", channel: ChatChannel.Public, message: "hi", sentAt: new Date("2021-03-04T05:06:07Z")});
+  store.rewindReplay();
+
+  let storeValues: StoreValues = get(store);
+  expect(storeValues.pageToShow).to.equal(Page.PartyRoom);
+  expect(storeValues.player).to.equal("a");
+  expect(storeValues.players).to.deep.equal([]);
+  expect(storeValues.chatMessages).to.deep.equal([]);
+  expect(storeValues.replayProgress).to.deep.equal({position: 5, nbEvents: 10, playing: true});
+});
+
+test(`updateReplayProgress`, () => {
+  const store = new Store();
+  store.updateReplayProgress({position: 1, nbEvents: 10, playing: false});
+
+  let storeValues: StoreValues = get(store);
+  expect(storeValues.replayProgress).to.deep.equal({position: 1, nbEvents: 10, playing: false});
+});
@@ -2,11 +2,15 @@ import {get, type Readable, type Writable} from "svelte/store";
 import {writable} from "svelte/store";
 import { 
   Allegiance,
+  ChatChannel,
   Dialog,
   GamePhase,
   Page, 
+  type ChatMessage, 
+  type GameSummary, 
   type MissionRequirement, 
   type MissionResult, 
+  type ReplayProgress, 
   type TeamVotes 
 } from "../types/types";
 
@@ -14,6 +18,7 @@ export interface StoreValues {
   pageToShow: Page
   player: string
   players: string[]
+  readyPlayers: Set<string>
   missionRequirements: MissionRequirement[]
   currentMission: number,
   currentGamePhase: GamePhase,
@@ -28,8 +33,15 @@ export interface StoreValues {
   missionResults: MissionResult[]
   dialogShown: Dialog,
   revealedSpies: Set<string>,
+  rejoinCode: string,
+  joinError: string,
   missionDetailsShown: number,
   winner: Allegiance,
+  gameSummary: GameSummary | null,
+  chatMessages: ChatMessage[],
+  chatError: string,
+  spyChannelOpen: boolean,
+  replayProgress: ReplayProgress | null,
 }
 
 function defaultValues(): StoreValues {
@@ -37,6 +49,7 @@ function defaultValues(): StoreValues {
     pageToShow: Page.Loading,
     player: "",
     players: [],
+    readyPlayers: new Set<string>(),
     missionRequirements: [],
     currentMission: 0,
     currentGamePhase: GamePhase.TeamSelection,
@@ -51,8 +64,15 @@ function defaultValues(): StoreValues {
     missionResults: [],
     dialogShown: null,
     revealedSpies: new Set<string>(),
+    rejoinCode: "",
+    joinError: "",
     missionDetailsShown: 0,
     winner: null,
+    gameSummary: null,
+    chatMessages: [],
+    chatError: "",
+    spyChannelOpen: false,
+    replayProgress: null,
   }
 }
 
@@ -103,6 +123,8 @@ export class Store implements Readable<StoreValues> {
   readonly showPartyRoom = showPartyRoom;
   readonly showGameRoom = showGameRoom;
   readonly joinPlayer = joinPlayer;
+  readonly markPlayerReady = markPlayerReady;
+  readonly markPlayerNotReady = markPlayerNotReady;
   readonly definePlayer = definePlayer;
   readonly setMissionRequirements = setMissionRequirements;
   readonly startTeamSelection = startTeamSelection;
@@ -119,8 +141,18 @@ export class Store implements Readable<StoreValues> {
   readonly showMissionDetails = showMissionDetails;
   readonly closeDialog = closeDialog;
   readonly rememberSpies = rememberSpies;
+  readonly rememberRejoinCode = rememberRejoinCode;
+  readonly showJoinError = showJoinError;
   readonly showLastMissionResult = showLastMissionResult;
   readonly endGame = endGame;
+  readonly saveGameSummary = saveGameSummary;
+  readonly startRematch = startRematch;
+  readonly addChatMessage = addChatMessage;
+  readonly showChatError = showChatError;
+  readonly openSpyChannel = openSpyChannel;
+  readonly revealSpyChat = revealSpyChat;
+  readonly rewindReplay = rewindReplay;
+  readonly updateReplayProgress = updateReplayProgress;
 }
 
 function showLobby(this: Store) {
@@ -147,6 +179,7 @@ function showGameRoom(this: Store) {
 function definePlayer(this: Store, name: string) {
   this.update(v => {
     v.player = name;
+    v.joinError = "";
     return v;
   });
 }
@@ -158,6 +191,20 @@ function joinPlayer(this: Store, name: string) {
   });
 }
 
+function markPlayerReady(this: Store, name: string) {
+  this.update(v => {
+    v.readyPlayers.add(name);
+    return v;
+  });
+}
+
+function markPlayerNotReady(this: Store, name: string) {
+  this.update(v => {
+    v.readyPlayers.delete(name);
+    return v;
+  });
+}
+
 function setMissionRequirements(this: Store, requirements: MissionRequirement[]) {
   this.update(v => {
     v.missionRequirements = requirements.slice();
@@ -282,6 +329,20 @@ function rememberSpies(this: Store, spies: Set<string>) {
   })
 }
 
+function rememberRejoinCode(this: Store, code: string) {
+  this.update(v => {
+    v.rejoinCode = code;
+    return v
+  })
+}
+
+function showJoinError(this: Store, reason: string) {
+  this.updateNoReplay(v => {
+    v.joinError = reason;
+    return v;
+  });
+}
+
 function showLastMissionResult(this: Store) {
   this.updateNoReplay(v => {
     v.dialogShown = Dialog.LastMissionResult;
@@ -294,6 +355,76 @@ function endGame(winner: Allegiance, spies: Set<string>) {
     v.currentGamePhase = GamePhase.GameEnded;
     v.revealedSpies = spies;
     v.winner = winner;
+    v.spyChannelOpen = false;
     return v
   })
-}
\ No newline at end of file
+}
+
+function saveGameSummary(this: Store, summary: GameSummary) {
+  this.update(v => {
+    v.gameSummary = summary;
+    return v;
+  });
+}
+
+function startRematch(this: Store, players: string[]) {
+  this.update(v => ({
+    ...defaultValues(),
+    pageToShow: v.pageToShow,
+    player: v.player,
+    players: players.slice(),
+    readyPlayers: new Set<string>(players),
+    rejoinCode: v.rejoinCode,
+    chatMessages: v.chatMessages,
+    chatError: v.chatError,
+  }));
+}
+
+function addChatMessage(this: Store, chatMessage: ChatMessage) {
+  this.update(v => {
+    v.chatMessages.push(chatMessage);
+    v.chatError = "";
+    return v;
+  });
+}
+
+function showChatError(this: Store, reason: string) {
+  this.updateNoReplay(v => {
+    v.chatError = reason;
+    return v;
+  });
+}
+
+function openSpyChannel(this: Store) {
+  this.update(v => {
+    v.spyChannelOpen = true;
+    return v;
+  });
+}
+
+function revealSpyChat(this: Store, chatMessages: ChatMessage[]) {
+  this.update(v => {
+    v.chatMessages = v.chatMessages
+      .filter(m => m.channel !== ChatChannel.Spies)
+      .concat(chatMessages)
+      .sort((a, b) => a.sentAt.getTime() - b.sentAt.getTime());
+    v.spyChannelOpen = false;
+    return v;
+  });
+}
+
+function rewindReplay(this: Store) {
+  this.update(v => ({
+    ...defaultValues(),
+    pageToShow: Page.PartyRoom,
+    player: v.player,
+    replayProgress: v.replayProgress,
+  }));
+}
+
+function updateReplayProgress(this: Store, progress: ReplayProgress) {
+  this.update(v => {
+    v.replayProgress = progress;
+    return v;
+  });
+}
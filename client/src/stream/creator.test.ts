@@ -10,12 +10,15 @@ class WebsocketMock{
 }
 
 class EventHandlerMock {
+  public since: number = 7;
+  public closeCode: number;
   public onCloseCalled: boolean;
   public onErrorCalled: boolean;
   public receivedEvent: ServerEvent
 
-  onClose(): void {
+  onClose(code: number): void {
     this.onCloseCalled = true;
+    this.closeCode = code;
   }
   onError(): void {
     this.onErrorCalled = true
@@ -25,18 +28,25 @@ class EventHandlerMock {
   }
 }
 
-function setup(): {websocket: WebsocketMock, handler: EventHandlerMock} {
+function setup(): {websocket: WebsocketMock, handler: EventHandlerMock, givenSince: number} {
   let websocket = new WebsocketMock();
   let handler = new EventHandlerMock();
-  const ss = new Creator(() => websocket, handler);
+  let givenSince: number;
+  const ss = new Creator(since => {givenSince = since; return websocket}, handler);
   ss.create();
-  return {websocket, handler};
+  return {websocket, handler, givenSince};
 }
 
+test(`Creator - opens since the handler's last sequence`, () => {
+  let {givenSince} = setup();
+  expect(givenSince).to.equal(7);
+});
+
 test(`Creator - on close`, () => {
   let {websocket, handler} = setup();
-  websocket.onclose({} as CloseEvent);
+  websocket.onclose({code: 4408} as CloseEvent);
   expect(handler.onCloseCalled).to.be.true;
+  expect(handler.closeCode).to.equal(4408);
 });
 
 test(`Creator - on error`, () => {
@@ -7,27 +7,28 @@ interface BasicWebsocket {
 }
 
 interface EventHandler {
-  onClose(): void;
+  readonly since: number;
+  onClose(code: number): void;
   onError(): void;
   onEvent(event: ServerEvent): void
 }
 
 export class Creator {
   constructor(
-    private readonly wsCreator: () => BasicWebsocket,
+    private readonly wsCreator: (since: number) => BasicWebsocket,
     private readonly handler: EventHandler,
   ){}
 
   create(): void {
-    let socket = this.wsCreator();
+    let socket = this.wsCreator(this.handler.since);
 
     socket.onmessage = event => {
       let gameEvent: ServerEvent = JSON.parse(event.data);
       this.handler.onEvent(gameEvent);
     };
 
-    socket.onclose = () => {
-      this.handler.onClose();
+    socket.onclose = event => {
+      this.handler.onClose(event.code);
     };
 
     socket.onerror = () => {
@@ -2,9 +2,12 @@ import { expect, test } from "vitest";
 import { DispatcherMock } from "../messages/dispatcher.test-utils";
 import { 
   AllPlayerVotedOnTeam, 
+  ChatMessageRejected, 
+  ChatMessageSent, 
   EventsReplayEnded, 
   EventsReplayStarted, 
   GameStarted, 
+  GameSummarized, 
   LeaderConfirmedTeam, 
   LeaderDeselectedMember, 
   LeaderSelectedMember, 
@@ -14,19 +17,55 @@ import {
   PlayerConnected, 
   PlayerDisconnected, 
   PlayerJoined, 
+  PlayerNotReady, 
+  PlayerReady, 
   PlayerVotedOnTeam, 
   PlayerWorkedOnMission, 
+  RejoinCodeIssued, 
+  RematchStarted, 
+  ReplayProgressed, 
+  ReplayRewound, 
   ServerConnectionClosed, 
   ServerConnectionErrorOccured, 
-  SpiesRevealed 
+  ServerConnectionLost, 
+  SpiesRevealed, 
+  SpyChannelOpened, 
+  SpyChatRevealed 
 } from "../messages/events";
+import { Allegiance, ChatChannel } from "../types/types";
 import { Handler } from "./handler";
 
 test(`Handler - onClose`, () => {
+  for (const code of [1000, 1006, 4401, 4403]) {
+    const dispatcher: DispatcherMock = new DispatcherMock();
+    const handler = new Handler(dispatcher);
+    handler.onEvent({Sequence: 3, PlayerJoined: {Name: "testName"}});
+    handler.onClose(code);
+    expect(dispatcher.receivedMessage).to.deep.equal(new ServerConnectionClosed());
+    expect(handler.since).to.equal(0);
+  }
+});
+
+test(`Handler - onClose - resumable`, () => {
+  for (const code of [1001, 4408]) {
+    const dispatcher: DispatcherMock = new DispatcherMock();
+    const handler = new Handler(dispatcher);
+    handler.onEvent({Sequence: 3, PlayerJoined: {Name: "testName"}});
+    handler.onClose(code);
+    expect(dispatcher.receivedMessage).to.deep.equal(new ServerConnectionLost());
+    expect(handler.since).to.equal(3);
+  }
+});
+
+test(`Handler - tracks the highest sequence`, () => {
   const dispatcher: DispatcherMock = new DispatcherMock();
   const handler = new Handler(dispatcher);
-  handler.onClose();
-  expect(dispatcher.receivedMessage).to.deep.equal(new ServerConnectionClosed());
+  expect(handler.since).to.equal(0);
+
+  handler.onEvent({Sequence: 4, PlayerJoined: {Name: "a"}});
+  handler.onEvent({EventsReplayEnded: {}});
+  handler.onEvent({Sequence: 2, PlayerJoined: {Name: "b"}});
+  expect(handler.since).to.equal(4);
 });
 
 test(`Handler - onError`, () => {
@@ -40,7 +79,16 @@ test(`Handler - onEvent - EventsReplayStarted`, () => {
   const dispatcher: DispatcherMock = new DispatcherMock();
   const handler = new Handler(dispatcher);
   handler.onEvent({EventsReplayStarted: {Player: "testName"}});
-  expect(dispatcher.receivedMessage).to.deep.equal(new EventsReplayStarted("testName"));
+  expect(dispatcher.receivedMessage).to.deep.equal(new EventsReplayStarted("testName", 0));
+});
+
+test(`Handler - onEvent - EventsReplayStarted since`, () => {
+  const dispatcher: DispatcherMock = new DispatcherMock();
+  const handler = new Handler(dispatcher);
+  handler.onEvent({Sequence: 9, PlayerJoined: {Name: "testName"}});
+  handler.onEvent({EventsReplayStarted: {Player: "testName", Since: 5}});
+  expect(dispatcher.receivedMessage).to.deep.equal(new EventsReplayStarted("testName", 5));
+  expect(handler.since).to.equal(5);
 });
 
 test(`Handler - onEvent - EventsReplayEnded`, () => {
@@ -71,6 +119,27 @@ test(`Handler - onEvent - PlayerJoined`, () => {
   expect(dispatcher.receivedMessage).to.deep.equal(new PlayerJoined("testName"));
 });
 
+test(`Handler - onEvent - PlayerReady`, () => {
+  const dispatcher: DispatcherMock = new DispatcherMock();
+  const handler = new Handler(dispatcher);
+  handler.onEvent({PlayerReady: {Name: "testName"}});
+  expect(dispatcher.receivedMessage).to.deep.equal(new PlayerReady("testName"));
+});
+
+test(`Handler - onEvent - PlayerNotReady`, () => {
+  const dispatcher: DispatcherMock = new DispatcherMock();
+  const handler = new Handler(dispatcher);
+  handler.onEvent({PlayerNotReady: {Name: "testName"}});
+  expect(dispatcher.receivedMessage).to.deep.equal(new PlayerNotReady("testName"));
+});
+
+test(`Handler - onEvent - RematchStarted`, () => {
+  const dispatcher: DispatcherMock = new DispatcherMock();
+  const handler = new Handler(dispatcher);
+  handler.onEvent({RematchStarted: {Players: ["b", "a"]}});
+  expect(dispatcher.receivedMessage).to.deep.equal(new RematchStarted(["b", "a"]));
+});
+
 test(`Handler - onEvent - GameStarted`, () => {
   const dispatcher: DispatcherMock = new DispatcherMock();
   const handler = new Handler(dispatcher);
@@ -92,6 +161,13 @@ test(`Handler - onEvent - SpiesRevealed - no spies`, () => {
   expect(dispatcher.receivedMessage).to.deep.equal(new SpiesRevealed(new Set<string>([])));
 });
 
+test(`Handler - onEvent - RejoinCodeIssued`, () => {
+  const dispatcher: DispatcherMock = new DispatcherMock();
+  const handler = new Handler(dispatcher);
+  handler.onEvent({RejoinCodeIssued: {Code: "ABCDE"}});
+  expect(dispatcher.receivedMessage).to.deep.equal(new RejoinCodeIssued("ABCDE"));
+});
+
 test(`Handler - onEvent - LeaderStartedToSelectMembers`, () => {
   const dispatcher: DispatcherMock = new DispatcherMock();
   const handler = new Handler(dispatcher);
@@ -168,3 +244,72 @@ test(`Handler - onEvent - MissionCompleted`, () => {
   handler.onEvent({MissionCompleted: {Success: false, NbFails: 1}});
   expect(dispatcher.receivedMessage).to.deep.equal(new MissionCompleted(false, 1));
 });
+
+test(`Handler - onEvent - ChatMessageSent`, () => {
+  const dispatcher: DispatcherMock = new DispatcherMock();
+  const handler = new Handler(dispatcher);
+  handler.onEvent({ChatMessageSent: {Player: "testName", Channel: "public", Message: "it's Bob", SentAt: "2021-03-04T05:06:07Z"}});
+  expect(dispatcher.receivedMessage).to.deep.equal(new ChatMessageSent({
+    player: "testName", 
+    channel: ChatChannel.Public,
+    message: "it's Bob", 
+    sentAt: new Date("2021-03-04T05:06:07Z"),
+  }));
+});
+
+test(`Handler - onEvent - ChatMessageRejected`, () => {
+  const dispatcher: DispatcherMock = new DispatcherMock();
+  const handler = new Handler(dispatcher);
+  handler.onEvent({ChatMessageRejected: {Reason: "too many messages"}});
+  expect(dispatcher.receivedMessage).to.deep.equal(new ChatMessageRejected("too many messages"));
+});
+
+test(`Handler - onEvent - SpyChannelOpened`, () => {
+  const dispatcher: DispatcherMock = new DispatcherMock();
+  const handler = new Handler(dispatcher);
+  handler.onEvent({SpyChannelOpened: {Spies: ["a", "b"]}});
+  expect(dispatcher.receivedMessage).to.deep.equal(new SpyChannelOpened());
+});
+
+test(`Handler - onEvent - SpyChatRevealed`, () => {
+  const dispatcher: DispatcherMock = new DispatcherMock();
+  const handler = new Handler(dispatcher);
+  handler.onEvent({SpyChatRevealed: {Messages: [{Player: "a", Channel: "spies", Message: "fail it", SentAt: "2021-03-04T05:06:07Z"}]}});
+  expect(dispatcher.receivedMessage).to.deep.equal(new SpyChatRevealed([{
+    player: "a", 
+    channel: ChatChannel.Spies,
+    message: "fail it", 
+    sentAt: new Date("2021-03-04T05:06:07Z"),
+  }]));
+});
+
+test(`Handler - onEvent - GameSummarized`, () => {
+  const dispatcher: DispatcherMock = new DispatcherMock();
+  const handler = new Handler(dispatcher);
+  handler.onEvent({GameSummarized: {
+    Winner: "resistance",
+    Allegiances: {"a": "spy", "b": "resistance"},
+    TeamProposals: [{Mission: 1, Leader: "a", Team: ["a", "b"], Votes: {"a": true, "b": false}, Approved: true}],
+    Missions: [{Mission: 1, Team: ["a", "b"], Success: true, NbFails: 0}],
+  }});
+  expect(dispatcher.receivedMessage).to.deep.equal(new GameSummarized({
+    winner: Allegiance.Resistance,
+    allegiances: new Map<string, Allegiance>([["a", Allegiance.Spies], ["b", Allegiance.Resistance]]),
+    teamProposals: [{mission: 1, leader: "a", team: ["a", "b"], playerVotes: new Map<string, boolean>([["a", true], ["b", false]]), approved: true}],
+    missions: [{mission: 1, team: ["a", "b"], success: true, nbFails: 0, failedBy: []}],
+  }));
+});
+
+test(`Handler - onEvent - ReplayRewound`, () => {
+  const dispatcher: DispatcherMock = new DispatcherMock();
+  const handler = new Handler(dispatcher);
+  handler.onEvent({ReplayRewound: {}});
+  expect(dispatcher.receivedMessage).to.deep.equal(new ReplayRewound());
+});
+
+test(`Handler - onEvent - ReplayProgressed`, () => {
+  const dispatcher: DispatcherMock = new DispatcherMock();
+  const handler = new Handler(dispatcher);
+  handler.onEvent({ReplayProgressed: {Position: 3, NbEvents: 10, Playing: true}});
+  expect(dispatcher.receivedMessage).to.deep.equal(new ReplayProgressed({position: 3, nbEvents: 10, playing: true}));
+});
@@ -1,9 +1,14 @@
 import type { Dispatcher } from "../messages/dispatcher";
 import { 
   AllPlayerVotedOnTeam,
+  ChatMessageRejected, 
+  ChatMessageSent, 
+  SpyChannelOpened, 
+  SpyChatRevealed, 
   EventsReplayEnded, 
   EventsReplayStarted, 
   GameEnded, 
+  GameSummarized, 
   GameStarted, 
   LeaderConfirmedTeam, 
   LeaderDeselectedMember, 
@@ -14,28 +19,54 @@ import {
   PlayerConnected, 
   PlayerDisconnected, 
   PlayerJoined, 
+  PlayerNotReady, 
+  PlayerReady, 
   PlayerVotedOnTeam, 
   PlayerWorkedOnMission, 
+  RejoinCodeIssued, 
+  RematchStarted, 
+  ReplayProgressed, 
+  ReplayRewound, 
   ServerConnectionClosed, 
   ServerConnectionErrorOccured, 
+  ServerConnectionLost, 
   SpiesRevealed 
 } from "../messages/events";
-import { Allegiance } from "../types/types";
+import { Allegiance, ChatChannel, type ChatMessage, type GameSummary } from "../types/types";
 import type { ServerEvent } from "./server-event";
 
+// going away and too far behind, a failed upgrade shows up as 1006 and isn't retried
+const resumableCloseCodes = [1001, 4408];
+
 export class Handler {
+  private lastSequence: number = 0;
 
   constructor(private readonly dispatcher: Dispatcher){}
 
-  onClose(): void {
-    this.dispatcher.dispatch(new ServerConnectionClosed());
+  get since(): number {
+    return this.lastSequence;
+  }
+
+  onClose(code: number): void {
+    if (resumableCloseCodes.includes(code)) {
+      this.dispatcher.dispatch(new ServerConnectionLost());
+    }
+    else {
+      this.lastSequence = 0;
+      this.dispatcher.dispatch(new ServerConnectionClosed());
+    }
   }
   onError(): void {
     this.dispatcher.dispatch(new ServerConnectionErrorOccured());
   }
   onEvent(event: ServerEvent) {
+    if (event.Sequence > this.lastSequence) {
+      this.lastSequence = event.Sequence;
+    }
+
     if (event.EventsReplayStarted) {
-      this.dispatcher.dispatch(new EventsReplayStarted(event.EventsReplayStarted.Player));
+      this.lastSequence = event.EventsReplayStarted.Since ?? 0;
+      this.dispatcher.dispatch(new EventsReplayStarted(event.EventsReplayStarted.Player, this.lastSequence));
     }
     else if (event.EventsReplayEnded) {
       this.dispatcher.dispatch(new EventsReplayEnded());
@@ -49,6 +80,15 @@ export class Handler {
     else if (event.PlayerJoined) {
       this.dispatcher.dispatch(new PlayerJoined(event.PlayerJoined.Name));
     }
+    else if (event.PlayerReady) {
+      this.dispatcher.dispatch(new PlayerReady(event.PlayerReady.Name));
+    }
+    else if (event.PlayerNotReady) {
+      this.dispatcher.dispatch(new PlayerNotReady(event.PlayerNotReady.Name));
+    }
+    else if (event.RematchStarted) {
+      this.dispatcher.dispatch(new RematchStarted(event.RematchStarted.Players));
+    }
     else if (event.GameStarted) {
       const req = event.GameStarted.MissionRequirements
         .map(r => ({nbPeopleOnMission: r.NbPeopleOnMission, nbFailuresRequiredToFail: r.NbFailuresRequiredToFail}))
@@ -57,6 +97,9 @@ export class Handler {
     else if (event.SpiesRevealed) {
       this.dispatcher.dispatch(new SpiesRevealed(new Set<string>(Object.keys(event.SpiesRevealed.Spies || []))));
     }
+    else if (event.RejoinCodeIssued) {
+      this.dispatcher.dispatch(new RejoinCodeIssued(event.RejoinCodeIssued.Code));
+    }
     else if (event.LeaderStartedToSelectMembers) {
       this.dispatcher.dispatch(new LeaderStartedToSelectMembers(event.LeaderStartedToSelectMembers.Leader));
     }
@@ -88,8 +131,67 @@ export class Handler {
       this.dispatcher.dispatch(new MissionCompleted(event.MissionCompleted.Success, event.MissionCompleted.NbFails));
     }
     else if (event.GameEnded) {
-      const winner = event.GameEnded.Winner === "spy" ? Allegiance.Spies : Allegiance.Resistance
+      const winner = toAllegiance(event.GameEnded.Winner);
       this.dispatcher.dispatch(new GameEnded(winner, new Set<string>(event.GameEnded.Spies)));
     }
+    else if (event.GameSummarized) {
+      this.dispatcher.dispatch(new GameSummarized(toGameSummary(event.GameSummarized)));
+    }
+    else if (event.ChatMessageSent) {
+      this.dispatcher.dispatch(new ChatMessageSent(toChatMessage(event.ChatMessageSent)));
+    }
+    else if (event.ChatMessageRejected) {
+      this.dispatcher.dispatch(new ChatMessageRejected(event.ChatMessageRejected.Reason));
+    }
+    else if (event.SpyChannelOpened) {
+      this.dispatcher.dispatch(new SpyChannelOpened());
+    }
+    else if (event.SpyChatRevealed) {
+      this.dispatcher.dispatch(new SpyChatRevealed(event.SpyChatRevealed.Messages.map(toChatMessage)));
+    }
+    else if (event.ReplayRewound) {
+      this.dispatcher.dispatch(new ReplayRewound());
+    }
+    else if (event.ReplayProgressed) {
+      this.dispatcher.dispatch(new ReplayProgressed({
+        position: event.ReplayProgressed.Position,
+        nbEvents: event.ReplayProgressed.NbEvents,
+        playing: event.ReplayProgressed.Playing,
+      }));
+    }
   }
+}
+
+function toChatMessage(chatMessage: {Player: string, Channel: string, Message: string, SentAt: string}): ChatMessage {
+  return {
+    player: chatMessage.Player,
+    channel: chatMessage.Channel === ChatChannel.Spies ? ChatChannel.Spies : ChatChannel.Public,
+    message: chatMessage.Message,
+    sentAt: new Date(chatMessage.SentAt),
+  };
+}
+
+function toAllegiance(allegiance: string): Allegiance {
+  return allegiance === "spy" ? Allegiance.Spies : Allegiance.Resistance;
+}
+
+function toGameSummary(summary: ServerEvent["GameSummarized"]): GameSummary {
+  return {
+    winner: toAllegiance(summary.Winner),
+    allegiances: new Map<string, Allegiance>(Object.keys(summary.Allegiances).map(k => [k, toAllegiance(summary.Allegiances[k])])),
+    teamProposals: summary.TeamProposals.map(p => ({
+      mission: p.Mission,
+      leader: p.Leader,
+      team: p.Team,
+      playerVotes: new Map<string, boolean>(Object.keys(p.Votes).map(k => [k, p.Votes[k]])),
+      approved: p.Approved,
+    })),
+    missions: summary.Missions.map(m => ({
+      mission: m.Mission,
+      team: m.Team,
+      success: m.Success,
+      nbFails: m.NbFails,
+      failedBy: m.FailedBy ?? [],
+    })),
+  };
 }
\ No newline at end of file
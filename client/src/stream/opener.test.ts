@@ -1,5 +1,5 @@
 import { expect, test } from "vitest";
-import { AppLoaded, JoinPartySucceeded } from "../messages/events";
+import { AppLoaded, JoinPartySucceeded, ServerConnectionLost } from "../messages/events";
 import { Opener } from "./opener";
 
 test(`Opener - open on AppLoaded`, () => {
@@ -15,3 +15,14 @@ test(`Opener - open on JoinPartySucceded`, () => {
   opener.consume(new JoinPartySucceeded())
   expect(wasCreated).to.be.true;
 });
+
+test(`Opener - reopen later on ServerConnectionLost`, () => {
+  let wasCreated = false
+  let scheduled: () => void;
+  const opener = new Opener({create: () => {wasCreated = true;}}, f => {scheduled = f});
+  opener.consume(new ServerConnectionLost())
+  expect(wasCreated).to.be.false;
+
+  scheduled();
+  expect(wasCreated).to.be.true;
+});
@@ -1,13 +1,21 @@
-import { AppLoaded, JoinPartySucceeded } from "../messages/events";
+import { AppLoaded, JoinPartySucceeded, ServerConnectionLost } from "../messages/events";
 import type { Message } from "../messages/message-bus";
 
+const reconnectDelay = 2000;
+
 export class Opener {
-  constructor(private readonly creator: {create: ()=>void}){}
+  constructor(
+    private readonly creator: {create: ()=>void},
+    private readonly schedule: (f: ()=>void) => void = f => setTimeout(f, reconnectDelay),
+  ){}
 
   consume(message: Message): void {
     if(message instanceof AppLoaded || 
       message instanceof JoinPartySucceeded) {
       this.creator.create();
     }
+    else if(message instanceof ServerConnectionLost) {
+      this.schedule(() => this.creator.create());
+    }
   }
 }
\ No newline at end of file
@@ -1,4 +1,6 @@
 export interface ServerEvent {
+  Sequence?: number,
+
   PlayerConnected?: {
     Name: string
   },
@@ -10,6 +12,14 @@ export interface ServerEvent {
   PlayerJoined?: {
     Name: string
   },
+
+  PlayerReady?: {
+    Name: string
+  },
+
+  PlayerNotReady?: {
+    Name: string
+  },
   
   GameStarted?: {
     MissionRequirements: {
@@ -21,6 +31,10 @@ export interface ServerEvent {
   SpiesRevealed?: {
     Spies: {[name:string]:{}}
   },
+
+  RejoinCodeIssued?: {
+    Code: string,
+  },
   
   LeaderStartedToSelectMembers?: {
     Leader: string,
@@ -64,9 +78,65 @@ export interface ServerEvent {
     Spies: string[],
   }
 
+  RematchStarted?: {
+    Players: string[],
+  }
+
+  GameSummarized?: {
+    Winner: string,
+    Allegiances: {[name:string]: string},
+    TeamProposals: {
+      Mission: number,
+      Leader: string,
+      Team: string[],
+      Votes: {[name:string]: boolean},
+      Approved: boolean,
+    }[],
+    Missions: {
+      Mission: number,
+      Team: string[],
+      Success: boolean,
+      NbFails: number,
+      FailedBy?: string[],
+    }[],
+  }
+
+  ChatMessageSent?: {
+    Player: string,
+    Channel: string,
+    Message: string,
+    SentAt: string,
+  }
+
+  ChatMessageRejected?: {
+    Reason: string,
+  }
+
+  SpyChannelOpened?: {
+    Spies: string[],
+  }
+
+  SpyChatRevealed?: {
+    Messages: {
+      Player: string,
+      Channel: string,
+      Message: string,
+      SentAt: string,
+    }[],
+  }
+
   EventsReplayStarted?: {
     Player: string,
+    Since?: number,
   }
   
   EventsReplayEnded?: {}
+
+  ReplayRewound?: {}
+
+  ReplayProgressed?: {
+    Position: number,
+    NbEvents: number,
+    Playing: boolean,
+  }
 }
\ No newline at end of file
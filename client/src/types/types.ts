@@ -40,4 +40,45 @@ export interface MissionRequirement {
 export enum Allegiance {
   Resistance = "Resistance",
   Spies = "Spies",
-}
\ No newline at end of file
+}
+
+export interface TeamProposal {
+  readonly mission: number
+  readonly leader: string
+  readonly team: string[]
+  readonly playerVotes: Map<string, boolean>
+  readonly approved: boolean
+}
+
+export interface MissionSummary {
+  readonly mission: number
+  readonly team: string[]
+  readonly success: boolean
+  readonly nbFails: number
+  readonly failedBy: string[]
+}
+
+export interface GameSummary {
+  readonly winner: Allegiance
+  readonly allegiances: Map<string, Allegiance>
+  readonly teamProposals: TeamProposal[]
+  readonly missions: MissionSummary[]
+}
+
+export enum ChatChannel {
+  Public = "public",
+  Spies = "spies",
+}
+
+export interface ChatMessage {
+  readonly player: string
+  readonly channel: ChatChannel
+  readonly message: string
+  readonly sentAt: Date
+}
+
+export interface ReplayProgress {
+  readonly position: number
+  readonly nbEvents: number
+  readonly playing: boolean
+}
@@ -1,4 +1,5 @@
 import type { AppValues } from "./App-service";
+import type { ChatValues } from "./components/Chat-service";
 import type { EndGameValues } from "./components/EndGame-service";
 import type { GameValues } from "./components/Game-service";
 import type { IdentityValues } from "./components/Identity-service";
@@ -7,10 +8,11 @@ import type { MissionConductingValues } from "./components/MissionConducting-ser
 import { MissionTimeline, type MissionDetailsValues } from "./components/MissionDetails-service";
 import type { MissionTrackerValues } from "./components/MissionTracker-service";
 import type { PartyRoomValues } from "./components/PartyRoom-service";
+import type { ReplayControlsValues } from "./components/ReplayControls-service";
 import type { TeamSelectionValues } from "./components/TeamSelection-service";
 import type { TeamVoteValues } from "./components/TeamVote-service";
 import type { StoreValues } from "./store/store";
-import type { Allegiance, Dialog, GamePhase, MissionRequirement, MissionResult, Page, TeamVotes } from "./types/types";
+import type { Allegiance, ChatMessage, Dialog, GamePhase, GameSummary, MissionRequirement, MissionResult, Page, ReplayProgress, TeamVotes } from "./types/types";
 
 export class IdentityValuesBroker implements IdentityValues {
   constructor(private readonly storeValues: StoreValues){}
@@ -21,6 +23,9 @@ export class IdentityValuesBroker implements IdentityValues {
   get revealedSpies(): Set<string>{
     return this.storeValues.revealedSpies;
   }
+  get rejoinCode(): string {
+    return this.storeValues.rejoinCode;
+  }
 }
 
 export class MissionTrackerValuesBroker implements MissionTrackerValues {
@@ -164,6 +169,10 @@ export class EndGameValuesBroker implements EndGameValues {
   get player(): string {
     return this.storeValues.player;
   }
+
+  get gameSummary(): GameSummary | null {
+    return this.storeValues.gameSummary;
+  }
 }
 
 export class GameValuesBroker implements GameValues {
@@ -219,6 +228,42 @@ export class PartyRoomValuesBroker implements PartyRoomValues {
   get hasPlayerJoined(): boolean {
     return this.storeValues.players.includes(this.storeValues.player);
   }
+
+  get player(): string {
+    return this.storeValues.player;
+  }
+
+  get readyPlayers(): Set<string> {
+    return this.storeValues.readyPlayers;
+  }
+
+  get joinError(): string {
+    return this.storeValues.joinError;
+  }
+}
+
+export class ChatValuesBroker implements ChatValues {
+  constructor(private readonly storeValues: StoreValues){}
+
+  get chatMessages(): ChatMessage[] {
+    return this.storeValues.chatMessages;
+  }
+
+  get chatError(): string {
+    return this.storeValues.chatError;
+  }
+
+  get spyChannelOpen(): boolean {
+    return this.storeValues.spyChannelOpen;
+  }
+}
+
+export class ReplayControlsValuesBroker implements ReplayControlsValues {
+  constructor(private readonly storeValues: StoreValues){}
+
+  get replayProgress(): ReplayProgress {
+    return this.storeValues.replayProgress;
+  }
 }
 
 export class AppValuesBroker implements AppValues {
@@ -227,6 +272,10 @@ export class AppValuesBroker implements AppValues {
   get pageToShow(): Page {
     return this.storeValues.pageToShow;
   };
+
+  get replayProgress(): ReplayProgress | null {
+    return this.storeValues.replayProgress;
+  }
   
   get gameValues(): GameValues {
     return new GameValuesBroker(this.storeValues);
@@ -235,4 +284,12 @@ export class AppValuesBroker implements AppValues {
   get partyRoomValues(): PartyRoomValues {
     return new PartyRoomValuesBroker(this.storeValues);
   }
+
+  get chatValues(): ChatValues {
+    return new ChatValuesBroker(this.storeValues);
+  }
+
+  get replayControlsValues(): ReplayControlsValues {
+    return new ReplayControlsValuesBroker(this.storeValues);
+  }
 }
\ No newline at end of file
@@ -0,0 +1,85 @@
+package analysis
+
+import "errors"
+
+var errNoConsistentAssignment = errors.New("no allegiance assignment is consistent with the history")
+
+type Mission struct {
+	Team    []string
+	NbFails int
+}
+
+type Analysis struct {
+	SpyProbabilities      map[string]float64 `json:"spyProbabilities"`
+	ConsistentAssignments int                `json:"consistentAssignments"`
+}
+
+func Compute(players []string, nbSpies int, missions []Mission, knownResistance []string) (Analysis, error) {
+	excluded := make(map[string]bool)
+	for _, name := range knownResistance {
+		excluded[name] = true
+	}
+
+	spyCounts := make(map[string]int)
+	consistentAssignments := 0
+	forEachAssignment(players, nbSpies, func(spies map[string]bool) {
+		for name := range spies {
+			if excluded[name] {
+				return
+			}
+		}
+		if !isConsistent(spies, missions) {
+			return
+		}
+		consistentAssignments += 1
+		for name := range spies {
+			spyCounts[name] += 1
+		}
+	})
+
+	if consistentAssignments == 0 {
+		return Analysis{}, errNoConsistentAssignment
+	}
+
+	probabilities := make(map[string]float64, len(players))
+	for _, name := range players {
+		probabilities[name] = float64(spyCounts[name]) / float64(consistentAssignments)
+	}
+
+	return Analysis{
+		SpyProbabilities:      probabilities,
+		ConsistentAssignments: consistentAssignments,
+	}, nil
+}
+
+func isConsistent(spies map[string]bool, missions []Mission) bool {
+	for _, mission := range missions {
+		spiesOnTeam := 0
+		for _, member := range mission.Team {
+			if spies[member] {
+				spiesOnTeam += 1
+			}
+		}
+		if spiesOnTeam < mission.NbFails {
+			return false
+		}
+	}
+	return true
+}
+
+func forEachAssignment(players []string, nbSpies int, visit func(spies map[string]bool)) {
+	spies := make(map[string]bool, nbSpies)
+	var choose func(start int)
+	choose = func(start int) {
+		if len(spies) == nbSpies {
+			visit(spies)
+			return
+		}
+		for i := start; i <= len(players)-(nbSpies-len(spies)); i++ {
+			spies[players[i]] = true
+			choose(i + 1)
+			delete(spies, players[i])
+		}
+	}
+	choose(0)
+}
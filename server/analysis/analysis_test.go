@@ -0,0 +1,69 @@
+package analysis
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+var fivePlayers = []string{"Alice", "Bob", "Charlie", "Dan", "Edith"}
+
+func Test_Compute_NoHistory(t *testing.T) {
+	analysis, err := Compute(fivePlayers, 2, nil, nil)
+
+	g := NewWithT(t)
+	g.Expect(err).To(BeNil())
+	g.Expect(analysis).To(Equal(Analysis{
+		SpyProbabilities: map[string]float64{
+			"Alice":   0.4,
+			"Bob":     0.4,
+			"Charlie": 0.4,
+			"Dan":     0.4,
+			"Edith":   0.4,
+		},
+		ConsistentAssignments: 10,
+	}))
+}
+
+func Test_Compute_FailedMission(t *testing.T) {
+	analysis, err := Compute(fivePlayers, 2, []Mission{{Team: []string{"Alice", "Bob"}, NbFails: 2}}, nil)
+
+	g := NewWithT(t)
+	g.Expect(err).To(BeNil())
+	g.Expect(analysis).To(Equal(Analysis{
+		SpyProbabilities: map[string]float64{
+			"Alice":   1,
+			"Bob":     1,
+			"Charlie": 0,
+			"Dan":     0,
+			"Edith":   0,
+		},
+		ConsistentAssignments: 1,
+	}))
+}
+
+func Test_Compute_KnownResistance(t *testing.T) {
+	analysis, err := Compute(fivePlayers, 2, []Mission{{Team: []string{"Alice", "Bob"}, NbFails: 1}}, []string{"Alice"})
+
+	g := NewWithT(t)
+	g.Expect(err).To(BeNil())
+	g.Expect(analysis.ConsistentAssignments).To(Equal(3))
+	g.Expect(analysis.SpyProbabilities["Alice"]).To(Equal(0.0))
+	g.Expect(analysis.SpyProbabilities["Bob"]).To(Equal(1.0))
+	g.Expect(analysis.SpyProbabilities["Charlie"]).To(BeNumerically("~", 1.0/3))
+}
+
+func Test_Compute_SuccessfulMissionsDontConstrain(t *testing.T) {
+	analysis, err := Compute(fivePlayers, 2, []Mission{{Team: []string{"Alice", "Bob"}, NbFails: 0}}, nil)
+
+	g := NewWithT(t)
+	g.Expect(err).To(BeNil())
+	g.Expect(analysis.ConsistentAssignments).To(Equal(10))
+}
+
+func Test_Compute_NoConsistentAssignment(t *testing.T) {
+	_, err := Compute(fivePlayers, 2, []Mission{{Team: []string{"Alice", "Bob"}, NbFails: 2}}, []string{"Alice"})
+
+	g := NewWithT(t)
+	g.Expect(err).To(Equal(errNoConsistentAssignment))
+}
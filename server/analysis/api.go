@@ -0,0 +1,60 @@
+package analysis
+
+import (
+	"github.com/damien-springuel/bomb-canary/server/sessions"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	playerNameKey = "playerName"
+)
+
+type sessionGetter interface {
+	Get(session string) (name string, err error)
+}
+
+type analyzer interface {
+	Analyze(viewer string) (Analysis, error)
+}
+
+type analysisServer struct {
+	sessionGetter sessionGetter
+	analyzer      analyzer
+}
+
+func Register(engine *gin.Engine, sessionGetter sessionGetter, analyzer analyzer) {
+	analysisServer := analysisServer{
+		sessionGetter: sessionGetter,
+		analyzer:      analyzer,
+	}
+
+	engine.GET("/game/analysis", analysisServer.checkSession, analysisServer.analyze)
+}
+
+func (a analysisServer) checkSession(c *gin.Context) {
+	session, err := sessions.FromRequest(c.Request)
+	if err != nil {
+		c.AbortWithStatus(401)
+		return
+	}
+
+	playerName, err := a.sessionGetter.Get(session)
+	if err != nil {
+		c.AbortWithStatus(403)
+		return
+	}
+
+	c.Set(playerNameKey, playerName)
+
+	c.Next()
+}
+
+func (a analysisServer) analyze(c *gin.Context) {
+	analysis, err := a.analyzer.Analyze(c.GetString(playerNameKey))
+	if err != nil {
+		c.AbortWithStatusJSON(409, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, analysis)
+}
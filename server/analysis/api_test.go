@@ -0,0 +1,99 @@
+package analysis
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/gomega"
+)
+
+type mockSessionGetter struct {
+	receivedSession string
+	getError        error
+}
+
+func (m *mockSessionGetter) Get(session string) (name string, err error) {
+	m.receivedSession = session
+	return "testName", m.getError
+}
+
+type mockAnalyzer struct {
+	receivedViewer string
+	analyzeError   error
+}
+
+func (m *mockAnalyzer) Analyze(viewer string) (Analysis, error) {
+	m.receivedViewer = viewer
+	return Analysis{SpyProbabilities: map[string]float64{"testName": 0.5}, ConsistentAssignments: 2}, m.analyzeError
+}
+
+func makeCall(req *http.Request, sessionGetter *mockSessionGetter, analyzer *mockAnalyzer) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	ginEngine := gin.New()
+	Register(ginEngine, sessionGetter, analyzer)
+
+	w := httptest.NewRecorder()
+	ginEngine.ServeHTTP(w, req)
+	return w
+}
+
+func Test_Analysis(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/game/analysis", nil)
+	req.AddCookie(&http.Cookie{Name: "session", Value: "testSession"})
+	sessionGetter := &mockSessionGetter{}
+	analyzer := &mockAnalyzer{}
+	w := makeCall(req, sessionGetter, analyzer)
+
+	g := NewWithT(t)
+	g.Expect(w.Code).To(Equal(200))
+	g.Expect(w.Body.String()).To(Equal(`{"spyProbabilities":{"testName":0.5},"consistentAssignments":2}`))
+	g.Expect(sessionGetter.receivedSession).To(Equal("testSession"))
+	g.Expect(analyzer.receivedViewer).To(Equal("testName"))
+}
+
+func Test_Analysis_AcceptsBearerToken(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/game/analysis", nil)
+	req.Header.Set("Authorization", "Bearer testSession")
+	sessionGetter := &mockSessionGetter{}
+	analyzer := &mockAnalyzer{}
+	w := makeCall(req, sessionGetter, analyzer)
+
+	g := NewWithT(t)
+	g.Expect(w.Code).To(Equal(200))
+	g.Expect(sessionGetter.receivedSession).To(Equal("testSession"))
+	g.Expect(analyzer.receivedViewer).To(Equal("testName"))
+}
+
+func Test_Analysis_Returns401IfNoSessionCookie(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/game/analysis", nil)
+	analyzer := &mockAnalyzer{}
+	w := makeCall(req, &mockSessionGetter{}, analyzer)
+
+	g := NewWithT(t)
+	g.Expect(w.Code).To(Equal(401))
+	g.Expect(analyzer.receivedViewer).To(BeEmpty())
+}
+
+func Test_Analysis_Returns403IfSessionInvalid(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/game/analysis", nil)
+	req.AddCookie(&http.Cookie{Name: "session", Value: "testSession"})
+	analyzer := &mockAnalyzer{}
+	w := makeCall(req, &mockSessionGetter{getError: errors.New("invalid")}, analyzer)
+
+	g := NewWithT(t)
+	g.Expect(w.Code).To(Equal(403))
+	g.Expect(analyzer.receivedViewer).To(BeEmpty())
+}
+
+func Test_Analysis_Returns409IfAnalysisUnavailable(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/game/analysis", nil)
+	req.AddCookie(&http.Cookie{Name: "session", Value: "testSession"})
+	w := makeCall(req, &mockSessionGetter{}, &mockAnalyzer{analyzeError: errGameNotStarted})
+
+	g := NewWithT(t)
+	g.Expect(w.Code).To(Equal(409))
+	g.Expect(w.Body.String()).To(Equal(`{"error":"game hasn't started"}`))
+}
@@ -0,0 +1,84 @@
+package analysis
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/damien-springuel/bomb-canary/server/messagebus"
+)
+
+var errGameNotStarted = errors.New("game hasn't started")
+
+type tracker struct {
+	mut         *sync.RWMutex
+	players     []string
+	allegiances map[string]messagebus.Allegiance
+	nbSpies     int
+	team        []string
+	missions    []Mission
+}
+
+func NewTracker() *tracker {
+	return &tracker{
+		mut:         &sync.RWMutex{},
+		allegiances: make(map[string]messagebus.Allegiance),
+	}
+}
+
+func (t *tracker) Consume(m messagebus.Message) {
+	t.mut.Lock()
+	defer t.mut.Unlock()
+
+	switch m := m.(type) {
+	case messagebus.PlayerJoined:
+		t.players = append(t.players, m.Player)
+
+	case messagebus.RematchStarted:
+		t.players = append([]string{}, m.Players...)
+		t.allegiances = make(map[string]messagebus.Allegiance)
+		t.nbSpies = 0
+		t.team = nil
+		t.missions = nil
+
+	case messagebus.AllegianceRevealed:
+		for name, allegiance := range m.AllegianceByPlayer {
+			t.allegiances[name] = allegiance
+			if allegiance == messagebus.Spy {
+				t.nbSpies += 1
+			}
+		}
+
+	case messagebus.LeaderStartedToSelectMembers:
+		t.team = nil
+
+	case messagebus.LeaderSelectedMember:
+		t.team = append(t.team, m.SelectedMember)
+
+	case messagebus.LeaderDeselectedMember:
+		for i, member := range t.team {
+			if member == m.DeselectedMember {
+				t.team = append(t.team[:i:i], t.team[i+1:]...)
+				break
+			}
+		}
+
+	case messagebus.MissionCompleted:
+		t.missions = append(t.missions, Mission{Team: t.team, NbFails: m.Outcomes[false]})
+	}
+}
+
+func (t *tracker) Analyze(viewer string) (Analysis, error) {
+	t.mut.RLock()
+	defer t.mut.RUnlock()
+
+	if t.nbSpies == 0 {
+		return Analysis{}, errGameNotStarted
+	}
+
+	knownResistance := []string{}
+	if t.allegiances[viewer] == messagebus.Resistance {
+		knownResistance = append(knownResistance, viewer)
+	}
+
+	return Compute(t.players, t.nbSpies, t.missions, knownResistance)
+}
@@ -0,0 +1,103 @@
+package analysis
+
+import (
+	"testing"
+
+	"github.com/damien-springuel/bomb-canary/server/messagebus"
+	. "github.com/onsi/gomega"
+)
+
+func startedGameTracker() *tracker {
+	tracker := NewTracker()
+	for _, name := range fivePlayers {
+		tracker.Consume(messagebus.PlayerJoined{Player: name})
+	}
+	tracker.Consume(messagebus.AllegianceRevealed{AllegianceByPlayer: map[string]messagebus.Allegiance{
+		"Alice":   messagebus.Spy,
+		"Bob":     messagebus.Spy,
+		"Charlie": messagebus.Resistance,
+		"Dan":     messagebus.Resistance,
+		"Edith":   messagebus.Resistance,
+	}})
+	tracker.Consume(messagebus.LeaderStartedToSelectMembers{Leader: "Alice"})
+	return tracker
+}
+
+func Test_Tracker_ErrorsIfGameNotStarted(t *testing.T) {
+	tracker := NewTracker()
+	tracker.Consume(messagebus.PlayerJoined{Player: "Alice"})
+
+	_, err := tracker.Analyze("Alice")
+
+	g := NewWithT(t)
+	g.Expect(err).To(Equal(errGameNotStarted))
+}
+
+func Test_Tracker_AnalyzeFromMissionHistory(t *testing.T) {
+	tracker := startedGameTracker()
+	tracker.Consume(messagebus.LeaderSelectedMember{SelectedMember: "Alice"})
+	tracker.Consume(messagebus.LeaderSelectedMember{SelectedMember: "Charlie"})
+	tracker.Consume(messagebus.LeaderDeselectedMember{DeselectedMember: "Charlie"})
+	tracker.Consume(messagebus.LeaderSelectedMember{SelectedMember: "Bob"})
+	tracker.Consume(messagebus.MissionCompleted{Success: false, Outcomes: map[bool]int{false: 2}})
+	tracker.Consume(messagebus.LeaderStartedToSelectMembers{Leader: "Bob"})
+
+	analysis, err := tracker.Analyze("Charlie")
+
+	g := NewWithT(t)
+	g.Expect(err).To(BeNil())
+	g.Expect(analysis.ConsistentAssignments).To(Equal(1))
+	g.Expect(analysis.SpyProbabilities).To(Equal(map[string]float64{
+		"Alice":   1,
+		"Bob":     1,
+		"Charlie": 0,
+		"Dan":     0,
+		"Edith":   0,
+	}))
+}
+
+func Test_Tracker_ResistanceViewerExcludesThemselves(t *testing.T) {
+	tracker := startedGameTracker()
+
+	analysis, _ := tracker.Analyze("Charlie")
+
+	g := NewWithT(t)
+	g.Expect(analysis.ConsistentAssignments).To(Equal(6))
+	g.Expect(analysis.SpyProbabilities["Charlie"]).To(Equal(0.0))
+	g.Expect(analysis.SpyProbabilities["Alice"]).To(Equal(0.5))
+}
+
+func Test_Tracker_SpyViewerSeesPublicAnalysis(t *testing.T) {
+	tracker := startedGameTracker()
+
+	analysis, _ := tracker.Analyze("Alice")
+
+	g := NewWithT(t)
+	g.Expect(analysis.ConsistentAssignments).To(Equal(10))
+	g.Expect(analysis.SpyProbabilities["Alice"]).To(Equal(0.4))
+}
+
+func Test_Tracker_RematchStartsFromAFreshHistory(t *testing.T) {
+	tracker := startedGameTracker()
+	tracker.Consume(messagebus.LeaderSelectedMember{SelectedMember: "Alice"})
+	tracker.Consume(messagebus.LeaderSelectedMember{SelectedMember: "Bob"})
+	tracker.Consume(messagebus.MissionCompleted{Success: false, Outcomes: map[bool]int{false: 2}})
+
+	tracker.Consume(messagebus.RematchStarted{Players: []string{"Bob", "Charlie", "Dan", "Edith", "Alice"}})
+	_, err := tracker.Analyze("Charlie")
+
+	g := NewWithT(t)
+	g.Expect(err).To(Equal(errGameNotStarted))
+
+	tracker.Consume(messagebus.AllegianceRevealed{AllegianceByPlayer: map[string]messagebus.Allegiance{
+		"Alice":   messagebus.Resistance,
+		"Bob":     messagebus.Spy,
+		"Charlie": messagebus.Spy,
+		"Dan":     messagebus.Resistance,
+		"Edith":   messagebus.Resistance,
+	}})
+	analysis, err := tracker.Analyze("Dan")
+	g.Expect(err).To(BeNil())
+	g.Expect(analysis.SpyProbabilities["Dan"]).To(Equal(0.0))
+	g.Expect(analysis.SpyProbabilities["Alice"]).To(Equal(0.5))
+}
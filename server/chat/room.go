@@ -0,0 +1,176 @@
+package chat
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/damien-springuel/bomb-canary/server/messagebus"
+)
+
+const (
+	MaxMessageLength     = 500
+	defaultMessagesLimit = 5
+	defaultLimitWindow   = 10 * time.Second
+)
+
+var (
+	errEmptyMessage       = errors.New("message is required")
+	errMessageTooLong     = fmt.Errorf("message can't be longer than %d characters", MaxMessageLength)
+	errTooManyMessages    = errors.New("too many messages, slow down")
+	errUnknownChannel     = errors.New("unknown chat channel")
+	errSpyChannelDisabled = errors.New("spy channel is disabled")
+	errNotASpy            = errors.New("only spies can use the spy channel")
+	errSpyChannelClosed   = errors.New("spy channel is closed")
+)
+
+type messageDispatcher interface {
+	Dispatch(m messagebus.Message)
+}
+
+type room struct {
+	messageDispatcher messageDispatcher
+	now               func() time.Time
+	messagesLimit     int
+	limitWindow       time.Duration
+	sentAtByPlayer    map[string][]time.Time
+	spyChannelEnabled bool
+	spyChannelOpen    bool
+	spies             []string
+	spyMessages       []messagebus.ChatMessageSent
+}
+
+func NewRoom(messageDispatcher messageDispatcher, spyChannelEnabled bool) *room {
+	return &room{
+		messageDispatcher: messageDispatcher,
+		now:               time.Now,
+		messagesLimit:     defaultMessagesLimit,
+		limitWindow:       defaultLimitWindow,
+		sentAtByPlayer:    make(map[string][]time.Time),
+		spyChannelEnabled: spyChannelEnabled,
+	}
+}
+
+func Validate(message string) error {
+	message = strings.TrimSpace(message)
+	if message == "" {
+		return errEmptyMessage
+	}
+	if utf8.RuneCountInString(message) > MaxMessageLength {
+		return errMessageTooLong
+	}
+	return nil
+}
+
+func (r *room) Consume(m messagebus.Message) {
+	switch m := m.(type) {
+	case messagebus.AllegianceRevealed:
+		r.openSpyChannel(m.AllegianceByPlayer)
+	case messagebus.GameEnded:
+		r.revealSpyChat()
+	case messagebus.SendChatMessage:
+		r.sendChatMessage(m)
+	}
+}
+
+func (r *room) openSpyChannel(allegianceByPlayer map[string]messagebus.Allegiance) {
+	if !r.spyChannelEnabled {
+		return
+	}
+
+	r.spies = []string{}
+	for name, allegiance := range allegianceByPlayer {
+		if allegiance == messagebus.Spy {
+			r.spies = append(r.spies, name)
+		}
+	}
+	sort.Strings(r.spies)
+	r.spyChannelOpen = true
+
+	r.messageDispatcher.Dispatch(messagebus.SpyChannelOpened{Spies: r.spies})
+}
+
+func (r *room) revealSpyChat() {
+	if !r.spyChannelOpen {
+		return
+	}
+
+	r.spyChannelOpen = false
+	if len(r.spyMessages) > 0 {
+		r.messageDispatcher.Dispatch(messagebus.SpyChatRevealed{Messages: r.spyMessages})
+	}
+	r.spyMessages = nil
+}
+
+func (r *room) sendChatMessage(sendChatMessage messagebus.SendChatMessage) {
+	now := r.now()
+	err := Validate(sendChatMessage.Message)
+	if err == nil {
+		err = r.checkChannel(sendChatMessage.Player, sendChatMessage.Channel)
+	}
+	if err == nil {
+		err = r.checkRate(sendChatMessage.Player, now)
+	}
+	if err != nil {
+		r.messageDispatcher.Dispatch(messagebus.ChatMessageRejected{
+			Player: sendChatMessage.Player,
+			Reason: err.Error(),
+		})
+		return
+	}
+
+	chatMessageSent := messagebus.ChatMessageSent{
+		Player:  sendChatMessage.Player,
+		Channel: sendChatMessage.Channel,
+		Message: strings.TrimSpace(sendChatMessage.Message),
+		SentAt:  now,
+	}
+	if sendChatMessage.Channel == messagebus.SpyChannel {
+		chatMessageSent.Recipients = r.spies
+		r.spyMessages = append(r.spyMessages, chatMessageSent)
+	}
+
+	r.messageDispatcher.Dispatch(chatMessageSent)
+}
+
+func (r *room) checkChannel(player string, channel messagebus.ChatChannel) error {
+	switch channel {
+	case messagebus.PublicChannel:
+		return nil
+	case messagebus.SpyChannel:
+		if !r.spyChannelEnabled {
+			return errSpyChannelDisabled
+		}
+		if !r.spyChannelOpen {
+			return errSpyChannelClosed
+		}
+		for _, spy := range r.spies {
+			if spy == player {
+				return nil
+			}
+		}
+		return errNotASpy
+	default:
+		return errUnknownChannel
+	}
+}
+
+func (r *room) checkRate(player string, now time.Time) error {
+	recent := []time.Time{}
+	for _, sentAt := range r.sentAtByPlayer[player] {
+		if now.Sub(sentAt) < r.limitWindow {
+			recent = append(recent, sentAt)
+		}
+	}
+
+	if len(recent) >= r.messagesLimit {
+		r.sentAtByPlayer[player] = recent
+		return errTooManyMessages
+	}
+
+	r.sentAtByPlayer[player] = append(recent, now)
+	return nil
+}
@@ -0,0 +1,226 @@
+package chat
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/damien-springuel/bomb-canary/server/messagebus"
+	. "github.com/onsi/gomega"
+)
+
+type mockDispatcher struct {
+	receivedMessages []messagebus.Message
+}
+
+func (m *mockDispatcher) Dispatch(message messagebus.Message) {
+	m.receivedMessages = append(m.receivedMessages, message)
+}
+
+type fakeClock struct {
+	current time.Time
+}
+
+func (f *fakeClock) now() time.Time {
+	return f.current
+}
+
+func setupRoomWithSpyChannel(spyChannelEnabled bool) (*mockDispatcher, *fakeClock, *room) {
+	dispatcher := &mockDispatcher{}
+	clock := &fakeClock{current: time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)}
+	r := NewRoom(dispatcher, spyChannelEnabled)
+	r.now = clock.now
+	return dispatcher, clock, r
+}
+
+func setupRoom() (*mockDispatcher, *fakeClock, *room) {
+	return setupRoomWithSpyChannel(false)
+}
+
+func revealAllegiances(r *room) {
+	r.Consume(messagebus.AllegianceRevealed{AllegianceByPlayer: map[string]messagebus.Allegiance{
+		"Alice":   messagebus.Spy,
+		"Bob":     messagebus.Resistance,
+		"Charlie": messagebus.Spy,
+		"Dan":     messagebus.Resistance,
+		"Edith":   messagebus.Resistance,
+	}})
+}
+
+func Test_SendChatMessage(t *testing.T) {
+	dispatcher, clock, r := setupRoom()
+	r.Consume(messagebus.SendChatMessage{Player: "Alice", Channel: messagebus.PublicChannel, Message: "  it's Bob  "})
+
+	g := NewWithT(t)
+	g.Expect(dispatcher.receivedMessages).To(Equal([]messagebus.Message{
+		messagebus.ChatMessageSent{Player: "Alice", Channel: messagebus.PublicChannel, Message: "it's Bob", SentAt: clock.current},
+	}))
+}
+
+func Test_SendChatMessage_IgnoresOtherMessages(t *testing.T) {
+	dispatcher, _, r := setupRoom()
+	r.Consume(messagebus.PlayerJoined{Player: "Alice"})
+
+	g := NewWithT(t)
+	g.Expect(dispatcher.receivedMessages).To(BeEmpty())
+}
+
+func Test_SendChatMessage_RejectsEmptyAndTooLongMessages(t *testing.T) {
+	dispatcher, _, r := setupRoom()
+	r.Consume(messagebus.SendChatMessage{Player: "Alice", Channel: messagebus.PublicChannel, Message: "   "})
+	r.Consume(messagebus.SendChatMessage{Player: "Alice", Channel: messagebus.PublicChannel, Message: strings.Repeat("é", MaxMessageLength+1)})
+
+	g := NewWithT(t)
+	g.Expect(dispatcher.receivedMessages).To(Equal([]messagebus.Message{
+		messagebus.ChatMessageRejected{Player: "Alice", Reason: "message is required"},
+		messagebus.ChatMessageRejected{Player: "Alice", Reason: "message can't be longer than 500 characters"},
+	}))
+}
+
+func Test_SendChatMessage_AcceptsMessageAtMaxLength(t *testing.T) {
+	dispatcher, _, r := setupRoom()
+	r.Consume(messagebus.SendChatMessage{Player: "Alice", Channel: messagebus.PublicChannel, Message: strings.Repeat("é", MaxMessageLength)})
+
+	g := NewWithT(t)
+	g.Expect(dispatcher.receivedMessages).To(HaveLen(1))
+	g.Expect(dispatcher.receivedMessages[0]).To(BeAssignableToTypeOf(messagebus.ChatMessageSent{}))
+}
+
+func Test_SendChatMessage_RateLimitsPerPlayer(t *testing.T) {
+	dispatcher, clock, r := setupRoom()
+	for i := 0; i < defaultMessagesLimit; i++ {
+		r.Consume(messagebus.SendChatMessage{Player: "Alice", Channel: messagebus.PublicChannel, Message: "spam"})
+		clock.current = clock.current.Add(time.Second)
+	}
+	dispatcher.receivedMessages = nil
+
+	r.Consume(messagebus.SendChatMessage{Player: "Alice", Channel: messagebus.PublicChannel, Message: "spam"})
+	r.Consume(messagebus.SendChatMessage{Player: "Bob", Channel: messagebus.PublicChannel, Message: "hello"})
+
+	g := NewWithT(t)
+	g.Expect(dispatcher.receivedMessages).To(Equal([]messagebus.Message{
+		messagebus.ChatMessageRejected{Player: "Alice", Reason: "too many messages, slow down"},
+		messagebus.ChatMessageSent{Player: "Bob", Channel: messagebus.PublicChannel, Message: "hello", SentAt: clock.current},
+	}))
+}
+
+func Test_SendChatMessage_RateLimitSlidesWithTime(t *testing.T) {
+	dispatcher, clock, r := setupRoom()
+	for i := 0; i < defaultMessagesLimit; i++ {
+		r.Consume(messagebus.SendChatMessage{Player: "Alice", Channel: messagebus.PublicChannel, Message: "spam"})
+	}
+	clock.current = clock.current.Add(defaultLimitWindow)
+	dispatcher.receivedMessages = nil
+
+	r.Consume(messagebus.SendChatMessage{Player: "Alice", Channel: messagebus.PublicChannel, Message: "again"})
+
+	g := NewWithT(t)
+	g.Expect(dispatcher.receivedMessages).To(Equal([]messagebus.Message{
+		messagebus.ChatMessageSent{Player: "Alice", Channel: messagebus.PublicChannel, Message: "again", SentAt: clock.current},
+	}))
+}
+
+func Test_SendChatMessage_RejectsUnknownChannel(t *testing.T) {
+	dispatcher, _, r := setupRoom()
+	r.Consume(messagebus.SendChatMessage{Player: "Alice", Channel: "secret", Message: "hi"})
+
+	g := NewWithT(t)
+	g.Expect(dispatcher.receivedMessages).To(Equal([]messagebus.Message{
+		messagebus.ChatMessageRejected{Player: "Alice", Reason: "unknown chat channel"},
+	}))
+}
+
+func Test_SpyChannel_DisabledByDefault(t *testing.T) {
+	dispatcher, _, r := setupRoom()
+	revealAllegiances(r)
+	r.Consume(messagebus.SendChatMessage{Player: "Alice", Channel: messagebus.SpyChannel, Message: "hi"})
+
+	g := NewWithT(t)
+	g.Expect(dispatcher.receivedMessages).To(Equal([]messagebus.Message{
+		messagebus.ChatMessageRejected{Player: "Alice", Reason: "spy channel is disabled"},
+	}))
+}
+
+func Test_SpyChannel_OpensWhenAllegiancesAreRevealed(t *testing.T) {
+	dispatcher, _, r := setupRoomWithSpyChannel(true)
+	revealAllegiances(r)
+
+	g := NewWithT(t)
+	g.Expect(dispatcher.receivedMessages).To(Equal([]messagebus.Message{
+		messagebus.SpyChannelOpened{Spies: []string{"Alice", "Charlie"}},
+	}))
+}
+
+func Test_SpyChannel_SendsOnlyToSpies(t *testing.T) {
+	dispatcher, clock, r := setupRoomWithSpyChannel(true)
+	revealAllegiances(r)
+	dispatcher.receivedMessages = nil
+
+	r.Consume(messagebus.SendChatMessage{Player: "Charlie", Channel: messagebus.SpyChannel, Message: "fail the second one"})
+
+	g := NewWithT(t)
+	g.Expect(dispatcher.receivedMessages).To(Equal([]messagebus.Message{
+		messagebus.ChatMessageSent{
+			Player:     "Charlie",
+			Channel:    messagebus.SpyChannel,
+			Recipients: []string{"Alice", "Charlie"},
+			Message:    "fail the second one",
+			SentAt:     clock.current,
+		},
+	}))
+}
+
+func Test_SpyChannel_RejectsNonSpiesAndMessagesBeforeReveal(t *testing.T) {
+	dispatcher, _, r := setupRoomWithSpyChannel(true)
+	r.Consume(messagebus.SendChatMessage{Player: "Alice", Channel: messagebus.SpyChannel, Message: "too early"})
+	revealAllegiances(r)
+	dispatcher.receivedMessages = nil
+	r.Consume(messagebus.SendChatMessage{Player: "Bob", Channel: messagebus.SpyChannel, Message: "let me in"})
+
+	g := NewWithT(t)
+	g.Expect(dispatcher.receivedMessages).To(Equal([]messagebus.Message{
+		messagebus.ChatMessageRejected{Player: "Bob", Reason: "only spies can use the spy channel"},
+	}))
+}
+
+func Test_SpyChannel_RevealedAndClosedWhenGameEnds(t *testing.T) {
+	dispatcher, clock, r := setupRoomWithSpyChannel(true)
+	r.Consume(messagebus.SendChatMessage{Player: "Alice", Channel: messagebus.SpyChannel, Message: "too early"})
+	revealAllegiances(r)
+	r.Consume(messagebus.SendChatMessage{Player: "Alice", Channel: messagebus.SpyChannel, Message: "fail it"})
+	firstSentAt := clock.current
+	clock.current = clock.current.Add(time.Minute)
+	r.Consume(messagebus.SendChatMessage{Player: "Charlie", Channel: messagebus.SpyChannel, Message: "ok"})
+	dispatcher.receivedMessages = nil
+
+	r.Consume(messagebus.GameEnded{Winner: messagebus.Spy, Spies: []string{"Alice", "Charlie"}})
+	r.Consume(messagebus.SendChatMessage{Player: "Alice", Channel: messagebus.SpyChannel, Message: "gg"})
+
+	g := NewWithT(t)
+	g.Expect(dispatcher.receivedMessages).To(Equal([]messagebus.Message{
+		messagebus.SpyChatRevealed{Messages: []messagebus.ChatMessageSent{
+			{Player: "Alice", Channel: messagebus.SpyChannel, Recipients: []string{"Alice", "Charlie"}, Message: "fail it", SentAt: firstSentAt},
+			{Player: "Charlie", Channel: messagebus.SpyChannel, Recipients: []string{"Alice", "Charlie"}, Message: "ok", SentAt: clock.current},
+		}},
+		messagebus.ChatMessageRejected{Player: "Alice", Reason: "spy channel is closed"},
+	}))
+}
+
+func Test_SpyChannel_RevealsOnlyTheLastGameMessages(t *testing.T) {
+	dispatcher, clock, r := setupRoomWithSpyChannel(true)
+	revealAllegiances(r)
+	r.Consume(messagebus.SendChatMessage{Player: "Alice", Channel: messagebus.SpyChannel, Message: "fail it"})
+	r.Consume(messagebus.GameEnded{Winner: messagebus.Spy, Spies: []string{"Alice", "Charlie"}})
+
+	revealAllegiances(r)
+	r.Consume(messagebus.SendChatMessage{Player: "Charlie", Channel: messagebus.SpyChannel, Message: "again"})
+	dispatcher.receivedMessages = nil
+	r.Consume(messagebus.GameEnded{Winner: messagebus.Spy, Spies: []string{"Alice", "Charlie"}})
+
+	g := NewWithT(t)
+	g.Expect(dispatcher.receivedMessages).To(Equal([]messagebus.Message{
+		messagebus.SpyChatRevealed{Messages: []messagebus.ChatMessageSent{
+			{Player: "Charlie", Channel: messagebus.SpyChannel, Recipients: []string{"Alice", "Charlie"}, Message: "again", SentAt: clock.current},
+		}},
+	}))
+}
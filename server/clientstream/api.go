@@ -2,7 +2,12 @@ package clientstream
 
 import (
 	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
 
+	"github.com/damien-springuel/bomb-canary/server/sessions"
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
 )
@@ -10,9 +15,25 @@ import (
 const (
 	outKey             = "out"
 	connClosedKey      = "connClosed"
+	inboundKey         = "inbound"
+	playerNameKey      = "playerName"
 	websocketWriterKey = "websocketWriter"
 )
 
+type Heartbeat struct {
+	PingInterval time.Duration
+	PongTimeout  time.Duration
+	WriteTimeout time.Duration
+}
+
+func DefaultHeartbeat() Heartbeat {
+	return Heartbeat{
+		PingInterval: 20 * time.Second,
+		PongTimeout:  30 * time.Second,
+		WriteTimeout: 10 * time.Second,
+	}
+}
+
 type websocketWriter func(messageType int, data []byte) error
 
 type sessionGetter interface {
@@ -20,52 +41,145 @@ type sessionGetter interface {
 }
 
 type clientBroker interface {
-	Add(name string) (chan []byte, func())
+	Add(name string, since int) (chan []byte, func())
+}
+
+type actionHandler interface {
+	Handle(player string, message []byte) []byte
+}
+
+type statsGetter interface {
+	Stats() StreamStats
 }
 
 type clientStreamServer struct {
-	sessionGetter sessionGetter
-	clientBroker  clientBroker
+	sessionGetter  sessionGetter
+	clientBroker   clientBroker
+	actionHandler  actionHandler
+	heartbeat      Heartbeat
+	allowedOrigins []string
 }
 
-func Register(engine *gin.Engine, sessionGetter sessionGetter, clientBroker clientBroker) {
+func Register(engine *gin.Engine, sessionGetter sessionGetter, clientBroker clientBroker, actionHandler actionHandler, heartbeat Heartbeat, allowedOrigins []string) {
 
 	clientStream := clientStreamServer{
-		sessionGetter: sessionGetter,
-		clientBroker:  clientBroker,
+		sessionGetter:  sessionGetter,
+		clientBroker:   clientBroker,
+		actionHandler:  actionHandler,
+		heartbeat:      heartbeat,
+		allowedOrigins: allowedOrigins,
 	}
 
 	engine.GET("/events",
-		createWebsocketConnection,
+		clientStream.createWebsocketConnection,
 		clientStream.checkSession,
+		clientStream.handleActions,
 		clientStream.streamEvents,
 	)
+
+	engine.GET("/events/sse",
+		checkHttpSession(sessionGetter),
+		clientStream.streamServerSentEvents,
+	)
+}
+
+func (s clientStreamServer) isOriginAllowed(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+
+	originUrl, err := url.Parse(origin)
+	if err == nil && originUrl.Host == r.Host {
+		return true
+	}
+
+	for _, allowedOrigin := range s.allowedOrigins {
+		if allowedOrigin == "*" || allowedOrigin == origin {
+			return true
+		}
+	}
+	return false
 }
 
-func createWebsocketConnection(c *gin.Context) {
-	upgrader := websocket.Upgrader{CheckOrigin: func(r *http.Request) bool { return true }} // temporary while developing frontend
+func (s clientStreamServer) createWebsocketConnection(c *gin.Context) {
+	upgrader := websocket.Upgrader{
+		CheckOrigin:  func(r *http.Request) bool { return true },
+		Subprotocols: []string{sessions.WebsocketProtocol},
+	}
 	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
 		c.Abort()
 		return
 	}
+
+	if !s.isOriginAllowed(c.Request) {
+		_ = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(4403, "origin not allowed"))
+		_ = conn.Close()
+		c.Abort()
+		return
+	}
+
+	extendReadDeadline := func() {
+		_ = conn.SetReadDeadline(time.Now().Add(s.heartbeat.PongTimeout))
+	}
+	extendReadDeadline()
+	conn.SetPongHandler(func(string) error {
+		extendReadDeadline()
+		return nil
+	})
+
+	requestDone := c.Request.Context().Done()
+	inbound := make(chan []byte)
+	c.Set(inboundKey, inbound)
 	connClosed := make(chan bool)
 	c.Set(connClosedKey, connClosed)
 	go func() {
 		defer conn.Close()
 		for {
-			_, _, err := conn.ReadMessage()
+			_, message, err := conn.ReadMessage()
 			if err != nil {
 				break
 			}
+			extendReadDeadline()
+			select {
+			case inbound <- message:
+			case <-requestDone:
+			}
 		}
+		close(inbound)
 		close(connClosed)
 	}()
 
-	c.Set(websocketWriterKey, websocketWriter(conn.WriteMessage))
+	go s.sendPings(conn, connClosed)
+
+	writeMut := &sync.Mutex{}
+	c.Set(websocketWriterKey, websocketWriter(func(messageType int, data []byte) error {
+		writeMut.Lock()
+		defer writeMut.Unlock()
+		_ = conn.SetWriteDeadline(time.Now().Add(s.heartbeat.WriteTimeout))
+		return conn.WriteMessage(messageType, data)
+	}))
 	c.Next()
 }
 
+func (s clientStreamServer) sendPings(conn *websocket.Conn, connClosed chan bool) {
+	ticker := time.NewTicker(s.heartbeat.PingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-connClosed:
+			return
+		case <-ticker.C:
+			err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(s.heartbeat.WriteTimeout))
+			if err != nil {
+				_ = conn.Close()
+				return
+			}
+		}
+	}
+}
+
 func getWebsocketWriterFromContext(c *gin.Context) websocketWriter {
 	value, _ := c.Get(websocketWriterKey)
 	return value.(websocketWriter)
@@ -76,11 +190,16 @@ func getConnClosedFromContext(c *gin.Context) chan bool {
 	return value.(chan bool)
 }
 
+func getInboundFromContext(c *gin.Context) chan []byte {
+	value, _ := c.Get(inboundKey)
+	return value.(chan []byte)
+}
+
 func (s clientStreamServer) checkSession(c *gin.Context) {
 	writer := getWebsocketWriterFromContext(c)
-	session, err := c.Cookie("session")
+	session, err := sessions.FromWebsocketRequest(c.Request)
 	if err != nil {
-		_ = writer(websocket.CloseMessage, websocket.FormatCloseMessage(4401, "no session cookie"))
+		_ = writer(websocket.CloseMessage, websocket.FormatCloseMessage(4401, "no session"))
 		c.Abort()
 		return
 	}
@@ -91,7 +210,7 @@ func (s clientStreamServer) checkSession(c *gin.Context) {
 		c.Abort()
 		return
 	}
-	out, closeClientStream := s.clientBroker.Add(playerName)
+	out, closeClientStream := s.clientBroker.Add(playerName, parseSince(c.Query("since")))
 	go func() {
 		connClosed := getConnClosedFromContext(c)
 		<-connClosed
@@ -99,11 +218,35 @@ func (s clientStreamServer) checkSession(c *gin.Context) {
 	}()
 
 	c.Set(outKey, out)
+	c.Set(playerNameKey, playerName)
 
 	c.Next()
 	closeClientStream()
 }
 
+func (s clientStreamServer) handleActions(c *gin.Context) {
+	write := getWebsocketWriterFromContext(c)
+	inbound := getInboundFromContext(c)
+	playerName := c.GetString(playerNameKey)
+
+	go func() {
+		for message := range inbound {
+			reply := s.actionHandler.Handle(playerName, message)
+			_ = write(websocket.TextMessage, reply)
+		}
+	}()
+
+	c.Next()
+}
+
+func parseSince(value string) int {
+	since, err := strconv.Atoi(value)
+	if err != nil || since < 0 {
+		return 0
+	}
+	return since
+}
+
 func getOutFromContext(c *gin.Context) chan []byte {
 	out, _ := c.Get(outKey)
 	return out.(chan []byte)
@@ -114,6 +257,11 @@ func (s clientStreamServer) streamEvents(c *gin.Context) {
 	out := getOutFromContext(c)
 
 	for messageToSend := range out {
+		if len(messageToSend) == 0 {
+			_ = write(websocket.CloseMessage, websocket.FormatCloseMessage(4408, "too far behind, reconnect with since"))
+			c.Abort()
+			return
+		}
 		err := write(websocket.TextMessage, messageToSend)
 		if err != nil {
 			c.Abort()
@@ -123,3 +271,9 @@ func (s clientStreamServer) streamEvents(c *gin.Context) {
 
 	_ = write(websocket.CloseMessage, websocket.FormatCloseMessage(1000, ""))
 }
+
+func RegisterStats(engine *gin.Engine, statsGetter statsGetter) {
+	engine.GET("/metrics/streams", func(c *gin.Context) {
+		c.JSON(200, statsGetter.Stats())
+	})
+}
@@ -5,7 +5,9 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
@@ -23,25 +25,58 @@ func (m *mockSessionGetter) Get(session string) (name string, err error) {
 }
 
 type mockClientBroker struct {
+	mut             sync.Mutex
 	channelToReturn chan []byte
 	receivedName    string
+	receivedSince   int
 	closerCalled    bool
 }
 
-func (m *mockClientBroker) Add(name string) (chan []byte, func()) {
+func (m *mockClientBroker) Add(name string, since int) (chan []byte, func()) {
 	m.receivedName = name
+	m.receivedSince = since
 	return m.channelToReturn, func() {
+		m.mut.Lock()
+		defer m.mut.Unlock()
 		m.closerCalled = true
 	}
 }
 
+func (m *mockClientBroker) wasCloserCalled() bool {
+	m.mut.Lock()
+	defer m.mut.Unlock()
+	return m.closerCalled
+}
+
+type mockActionHandler struct {
+	mut              sync.Mutex
+	receivedPlayer   string
+	receivedMessages []string
+}
+
+func (m *mockActionHandler) Handle(player string, message []byte) []byte {
+	m.mut.Lock()
+	defer m.mut.Unlock()
+	m.receivedPlayer = player
+	m.receivedMessages = append(m.receivedMessages, string(message))
+	return []byte("ack " + string(message))
+}
+
 func setup(sessionGetter *mockSessionGetter, clientBroker *mockClientBroker, header http.Header) (*websocket.Conn, func()) {
+	return setupWithQuery(sessionGetter, clientBroker, header, "")
+}
+
+func setupWithQuery(sessionGetter *mockSessionGetter, clientBroker *mockClientBroker, header http.Header, query string) (*websocket.Conn, func()) {
+	return setupWithHeartbeat(sessionGetter, clientBroker, header, query, DefaultHeartbeat())
+}
+
+func setupWithHeartbeat(sessionGetter *mockSessionGetter, clientBroker *mockClientBroker, header http.Header, query string, heartbeat Heartbeat) (*websocket.Conn, func()) {
 	gin.SetMode(gin.TestMode)
 	ginEngine := gin.New()
-	Register(ginEngine, sessionGetter, clientBroker)
+	Register(ginEngine, sessionGetter, clientBroker, &mockActionHandler{}, heartbeat, []string{"http://allowed.test"})
 	s := httptest.NewServer(ginEngine)
 
-	url := "ws" + strings.TrimPrefix(s.URL, "http") + "/events"
+	url := "ws" + strings.TrimPrefix(s.URL, "http") + "/events" + query
 	ws, _, _ := websocket.DefaultDialer.Dial(url, header)
 
 	return ws, func() {
@@ -127,3 +162,256 @@ func Test_StreamEvents_CloseConnectionWith4403IfSessionIsInvalid(t *testing.T) {
 	g.Expect(sessionGetter.receivedSession).To(Equal("testSession"))
 	g.Expect(clientBroker.receivedName).To(BeEmpty())
 }
+
+func Test_StreamEvents_PassesSinceToClientBroker(t *testing.T) {
+	clientOut := make(chan []byte)
+	close(clientOut)
+	clientBroker := &mockClientBroker{channelToReturn: clientOut}
+
+	header := http.Header{}
+	header.Add("Cookie", "session=testSession")
+	conn, closer := setupWithQuery(&mockSessionGetter{}, clientBroker, header, "?since=42")
+	defer closer()
+
+	_, _, err := conn.ReadMessage()
+
+	g := NewWithT(t)
+	g.Expect(err).To(BeAssignableToTypeOf(&websocket.CloseError{}))
+	g.Expect(clientBroker.receivedSince).To(Equal(42))
+}
+
+func Test_StreamEvents_InvalidSinceMeansFullReplay(t *testing.T) {
+	clientOut := make(chan []byte)
+	close(clientOut)
+	clientBroker := &mockClientBroker{channelToReturn: clientOut}
+
+	header := http.Header{}
+	header.Add("Cookie", "session=testSession")
+	conn, closer := setupWithQuery(&mockSessionGetter{}, clientBroker, header, "?since=garbage")
+	defer closer()
+
+	_, _, err := conn.ReadMessage()
+
+	g := NewWithT(t)
+	g.Expect(err).To(BeAssignableToTypeOf(&websocket.CloseError{}))
+	g.Expect(clientBroker.receivedName).To(Equal("testName"))
+	g.Expect(clientBroker.receivedSince).To(Equal(0))
+}
+
+func Test_StreamEvents_CloseConnectionWith4408IfEvicted(t *testing.T) {
+	clientOut := make(chan []byte, 2)
+	clientOut <- []byte("m1")
+	clientOut <- evictedNotice
+	close(clientOut)
+	clientBroker := &mockClientBroker{channelToReturn: clientOut}
+
+	header := http.Header{}
+	header.Add("Cookie", "session=testSession")
+	conn, closer := setup(&mockSessionGetter{}, clientBroker, header)
+	defer closer()
+
+	g := NewWithT(t)
+	_, message, err := conn.ReadMessage()
+	g.Expect(err).To(BeNil())
+	g.Expect(message).To(Equal([]byte("m1")))
+
+	_, _, err = conn.ReadMessage()
+	closeError, ok := err.(*websocket.CloseError)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(closeError.Code).To(Equal(4408))
+}
+
+func Test_StreamEvents_SendsPingsAndKeepsConnectionAliveWhileClientAnswers(t *testing.T) {
+	clientBroker := &mockClientBroker{channelToReturn: make(chan []byte)}
+
+	header := http.Header{}
+	header.Add("Cookie", "session=testSession")
+	heartbeat := Heartbeat{PingInterval: 10 * time.Millisecond, PongTimeout: 50 * time.Millisecond, WriteTimeout: 50 * time.Millisecond}
+	conn, closer := setupWithHeartbeat(&mockSessionGetter{}, clientBroker, header, "", heartbeat)
+	defer closer()
+
+	pings := make(chan bool, 100)
+	conn.SetPingHandler(func(data string) error {
+		pings <- true
+		return conn.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(time.Second))
+	})
+	go func() {
+		for {
+			_, _, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+		}
+	}()
+
+	g := NewWithT(t)
+	g.Eventually(func() int { return len(pings) }).Should(BeNumerically(">=", 3))
+	g.Consistently(clientBroker.wasCloserCalled, 200*time.Millisecond).Should(BeFalse())
+}
+
+func Test_StreamEvents_ClosesClientStreamWhenClientStopsAnsweringPings(t *testing.T) {
+	clientBroker := &mockClientBroker{channelToReturn: make(chan []byte)}
+
+	header := http.Header{}
+	header.Add("Cookie", "session=testSession")
+	heartbeat := Heartbeat{PingInterval: 10 * time.Millisecond, PongTimeout: 50 * time.Millisecond, WriteTimeout: 50 * time.Millisecond}
+	_, closer := setupWithHeartbeat(&mockSessionGetter{}, clientBroker, header, "", heartbeat)
+	defer closer()
+
+	g := NewWithT(t)
+	g.Eventually(clientBroker.wasCloserCalled).Should(BeTrue())
+}
+
+func Test_StreamEvents_HandlesInboundActionsAndRepliesOnSameSocket(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ginEngine := gin.New()
+	actionHandler := &mockActionHandler{}
+	Register(ginEngine, &mockSessionGetter{}, &mockClientBroker{channelToReturn: make(chan []byte)}, actionHandler, DefaultHeartbeat(), nil)
+	s := httptest.NewServer(ginEngine)
+	defer s.Close()
+
+	header := http.Header{}
+	header.Add("Cookie", "session=testSession")
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(s.URL, "http")+"/events", header)
+	g := NewWithT(t)
+	g.Expect(err).To(BeNil())
+	defer conn.Close()
+
+	g.Expect(conn.WriteMessage(websocket.TextMessage, []byte("a1"))).To(Succeed())
+	g.Expect(conn.WriteMessage(websocket.TextMessage, []byte("a2"))).To(Succeed())
+
+	_, reply, err := conn.ReadMessage()
+	g.Expect(err).To(BeNil())
+	g.Expect(string(reply)).To(Equal("ack a1"))
+	_, reply, err = conn.ReadMessage()
+	g.Expect(err).To(BeNil())
+	g.Expect(string(reply)).To(Equal("ack a2"))
+
+	actionHandler.mut.Lock()
+	defer actionHandler.mut.Unlock()
+	g.Expect(actionHandler.receivedPlayer).To(Equal("testName"))
+	g.Expect(actionHandler.receivedMessages).To(Equal([]string{"a1", "a2"}))
+}
+
+func Test_StreamEvents_AcceptsConnectionFromAllowedOrigin(t *testing.T) {
+	clientOut := make(chan []byte, 1)
+	clientOut <- []byte("m1")
+	close(clientOut)
+	clientBroker := &mockClientBroker{channelToReturn: clientOut}
+
+	header := http.Header{}
+	header.Add("Cookie", "session=testSession")
+	header.Add("Origin", "http://allowed.test")
+	conn, closer := setup(&mockSessionGetter{}, clientBroker, header)
+	defer closer()
+
+	_, message, err := conn.ReadMessage()
+
+	g := NewWithT(t)
+	g.Expect(err).To(BeNil())
+	g.Expect(message).To(Equal([]byte("m1")))
+	g.Expect(clientBroker.receivedName).To(Equal("testName"))
+}
+
+func Test_StreamEvents_AcceptsConnectionFromSameOrigin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ginEngine := gin.New()
+	clientOut := make(chan []byte, 1)
+	clientOut <- []byte("m1")
+	close(clientOut)
+	Register(ginEngine, &mockSessionGetter{}, &mockClientBroker{channelToReturn: clientOut}, &mockActionHandler{}, DefaultHeartbeat(), nil)
+	s := httptest.NewServer(ginEngine)
+	defer s.Close()
+
+	header := http.Header{}
+	header.Add("Cookie", "session=testSession")
+	header.Add("Origin", s.URL)
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(s.URL, "http")+"/events", header)
+	g := NewWithT(t)
+	g.Expect(err).To(BeNil())
+	defer conn.Close()
+
+	_, message, err := conn.ReadMessage()
+	g.Expect(err).To(BeNil())
+	g.Expect(message).To(Equal([]byte("m1")))
+}
+
+func Test_StreamEvents_CloseConnectionWith4403IfOriginIsNotAllowed(t *testing.T) {
+	clientBroker := &mockClientBroker{}
+	sessionGetter := &mockSessionGetter{}
+
+	header := http.Header{}
+	header.Add("Cookie", "session=testSession")
+	header.Add("Origin", "http://evil.test")
+	conn, closer := setup(sessionGetter, clientBroker, header)
+	defer closer()
+
+	_, _, err := conn.ReadMessage()
+
+	g := NewWithT(t)
+	closeError, ok := err.(*websocket.CloseError)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(closeError.Code).To(Equal(4403))
+	g.Expect(closeError.Text).To(Equal("origin not allowed"))
+	g.Expect(sessionGetter.receivedSession).To(BeEmpty())
+	g.Expect(clientBroker.receivedName).To(BeEmpty())
+}
+
+func Test_StreamEvents_AcceptsTokenInWebsocketProtocol(t *testing.T) {
+	clientOut := make(chan []byte)
+	close(clientOut)
+	clientBroker := &mockClientBroker{channelToReturn: clientOut}
+	sessionGetter := &mockSessionGetter{}
+
+	header := http.Header{}
+	header.Add("Sec-WebSocket-Protocol", "bearer, testSession")
+	conn, closer := setup(sessionGetter, clientBroker, header)
+	defer closer()
+
+	g := NewWithT(t)
+	g.Expect(conn.Subprotocol()).To(Equal("bearer"))
+
+	_, _, err := conn.ReadMessage()
+	closeError, ok := err.(*websocket.CloseError)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(closeError.Code).To(Equal(1000))
+	g.Expect(sessionGetter.receivedSession).To(Equal("testSession"))
+	g.Expect(clientBroker.receivedName).To(Equal("testName"))
+}
+
+func Test_StreamEvents_AcceptsTokenInQuery(t *testing.T) {
+	clientOut := make(chan []byte)
+	close(clientOut)
+	clientBroker := &mockClientBroker{channelToReturn: clientOut}
+	sessionGetter := &mockSessionGetter{}
+
+	conn, closer := setupWithQuery(sessionGetter, clientBroker, http.Header{}, "?token=testSession&since=3")
+	defer closer()
+
+	_, _, err := conn.ReadMessage()
+
+	g := NewWithT(t)
+	g.Expect(err).To(BeAssignableToTypeOf(&websocket.CloseError{}))
+	g.Expect(sessionGetter.receivedSession).To(Equal("testSession"))
+	g.Expect(clientBroker.receivedSince).To(Equal(3))
+}
+
+type mockStatsGetter struct{}
+
+func (m mockStatsGetter) Stats() StreamStats {
+	return StreamStats{MaxQueuedMessages: 10, QueueDepths: map[string]int{"p1": 3}, HighestQueueDepth: 4, Evictions: 1}
+}
+
+func Test_RegisterStats(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ginEngine := gin.New()
+	RegisterStats(ginEngine, mockStatsGetter{})
+
+	req, _ := http.NewRequest("GET", "/metrics/streams", nil)
+	w := httptest.NewRecorder()
+	ginEngine.ServeHTTP(w, req)
+
+	g := NewWithT(t)
+	g.Expect(w.Code).To(Equal(200))
+	g.Expect(w.Body.String()).To(Equal(`{"maxQueuedMessages":10,"queueDepths":{"p1":3},"highestQueueDepth":4,"evictions":1}`))
+}
@@ -51,6 +51,18 @@ func (c clientEventBroker) Consume(m messagebus.Message) {
 	case messagebus.PlayerJoined:
 		c.send(clientEvent{PlayerJoined: &playerJoined{Name: m.Player}})
 
+	case messagebus.PlayerReady:
+		c.send(clientEvent{PlayerReady: &playerReady{Name: m.Player}})
+
+	case messagebus.PlayerNotReady:
+		c.send(clientEvent{PlayerNotReady: &playerNotReady{Name: m.Player}})
+
+	case messagebus.RejoinCodeIssued:
+		c.sendToPlayer(m.Player, clientEvent{RejoinCodeIssued: &rejoinCodeIssued{Code: m.Code}})
+
+	case messagebus.RematchStarted:
+		c.send(clientEvent{RematchStarted: &rematchStarted{Players: m.Players}})
+
 	case messagebus.GameStarted:
 		requirements := make([]missionRequirement, len(m.MissionRequirements))
 		for i := range requirements {
@@ -105,9 +117,86 @@ func (c clientEventBroker) Consume(m messagebus.Message) {
 
 	case messagebus.GameEnded:
 		c.send(clientEvent{GameEnded: &gameEnded{Winner: string(m.Winner), Spies: m.Spies}})
+
+	case messagebus.GameSummarized:
+		c.send(clientEvent{GameSummarized: toGameSummarized(m)})
+
+	case messagebus.ChatMessageSent:
+		event := clientEvent{ChatMessageSent: toChatMessageSent(m)}
+		if m.Channel == messagebus.SpyChannel {
+			for _, recipient := range m.Recipients {
+				c.sendToPlayer(recipient, event)
+			}
+		} else {
+			c.send(event)
+		}
+
+	case messagebus.ChatMessageRejected:
+		c.sendToPlayer(m.Player, clientEvent{ChatMessageRejected: &chatMessageRejected{Reason: m.Reason}})
+
+	case messagebus.SpyChannelOpened:
+		for _, spy := range m.Spies {
+			c.sendToPlayer(spy, clientEvent{SpyChannelOpened: &spyChannelOpened{Spies: m.Spies}})
+		}
+
+	case messagebus.SpyChatRevealed:
+		messages := make([]chatMessageSent, len(m.Messages))
+		for i := range m.Messages {
+			messages[i] = *toChatMessageSent(m.Messages[i])
+		}
+		c.send(clientEvent{SpyChatRevealed: &spyChatRevealed{Messages: messages}})
+
+	case messagebus.ReplayRewound:
+		c.send(clientEvent{ReplayRewound: &replayRewound{}})
+
+	case messagebus.ReplayRewindEnded:
+		c.send(clientEvent{ReplayRewindEnded: &replayRewindEnded{}})
+
+	case messagebus.ReplayProgressed:
+		c.send(clientEvent{ReplayProgressed: &replayProgressed{Position: m.Position, NbEvents: m.NbEvents, Playing: m.Playing}})
 	}
 }
 
+func toChatMessageSent(m messagebus.ChatMessageSent) *chatMessageSent {
+	return &chatMessageSent{Player: m.Player, Channel: string(m.Channel), Message: m.Message, SentAt: m.SentAt}
+}
+
 func boolP(b bool) *bool {
 	return &b
 }
+
+func toGameSummarized(m messagebus.GameSummarized) *gameSummarized {
+	allegiances := make(map[string]string)
+	for name, allegiance := range m.AllegianceByPlayer {
+		allegiances[name] = string(allegiance)
+	}
+
+	proposals := make([]teamProposal, len(m.TeamProposals))
+	for i, proposal := range m.TeamProposals {
+		proposals[i] = teamProposal{
+			Mission:  proposal.Mission,
+			Leader:   proposal.Leader,
+			Team:     proposal.Team,
+			Votes:    proposal.Votes,
+			Approved: proposal.Approved,
+		}
+	}
+
+	missions := make([]missionSummary, len(m.Missions))
+	for i, mission := range m.Missions {
+		missions[i] = missionSummary{
+			Mission:  mission.Mission,
+			Team:     mission.Team,
+			Success:  mission.Success,
+			NbFails:  mission.NbFails,
+			FailedBy: mission.FailedBy,
+		}
+	}
+
+	return &gameSummarized{
+		Winner:        string(m.Winner),
+		Allegiances:   allegiances,
+		TeamProposals: proposals,
+		Missions:      missions,
+	}
+}
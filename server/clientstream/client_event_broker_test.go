@@ -2,6 +2,7 @@ package clientstream
 
 import (
 	"testing"
+	"time"
 
 	mb "github.com/damien-springuel/bomb-canary/server/messagebus"
 	. "github.com/onsi/gomega"
@@ -19,6 +20,12 @@ type mockEventSender struct {
 
 	shouldTrackAll      bool
 	allReceivedMessages [][]byte
+
+	connectedPlayers []string
+}
+
+func (m *mockEventSender) ConnectedPlayers() []string {
+	return m.connectedPlayers
 }
 
 func (m *mockEventSender) clearAllReceivedMessages() {
@@ -44,6 +51,12 @@ func (m *mockEventSender) SendToPlayer(name string, message []byte) {
 	}
 }
 
+func (m *mockEventSender) ReplayToPlayer(name string, messages [][]byte) {
+	for _, message := range messages {
+		m.SendToPlayer(name, message)
+	}
+}
+
 func (m *mockEventSender) SendToAllButPlayer(name string, message []byte) {
 	m.receivedNameToAllButPlayer = name
 	m.receivedMessageToAllButPlayers = message
@@ -65,6 +78,46 @@ func Test_ClientEventBroker_PlayerJoined(t *testing.T) {
 	))
 }
 
+func Test_ClientEventBroker_PlayerReady(t *testing.T) {
+	eventSender := &mockEventSender{}
+	eventBroker := NewClientEventBroker(eventSender)
+	eventBroker.Consume(mb.PlayerReady{Player: "testName"})
+
+	g := NewWithT(t)
+	g.Expect(*eventSender).To(Equal(
+		mockEventSender{
+			receivedMessage: toJsonBytes(clientEvent{PlayerReady: &playerReady{Name: "testName"}}),
+		},
+	))
+}
+
+func Test_ClientEventBroker_PlayerNotReady(t *testing.T) {
+	eventSender := &mockEventSender{}
+	eventBroker := NewClientEventBroker(eventSender)
+	eventBroker.Consume(mb.PlayerNotReady{Player: "testName"})
+
+	g := NewWithT(t)
+	g.Expect(*eventSender).To(Equal(
+		mockEventSender{
+			receivedMessage: toJsonBytes(clientEvent{PlayerNotReady: &playerNotReady{Name: "testName"}}),
+		},
+	))
+}
+
+func Test_ClientEventBroker_RejoinCodeIssued(t *testing.T) {
+	eventSender := &mockEventSender{}
+	eventBroker := NewClientEventBroker(eventSender)
+	eventBroker.Consume(mb.RejoinCodeIssued{Player: "testName", Code: "ABC123"})
+
+	g := NewWithT(t)
+	g.Expect(*eventSender).To(Equal(
+		mockEventSender{
+			receivedNameToPlayer:    "testName",
+			receivedMessageToPlayer: toJsonBytes(clientEvent{RejoinCodeIssued: &rejoinCodeIssued{Code: "ABC123"}}),
+		},
+	))
+}
+
 func Test_ClientEventBroker_PlayerConnected(t *testing.T) {
 	eventSender := &mockEventSender{}
 	eventBroker := NewClientEventBroker(eventSender)
@@ -273,6 +326,19 @@ func Test_ClientEventBroker_MissionCompleted(t *testing.T) {
 	))
 }
 
+func Test_ClientEventBroker_RematchStarted(t *testing.T) {
+	eventSender := &mockEventSender{}
+	eventBroker := NewClientEventBroker(eventSender)
+	eventBroker.Consume(mb.RematchStarted{Players: []string{"p2", "p1"}})
+
+	g := NewWithT(t)
+	g.Expect(*eventSender).To(Equal(
+		mockEventSender{
+			receivedMessage: toJsonBytes(clientEvent{RematchStarted: &rematchStarted{Players: []string{"p2", "p1"}}}),
+		},
+	))
+}
+
 func Test_ClientEventBroker_GameEnded(t *testing.T) {
 	eventSender := &mockEventSender{}
 	eventBroker := NewClientEventBroker(eventSender)
@@ -285,3 +351,129 @@ func Test_ClientEventBroker_GameEnded(t *testing.T) {
 		},
 	))
 }
+
+func Test_ClientEventBroker_GameSummarized(t *testing.T) {
+	eventSender := &mockEventSender{}
+	eventBroker := NewClientEventBroker(eventSender)
+	eventBroker.Consume(mb.GameSummarized{
+		Winner:             mb.Spy,
+		AllegianceByPlayer: map[string]mb.Allegiance{"p1": mb.Spy, "p2": mb.Resistance},
+		TeamProposals: []mb.TeamProposal{
+			{Mission: 1, Leader: "p1", Team: []string{"p1", "p2"}, Votes: map[string]bool{"p1": true, "p2": false}, Approved: true},
+		},
+		Missions: []mb.MissionSummary{
+			{Mission: 1, Team: []string{"p1", "p2"}, Success: false, NbFails: 1, FailedBy: []string{"p1"}},
+		},
+	})
+
+	g := NewWithT(t)
+	g.Expect(string(eventSender.receivedMessage)).To(Equal(`{"GameSummarized":{"Winner":"spy","Allegiances":{"p1":"spy","p2":"resistance"},` +
+		`"TeamProposals":[{"Mission":1,"Leader":"p1","Team":["p1","p2"],"Votes":{"p1":true,"p2":false},"Approved":true}],` +
+		`"Missions":[{"Mission":1,"Team":["p1","p2"],"Success":false,"NbFails":1,"FailedBy":["p1"]}]}}`))
+}
+
+func Test_ClientEventBroker_ChatMessageSent(t *testing.T) {
+	eventSender := &mockEventSender{}
+	eventBroker := NewClientEventBroker(eventSender)
+	sentAt := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	eventBroker.Consume(mb.ChatMessageSent{Player: "testName", Channel: mb.PublicChannel, Message: "it's Bob", SentAt: sentAt})
+
+	g := NewWithT(t)
+	g.Expect(string(eventSender.receivedMessage)).To(Equal(`{"ChatMessageSent":{"Player":"testName","Channel":"public","Message":"it's Bob","SentAt":"2021-03-04T05:06:07Z"}}`))
+}
+
+func Test_ClientEventBroker_ChatMessageRejected(t *testing.T) {
+	eventSender := &mockEventSender{}
+	eventBroker := NewClientEventBroker(eventSender)
+	eventBroker.Consume(mb.ChatMessageRejected{Player: "testName", Reason: "too many messages"})
+
+	g := NewWithT(t)
+	g.Expect(*eventSender).To(Equal(
+		mockEventSender{
+			receivedNameToPlayer:    "testName",
+			receivedMessageToPlayer: toJsonBytes(clientEvent{ChatMessageRejected: &chatMessageRejected{Reason: "too many messages"}}),
+		},
+	))
+}
+
+func Test_ClientEventBroker_SpyChatMessageSentOnlyToRecipients(t *testing.T) {
+	eventSender := &mockEventSender{shouldTrackAll: true}
+	eventBroker := NewClientEventBroker(eventSender)
+	sentAt := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	eventBroker.Consume(mb.ChatMessageSent{Player: "a", Channel: mb.SpyChannel, Recipients: []string{"a", "c"}, Message: "fail it", SentAt: sentAt})
+
+	expectedMessage := toJsonBytes(clientEvent{ChatMessageSent: &chatMessageSent{Player: "a", Channel: "spies", Message: "fail it", SentAt: sentAt}})
+
+	g := NewWithT(t)
+	g.Expect(eventSender.receivedAllNamesToPlayer).To(Equal(map[string][]byte{"a": expectedMessage, "c": expectedMessage}))
+	g.Expect(eventSender.receivedMessage).To(BeNil())
+}
+
+func Test_ClientEventBroker_SpyChannelOpened(t *testing.T) {
+	eventSender := &mockEventSender{shouldTrackAll: true}
+	eventBroker := NewClientEventBroker(eventSender)
+	eventBroker.Consume(mb.SpyChannelOpened{Spies: []string{"a", "c"}})
+
+	expectedMessage := toJsonBytes(clientEvent{SpyChannelOpened: &spyChannelOpened{Spies: []string{"a", "c"}}})
+
+	g := NewWithT(t)
+	g.Expect(eventSender.receivedAllNamesToPlayer).To(Equal(map[string][]byte{"a": expectedMessage, "c": expectedMessage}))
+	g.Expect(eventSender.receivedMessage).To(BeNil())
+}
+
+func Test_ClientEventBroker_SpyChatRevealed(t *testing.T) {
+	eventSender := &mockEventSender{}
+	eventBroker := NewClientEventBroker(eventSender)
+	sentAt := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	eventBroker.Consume(mb.SpyChatRevealed{Messages: []mb.ChatMessageSent{
+		{Player: "a", Channel: mb.SpyChannel, Recipients: []string{"a", "c"}, Message: "fail it", SentAt: sentAt},
+	}})
+
+	g := NewWithT(t)
+	g.Expect(*eventSender).To(Equal(
+		mockEventSender{
+			receivedMessage: toJsonBytes(clientEvent{SpyChatRevealed: &spyChatRevealed{Messages: []chatMessageSent{
+				{Player: "a", Channel: "spies", Message: "fail it", SentAt: sentAt},
+			}}}),
+		},
+	))
+}
+
+func Test_ClientEventBroker_ReplayRewound(t *testing.T) {
+	eventSender := &mockEventSender{}
+	eventBroker := NewClientEventBroker(eventSender)
+	eventBroker.Consume(mb.ReplayRewound{})
+
+	g := NewWithT(t)
+	g.Expect(*eventSender).To(Equal(
+		mockEventSender{
+			receivedMessage: toJsonBytes(clientEvent{ReplayRewound: &replayRewound{}}),
+		},
+	))
+}
+
+func Test_ClientEventBroker_ReplayRewindEnded(t *testing.T) {
+	eventSender := &mockEventSender{}
+	eventBroker := NewClientEventBroker(eventSender)
+	eventBroker.Consume(mb.ReplayRewindEnded{})
+
+	g := NewWithT(t)
+	g.Expect(*eventSender).To(Equal(
+		mockEventSender{
+			receivedMessage: toJsonBytes(clientEvent{ReplayRewindEnded: &replayRewindEnded{}}),
+		},
+	))
+}
+
+func Test_ClientEventBroker_ReplayProgressed(t *testing.T) {
+	eventSender := &mockEventSender{}
+	eventBroker := NewClientEventBroker(eventSender)
+	eventBroker.Consume(mb.ReplayProgressed{Position: 3, NbEvents: 10, Playing: true})
+
+	g := NewWithT(t)
+	g.Expect(*eventSender).To(Equal(
+		mockEventSender{
+			receivedMessage: toJsonBytes(clientEvent{ReplayProgressed: &replayProgressed{Position: 3, NbEvents: 10, Playing: true}}),
+		},
+	))
+}
@@ -1,9 +1,15 @@
 package clientstream
 
+import "time"
+
 type clientEvent struct {
+	Sequence                     int                           `json:",omitempty"`
 	PlayerConnected              *playerConnected              `json:",omitempty"`
 	PlayerDisconnected           *playerDisconnected           `json:",omitempty"`
 	PlayerJoined                 *playerJoined                 `json:",omitempty"`
+	PlayerReady                  *playerReady                  `json:",omitempty"`
+	PlayerNotReady               *playerNotReady               `json:",omitempty"`
+	RematchStarted               *rematchStarted               `json:",omitempty"`
 	GameStarted                  *gameStarted                  `json:",omitempty"`
 	SpiesRevealed                *spiesRevealed                `json:",omitempty"`
 	LeaderStartedToSelectMembers *leaderStartedToSelectMembers `json:",omitempty"`
@@ -16,14 +22,35 @@ type clientEvent struct {
 	PlayerWorkedOnMission        *playerWorkedOnMission        `json:",omitempty"`
 	MissionCompleted             *missionCompleted             `json:",omitempty"`
 	GameEnded                    *gameEnded                    `json:",omitempty"`
+	GameSummarized               *gameSummarized               `json:",omitempty"`
+	ChatMessageSent              *chatMessageSent              `json:",omitempty"`
+	ChatMessageRejected          *chatMessageRejected          `json:",omitempty"`
+	SpyChannelOpened             *spyChannelOpened             `json:",omitempty"`
+	SpyChatRevealed              *spyChatRevealed              `json:",omitempty"`
+	RejoinCodeIssued             *rejoinCodeIssued             `json:",omitempty"`
 	EventsReplayStarted          *eventsReplayStarted          `json:",omitempty"`
 	EventsReplayEnded            *eventsReplayEnded            `json:",omitempty"`
+	ReplayRewound                *replayRewound                `json:",omitempty"`
+	ReplayRewindEnded            *replayRewindEnded            `json:",omitempty"`
+	ReplayProgressed             *replayProgressed             `json:",omitempty"`
 }
 
 type playerJoined struct {
 	Name string
 }
 
+type playerReady struct {
+	Name string
+}
+
+type playerNotReady struct {
+	Name string
+}
+
+type rejoinCodeIssued struct {
+	Code string
+}
+
 type playerConnected struct {
 	Name string
 }
@@ -32,6 +59,10 @@ type playerDisconnected struct {
 	Name string
 }
 
+type rematchStarted struct {
+	Players []string
+}
+
 type missionRequirement struct {
 	NbPeopleOnMission        int
 	NbFailuresRequiredToFail int
@@ -87,8 +118,61 @@ type gameEnded struct {
 	Spies  []string
 }
 
+type teamProposal struct {
+	Mission  int
+	Leader   string
+	Team     []string
+	Votes    map[string]bool
+	Approved bool
+}
+
+type missionSummary struct {
+	Mission  int
+	Team     []string
+	Success  bool
+	NbFails  int
+	FailedBy []string `json:",omitempty"`
+}
+
+type gameSummarized struct {
+	Winner        string
+	Allegiances   map[string]string
+	TeamProposals []teamProposal
+	Missions      []missionSummary
+}
+
+type chatMessageSent struct {
+	Player  string
+	Channel string
+	Message string
+	SentAt  time.Time
+}
+
+type chatMessageRejected struct {
+	Reason string
+}
+
+type spyChannelOpened struct {
+	Spies []string
+}
+
+type spyChatRevealed struct {
+	Messages []chatMessageSent
+}
+
 type eventsReplayEnded struct{}
 
 type eventsReplayStarted struct {
 	Player string
+	Since  int `json:",omitempty"`
+}
+
+type replayRewound struct{}
+
+type replayRewindEnded struct{}
+
+type replayProgressed struct {
+	Position int
+	NbEvents int
+	Playing  bool
 }
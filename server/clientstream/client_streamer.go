@@ -2,88 +2,281 @@ package clientstream
 
 import (
 	"sync"
+	"time"
 
 	"github.com/damien-springuel/bomb-canary/server/messagebus"
 )
 
+const defaultMaxQueuedMessages = 256
+
+var evictedNotice = []byte{}
+
 type name string
 
 type messageDispatcher interface {
 	Dispatch(m messagebus.Message)
 }
 
+type StreamStats struct {
+	MaxQueuedMessages int            `json:"maxQueuedMessages"`
+	QueueDepths       map[string]int `json:"queueDepths"`
+	HighestQueueDepth int            `json:"highestQueueDepth"`
+	Evictions         int            `json:"evictions"`
+}
+
+type queuedMessage struct {
+	message []byte
+	live    bool
+}
+
+// clientQueue hands messages to out directly while it has room; what doesn't fit
+// waits in overflow and a pump feeds it to out as the client reads.
+// Only live messages count against the max, a replay can be as long as it needs.
+type clientQueue struct {
+	out      chan []byte
+	addedAt  time.Time
+	inOut    []bool
+	overflow []queuedMessage
+	nbLive   int
+	pumping  bool
+	closing  bool
+	stopped  bool
+	closed   bool
+	stop     chan struct{}
+}
+
+func (q *clientQueue) depth() int {
+	return len(q.out) + len(q.overflow)
+}
+
+func (q *clientQueue) forgetRead() {
+	read := len(q.inOut) - len(q.out)
+	if read <= 0 {
+		return
+	}
+	for _, live := range q.inOut[:read] {
+		if live {
+			q.nbLive -= 1
+		}
+	}
+	q.inOut = q.inOut[read:]
+}
+
 type clientStreamer struct {
-	mut               *sync.RWMutex
-	clientOutByName   map[name]chan []byte
+	mut               *sync.Mutex
+	clientByName      map[name]*clientQueue
 	messageDispatcher messageDispatcher
+	maxQueuedMessages int
+	stats             *StreamStats
 }
 
 func NewClientsStreamer(messageDispatcher messageDispatcher) clientStreamer {
 	return clientStreamer{
-		mut:               &sync.RWMutex{},
-		clientOutByName:   make(map[name]chan []byte),
+		mut:               &sync.Mutex{},
+		clientByName:      make(map[name]*clientQueue),
 		messageDispatcher: messageDispatcher,
+		maxQueuedMessages: defaultMaxQueuedMessages,
+		stats:             &StreamStats{},
 	}
 }
 
-func (c clientStreamer) Add(playerName string) (chan []byte, func()) {
+func (c clientStreamer) Add(playerName string, since int) (chan []byte, func()) {
 	c.mut.Lock()
 	defer c.mut.Unlock()
 
-	clientOut := make(chan []byte)
-	c.clientOutByName[name(playerName)] = clientOut
+	superseded, exists := c.clientByName[name(playerName)]
+	if exists {
+		c.stopQueue(superseded)
+	}
+
+	client := &clientQueue{
+		out:     make(chan []byte, c.maxQueuedMessages+1),
+		addedAt: time.Now(),
+		stop:    make(chan struct{}),
+	}
+	c.clientByName[name(playerName)] = client
 
-	c.dispatchConnectedMessage(playerName)
+	c.dispatchConnectedMessage(playerName, since)
 
-	return clientOut, func() {
-		c.remove(playerName)
+	return client.out, func() {
+		c.remove(playerName, client)
 	}
 }
 
-func (c clientStreamer) remove(playerName string) {
+func (c clientStreamer) remove(playerName string, client *clientQueue) {
 	c.mut.Lock()
 	defer c.mut.Unlock()
 
-	client, exists := c.clientOutByName[name(playerName)]
-	if exists {
-		close(client)
+	current, exists := c.clientByName[name(playerName)]
+	if exists && current == client {
 		c.dispatchDisconnectedMessage(playerName)
-		delete(c.clientOutByName, name(playerName))
+		delete(c.clientByName, name(playerName))
 	}
+	c.stopQueue(client)
 }
 
-func (c clientStreamer) Send(message []byte) {
-	c.mut.RLock()
-	defer c.mut.RUnlock()
+func (c clientStreamer) stopQueue(q *clientQueue) {
+	if q.closed || q.stopped {
+		return
+	}
+	if q.pumping {
+		q.stopped = true
+		close(q.stop)
+		return
+	}
+	close(q.out)
+	q.closed = true
+}
 
-	for _, out := range c.clientOutByName {
-		out <- []byte(message)
+func (c clientStreamer) push(q *clientQueue, m queuedMessage) {
+	if len(q.overflow) == 0 && len(q.out) < cap(q.out) {
+		q.out <- m.message
+		q.inOut = append(q.inOut, m.live)
+	} else {
+		q.overflow = append(q.overflow, m)
+		if !q.pumping {
+			q.pumping = true
+			go c.pump(q)
+		}
 	}
 }
 
-func (c clientStreamer) SendToPlayer(playerName string, message []byte) {
-	c.mut.RLock()
-	defer c.mut.RUnlock()
+func (c clientStreamer) recordDepth(q *clientQueue) {
+	if q.depth() > c.stats.HighestQueueDepth {
+		c.stats.HighestQueueDepth = q.depth()
+	}
+}
 
-	client, exists := c.clientOutByName[name(playerName)]
-	if exists {
-		client <- message
+func (c clientStreamer) pump(q *clientQueue) {
+	for {
+		c.mut.Lock()
+		if q.stopped || len(q.overflow) == 0 {
+			if q.stopped || q.closing {
+				close(q.out)
+				q.closed = true
+			}
+			q.overflow = nil
+			q.pumping = false
+			c.mut.Unlock()
+			return
+		}
+		next := q.overflow[0]
+		c.mut.Unlock()
+
+		select {
+		case q.out <- next.message:
+			c.mut.Lock()
+			q.overflow = q.overflow[1:]
+			q.inOut = append(q.inOut, next.live)
+			c.mut.Unlock()
+		case <-q.stop:
+		}
+	}
+}
+
+func (c clientStreamer) enqueue(n name, q *clientQueue, message []byte) (evicted bool) {
+	q.forgetRead()
+	if q.nbLive >= c.maxQueuedMessages {
+		c.push(q, queuedMessage{message: evictedNotice})
+		q.closing = true
+		if !q.pumping {
+			close(q.out)
+			q.closed = true
+		}
+		delete(c.clientByName, n)
+		c.stats.Evictions += 1
+		return true
 	}
+
+	q.nbLive += 1
+	c.push(q, queuedMessage{message: message, live: true})
+	c.recordDepth(q)
+	return false
+}
+
+func (c clientStreamer) fanOut(shouldReceive func(n name) bool, message []byte) {
+	c.mut.Lock()
+	evictedNames := []name{}
+	for n, q := range c.clientByName {
+		if shouldReceive(n) && c.enqueue(n, q, message) {
+			evictedNames = append(evictedNames, n)
+		}
+	}
+	c.mut.Unlock()
+
+	for _, n := range evictedNames {
+		c.dispatchDisconnectedMessage(string(n))
+	}
+}
+
+func (c clientStreamer) Send(message []byte) {
+	c.fanOut(func(n name) bool { return true }, message)
+}
+
+func (c clientStreamer) SendToPlayer(playerName string, message []byte) {
+	c.fanOut(func(n name) bool { return n == name(playerName) }, message)
 }
 
 func (c clientStreamer) SendToAllButPlayer(playerName string, message []byte) {
-	c.mut.RLock()
-	defer c.mut.RUnlock()
+	c.fanOut(func(n name) bool { return n != name(playerName) }, message)
+}
+
+func (c clientStreamer) ReplayToPlayer(playerName string, messages [][]byte) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
 
-	for n, out := range c.clientOutByName {
-		if n != name(playerName) {
-			out <- []byte(message)
+	q, exists := c.clientByName[name(playerName)]
+	if !exists {
+		return
+	}
+	for _, message := range messages {
+		c.push(q, queuedMessage{message: message})
+	}
+	c.recordDepth(q)
+}
+
+func (c clientStreamer) ConnectedPlayers() []string {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	players := make([]string, 0, len(c.clientByName))
+	for n := range c.clientByName {
+		players = append(players, string(n))
+	}
+	return players
+}
+
+func (c clientStreamer) Consume(m messagebus.Message) {
+	loggedOut, ok := m.(messagebus.PlayerLoggedOut)
+	if ok {
+		c.mut.Lock()
+		client, exists := c.clientByName[name(loggedOut.Player)]
+		c.mut.Unlock()
+		if exists && !client.addedAt.After(loggedOut.At) {
+			c.remove(loggedOut.Player, client)
 		}
 	}
 }
 
-func (c clientStreamer) dispatchConnectedMessage(name string) {
-	c.messageDispatcher.Dispatch(messagebus.PlayerConnected{Player: name})
+func (c clientStreamer) Stats() StreamStats {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	depths := make(map[string]int, len(c.clientByName))
+	for n, q := range c.clientByName {
+		depths[string(n)] = q.depth()
+	}
+
+	return StreamStats{
+		MaxQueuedMessages: c.maxQueuedMessages,
+		QueueDepths:       depths,
+		HighestQueueDepth: c.stats.HighestQueueDepth,
+		Evictions:         c.stats.Evictions,
+	}
+}
+
+func (c clientStreamer) dispatchConnectedMessage(name string, since int) {
+	c.messageDispatcher.Dispatch(messagebus.PlayerConnected{Player: name, Since: since})
 }
 
 func (c clientStreamer) dispatchDisconnectedMessage(name string) {
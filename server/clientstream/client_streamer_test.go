@@ -2,6 +2,7 @@ package clientstream
 
 import (
 	"testing"
+	"time"
 
 	"github.com/damien-springuel/bomb-canary/server/messagebus"
 	. "github.com/onsi/gomega"
@@ -16,7 +17,7 @@ func (d *mockMessageDispatcher) Dispatch(m messagebus.Message) {
 }
 
 func createAndPumpOut(streamer clientStreamer, name string, done chan [][]byte) func() {
-	out, closer := streamer.Add(name)
+	out, closer := streamer.Add(name, 0)
 
 	actualMessages := [][]byte{}
 	go func() {
@@ -144,3 +145,170 @@ func Test_AddAndRemove_AndReconnectASecondTime(t *testing.T) {
 		messagebus.PlayerDisconnected{Player: "p1"},
 	}))
 }
+
+func Test_AddDispatchesSinceWithPlayerConnected(t *testing.T) {
+	dispatcher := &mockMessageDispatcher{}
+	streamer := NewClientsStreamer(dispatcher)
+	_, closer := streamer.Add("p1", 12)
+	closer()
+
+	g := NewWithT(t)
+	g.Expect(dispatcher.receivedMessages[0]).To(Equal(messagebus.PlayerConnected{Player: "p1", Since: 12}))
+}
+
+func Test_SendDoesntBlockOnSlowClient(t *testing.T) {
+	streamer := NewClientsStreamer(&mockMessageDispatcher{})
+	slowOut, _ := streamer.Add("slow", 0)
+	testOut := make(chan [][]byte)
+	done := createAndPumpOut(streamer, "fast", testOut)
+
+	streamer.Send([]byte("m1"))
+	streamer.Send([]byte("m2"))
+
+	done()
+	actualMessages := <-testOut
+
+	g := NewWithT(t)
+	g.Expect(actualMessages).To(Equal([][]byte{[]byte("m1"), []byte("m2")}))
+	g.Expect(slowOut).To(HaveLen(2))
+}
+
+func Test_EvictsClientTooFarBehind(t *testing.T) {
+	dispatcher := &mockMessageDispatcher{}
+	streamer := NewClientsStreamer(dispatcher)
+	streamer.maxQueuedMessages = 2
+	slowOut, closer := streamer.Add("slow", 0)
+
+	streamer.Send([]byte("m1"))
+	streamer.SendToPlayer("slow", []byte("m2"))
+	streamer.SendToAllButPlayer("other", []byte("m3"))
+	streamer.Send([]byte("m4"))
+
+	actualMessages := [][]byte{}
+	for m := range slowOut {
+		actualMessages = append(actualMessages, m)
+	}
+	closer()
+
+	g := NewWithT(t)
+	g.Expect(actualMessages).To(Equal([][]byte{[]byte("m1"), []byte("m2"), evictedNotice}))
+	g.Expect(dispatcher.receivedMessages).To(Equal([]messagebus.Message{
+		messagebus.PlayerConnected{Player: "slow"},
+		messagebus.PlayerDisconnected{Player: "slow"},
+	}))
+	g.Expect(streamer.Stats()).To(Equal(StreamStats{
+		MaxQueuedMessages: 2,
+		QueueDepths:       map[string]int{},
+		HighestQueueDepth: 2,
+		Evictions:         1,
+	}))
+}
+
+func Test_StaleCloserDoesntRemoveNewConnection(t *testing.T) {
+	dispatcher := &mockMessageDispatcher{}
+	streamer := NewClientsStreamer(dispatcher)
+	_, staleCloser := streamer.Add("p1", 0)
+	_, _ = streamer.Add("p1", 3)
+
+	staleCloser()
+	streamer.Send([]byte("m1"))
+
+	g := NewWithT(t)
+	g.Expect(streamer.Stats().QueueDepths).To(Equal(map[string]int{"p1": 1}))
+}
+
+func Test_AddingTheSamePlayerTwiceClosesTheFirstStream(t *testing.T) {
+	dispatcher := &mockMessageDispatcher{}
+	streamer := NewClientsStreamer(dispatcher)
+	firstOut, firstCloser := streamer.Add("p1", 0)
+	secondOut, _ := streamer.Add("p1", 3)
+
+	streamer.Send([]byte("m1"))
+	firstCloser()
+
+	g := NewWithT(t)
+	_, open := <-firstOut
+	g.Expect(open).To(BeFalse())
+	g.Expect(<-secondOut).To(Equal([]byte("m1")))
+	g.Expect(dispatcher.receivedMessages).To(Equal([]messagebus.Message{
+		messagebus.PlayerConnected{Player: "p1"},
+		messagebus.PlayerConnected{Player: "p1", Since: 3},
+	}))
+}
+
+func Test_ReplayIsntCountedAgainstTheMaxQueuedMessages(t *testing.T) {
+	dispatcher := &mockMessageDispatcher{}
+	streamer := NewClientsStreamer(dispatcher)
+	streamer.maxQueuedMessages = 2
+	out, closer := streamer.Add("p1", 0)
+
+	streamer.ReplayToPlayer("p1", [][]byte{[]byte("r1"), []byte("r2"), []byte("r3"), []byte("r4"), []byte("r5")})
+	streamer.Send([]byte("m1"))
+	streamer.Send([]byte("m2"))
+
+	actualMessages := [][]byte{}
+	for i := 0; i < 7; i++ {
+		actualMessages = append(actualMessages, <-out)
+	}
+	closer()
+
+	g := NewWithT(t)
+	g.Expect(actualMessages).To(Equal([][]byte{
+		[]byte("r1"), []byte("r2"), []byte("r3"), []byte("r4"), []byte("r5"), []byte("m1"), []byte("m2"),
+	}))
+	g.Expect(streamer.Stats().Evictions).To(Equal(0))
+}
+
+func Test_ReconnectingWithMoreRecordedEventsThanTheMaxQueuedMessages(t *testing.T) {
+	streamer := NewClientsStreamer(&mockMessageDispatcher{})
+	replayer := NewEventReplayer(streamer)
+	for i := 0; i < defaultMaxQueuedMessages+50; i++ {
+		replayer.Send([]byte("m"))
+	}
+
+	out, closer := streamer.Add("p1", 0)
+	replayer.Consume(messagebus.PlayerConnected{Player: "p1"})
+	replayer.Send([]byte("live"))
+
+	actualMessages := [][]byte{}
+	for i := 0; i < defaultMaxQueuedMessages+53; i++ {
+		actualMessages = append(actualMessages, <-out)
+	}
+	closer()
+
+	g := NewWithT(t)
+	g.Expect(actualMessages[defaultMaxQueuedMessages+51]).To(Equal(expectedReplayEnded))
+	g.Expect(actualMessages[defaultMaxQueuedMessages+52]).To(Equal([]byte("live")))
+	g.Expect(streamer.Stats().Evictions).To(Equal(0))
+}
+
+func Test_LoggedOutPlayerStreamIsClosed(t *testing.T) {
+	dispatcher := &mockMessageDispatcher{}
+	streamer := NewClientsStreamer(dispatcher)
+	p1Out, _ := streamer.Add("p1", 0)
+	p2Out, _ := streamer.Add("p2", 0)
+
+	streamer.Consume(messagebus.PlayerLoggedOut{Player: "p1", At: time.Now()})
+	streamer.Send([]byte("message1"))
+
+	g := NewWithT(t)
+	_, open := <-p1Out
+	g.Expect(open).To(BeFalse())
+	g.Expect(<-p2Out).To(Equal([]byte("message1")))
+	g.Expect(dispatcher.receivedMessages).To(ContainElement(messagebus.PlayerDisconnected{Player: "p1"}))
+	g.Expect(dispatcher.receivedMessages).ToNot(ContainElement(messagebus.PlayerDisconnected{Player: "p2"}))
+}
+
+func Test_LogoutDoesntCloseStreamOpenedAfterIt(t *testing.T) {
+	dispatcher := &mockMessageDispatcher{}
+	streamer := NewClientsStreamer(dispatcher)
+	loggedOutAt := time.Now()
+	p1Out, _ := streamer.Add("p1", 0)
+
+	streamer.Consume(messagebus.PlayerLoggedOut{Player: "p1", At: loggedOutAt.Add(-time.Second)})
+	streamer.Send([]byte("message1"))
+
+	g := NewWithT(t)
+	g.Expect(<-p1Out).To(Equal([]byte("message1")))
+	g.Expect(dispatcher.receivedMessages).ToNot(ContainElement(messagebus.PlayerDisconnected{Player: "p1"}))
+}
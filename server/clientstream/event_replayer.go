@@ -3,10 +3,13 @@ package clientstream
 import (
 	"encoding/json"
 	"sync"
+	"time"
 
 	"github.com/damien-springuel/bomb-canary/server/messagebus"
 )
 
+const defaultCompactionThreshold = 1000
+
 type replayType string
 
 const (
@@ -16,22 +19,52 @@ const (
 )
 
 type replayMessage struct {
-	replayType replayType
-	name       string
-	message    []byte
+	sequence         int
+	replayType       replayType
+	name             string
+	message          []byte
+	connectionStatus string
+}
+
+func (r replayMessage) isVisibleTo(playerName string) bool {
+	return r.replayType == All ||
+		(r.replayType == Player && r.name == playerName) ||
+		(r.replayType == AllButPlayer && r.name != playerName)
+}
+
+type PolledEvents struct {
+	Events []json.RawMessage `json:"events"`
+	Next   int               `json:"next"`
+	Reset  bool              `json:"reset"`
+}
+
+type replaySender interface {
+	eventSender
+	ReplayToPlayer(playerName string, messages [][]byte)
+	ConnectedPlayers() []string
 }
 
 type eventReplayer struct {
-	eventSender eventSender
-	mut         *sync.RWMutex
-	messages    []replayMessage
+	eventSender         replaySender
+	mut                 *sync.RWMutex
+	messages            []replayMessage
+	lastSequence        int
+	compactedUpTo       int
+	compactionThreshold int
+	sinceCompaction     int
+	updated             chan struct{}
+	cursors             map[string]int
+	rewinding           bool
 }
 
-func NewEventReplayer(eventSender eventSender) *eventReplayer {
+func NewEventReplayer(eventSender replaySender) *eventReplayer {
 	return &eventReplayer{
-		eventSender: eventSender,
-		mut:         &sync.RWMutex{},
-		messages:    make([]replayMessage, 0),
+		eventSender:         eventSender,
+		mut:                 &sync.RWMutex{},
+		messages:            make([]replayMessage, 0),
+		compactionThreshold: defaultCompactionThreshold,
+		updated:             make(chan struct{}),
+		cursors:             make(map[string]int),
 	}
 }
 
@@ -41,43 +74,179 @@ func (e *eventReplayer) Consume(m messagebus.Message) {
 		e.mut.RLock()
 		defer e.mut.RUnlock()
 
-		replayStartedMessage, _ := json.Marshal(clientEvent{EventsReplayStarted: &eventsReplayStarted{Player: connectEvent.Player}})
-		e.eventSender.SendToPlayer(connectEvent.Player, replayStartedMessage)
-
-		e.sendReplayableMessages(connectEvent.Player)
+		since := connectEvent.Since
+		if since < e.compactedUpTo || since > e.lastSequence {
+			since = 0
+		}
 
-		replayEndedMessage, _ := json.Marshal(clientEvent{EventsReplayEnded: &eventsReplayEnded{}})
-		e.eventSender.SendToPlayer(connectEvent.Player, replayEndedMessage)
+		e.replayTo(connectEvent.Player, since)
 	}
 }
 
-func (e *eventReplayer) sendReplayableMessages(playerName string) {
+func (e *eventReplayer) replayTo(playerName string, since int) {
+	replayStartedMessage, _ := json.Marshal(clientEvent{EventsReplayStarted: &eventsReplayStarted{Player: playerName, Since: since}})
+	replay := [][]byte{replayStartedMessage}
+
 	for _, replayMessage := range e.messages {
-		if replayMessage.replayType == All ||
-			(replayMessage.replayType == Player && replayMessage.name == playerName) ||
-			(replayMessage.replayType == AllButPlayer && replayMessage.name != playerName) {
-			e.eventSender.SendToPlayer(playerName, replayMessage.message)
+		if replayMessage.sequence <= since {
+			continue
+		}
+		if replayMessage.isVisibleTo(playerName) {
+			replay = append(replay, replayMessage.message)
 		}
 	}
+
+	replayEndedMessage, _ := json.Marshal(clientEvent{EventsReplayEnded: &eventsReplayEnded{}})
+	replay = append(replay, replayEndedMessage)
+	e.eventSender.ReplayToPlayer(playerName, replay)
 }
 
-func (e *eventReplayer) recordMessage(replayMessage replayMessage) {
+// recordMessage tells whether the message should also go out live. A rewind is
+// only recorded until it ends, then every connected player resyncs from a single replay.
+func (e *eventReplayer) recordMessage(replayMessage replayMessage) ([]byte, bool) {
 	e.mut.Lock()
 	defer e.mut.Unlock()
+
+	event := clientEvent{}
+	err := json.Unmarshal(replayMessage.message, &event)
+	if err == nil && event.ReplayRewindEnded != nil {
+		e.rewinding = false
+		for _, playerName := range e.eventSender.ConnectedPlayers() {
+			e.replayTo(playerName, 0)
+		}
+		return nil, false
+	}
+
+	e.lastSequence += 1
+	replayMessage.sequence = e.lastSequence
+
+	if err == nil {
+		event.Sequence = replayMessage.sequence
+		replayMessage.message, _ = json.Marshal(event)
+		if event.ReplayRewound != nil {
+			e.rewind()
+			e.rewinding = true
+		} else if event.PlayerConnected != nil {
+			replayMessage.connectionStatus = event.PlayerConnected.Name
+		} else if event.PlayerDisconnected != nil {
+			replayMessage.connectionStatus = event.PlayerDisconnected.Name
+		}
+	}
+
 	e.messages = append(e.messages, replayMessage)
+	e.sinceCompaction += 1
+	if e.sinceCompaction >= e.compactionThreshold {
+		e.compact()
+		e.sinceCompaction = 0
+	}
+	close(e.updated)
+	e.updated = make(chan struct{})
+	return replayMessage.message, !e.rewinding
+}
+
+func (e *eventReplayer) rewind() {
+	e.messages = make([]replayMessage, 0)
+	e.compactedUpTo = e.lastSequence - 1
+	e.sinceCompaction = 0
+}
+
+func (e *eventReplayer) compact() {
+	lastConnectionStatusIndex := make(map[string]int)
+	for i, m := range e.messages {
+		if m.connectionStatus != "" {
+			lastConnectionStatusIndex[m.connectionStatus] = i
+		}
+	}
+
+	compacted := make([]replayMessage, 0, len(e.messages))
+	for i, m := range e.messages {
+		if m.connectionStatus != "" && lastConnectionStatusIndex[m.connectionStatus] != i {
+			continue
+		}
+		compacted = append(compacted, m)
+	}
+
+	if len(compacted) < len(e.messages) {
+		e.compactedUpTo = e.lastSequence
+	}
+	e.messages = compacted
+}
+
+func (e *eventReplayer) Cursor(playerName string) int {
+	e.mut.RLock()
+	defer e.mut.RUnlock()
+	return e.cursors[playerName]
+}
+
+func (e *eventReplayer) Poll(playerName string, since int, timeout time.Duration) PolledEvents {
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+
+	reset := false
+	for {
+		e.mut.Lock()
+		polled := e.eventsSince(playerName, since)
+		updated := e.updated
+		if len(polled.Events) > 0 {
+			e.cursors[playerName] = polled.Next
+		}
+		e.mut.Unlock()
+
+		if polled.Reset {
+			reset = true
+			since = 0
+		}
+		polled.Reset = reset
+
+		if len(polled.Events) > 0 {
+			return polled
+		}
+
+		select {
+		case <-updated:
+		case <-deadline.C:
+			return polled
+		}
+	}
+}
+
+func (e *eventReplayer) eventsSince(playerName string, since int) PolledEvents {
+	polled := PolledEvents{Events: []json.RawMessage{}, Next: since}
+	if since < e.compactedUpTo || since > e.lastSequence {
+		polled.Reset = true
+		polled.Next = 0
+		since = 0
+	}
+
+	for _, replayMessage := range e.messages {
+		if replayMessage.sequence <= since {
+			continue
+		}
+		if replayMessage.isVisibleTo(playerName) {
+			polled.Events = append(polled.Events, replayMessage.message)
+		}
+		polled.Next = replayMessage.sequence
+	}
+	return polled
 }
 
 func (e *eventReplayer) Send(message []byte) {
-	e.recordMessage(replayMessage{replayType: All, message: message})
-	e.eventSender.Send(message)
+	message, live := e.recordMessage(replayMessage{replayType: All, message: message})
+	if live {
+		e.eventSender.Send(message)
+	}
 }
 
 func (e *eventReplayer) SendToPlayer(playerName string, message []byte) {
-	e.recordMessage(replayMessage{replayType: Player, name: playerName, message: message})
-	e.eventSender.SendToPlayer(playerName, message)
+	message, live := e.recordMessage(replayMessage{replayType: Player, name: playerName, message: message})
+	if live {
+		e.eventSender.SendToPlayer(playerName, message)
+	}
 }
 
 func (e *eventReplayer) SendToAllButPlayer(playerName string, message []byte) {
-	e.recordMessage(replayMessage{replayType: AllButPlayer, name: playerName, message: message})
-	e.eventSender.SendToAllButPlayer(playerName, message)
+	message, live := e.recordMessage(replayMessage{replayType: AllButPlayer, name: playerName, message: message})
+	if live {
+		e.eventSender.SendToAllButPlayer(playerName, message)
+	}
 }
@@ -3,6 +3,7 @@ package clientstream
 import (
 	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/damien-springuel/bomb-canary/server/messagebus"
 	. "github.com/onsi/gomega"
@@ -182,3 +183,285 @@ func Test_Replayer_MixedCase(t *testing.T) {
 		expectedReplayEnded,
 	}))
 }
+
+func Test_Replayer_AddsSequenceToClientEvents(t *testing.T) {
+	mockEventSender := &mockEventSender{shouldTrackAll: true}
+	replayer := NewEventReplayer(mockEventSender)
+	replayer.Send(toJsonBytes(clientEvent{PlayerJoined: &playerJoined{Name: "p1"}}))
+	replayer.SendToPlayer("p1", toJsonBytes(clientEvent{SpiesRevealed: &spiesRevealed{}}))
+	replayer.SendToAllButPlayer("p1", toJsonBytes(clientEvent{PlayerVotedOnTeam: &playerVotedOnTeam{Player: "p1"}}))
+
+	g := NewWithT(t)
+	g.Expect(mockEventSender.allReceivedMessages).To(Equal([][]byte{
+		toJsonBytes(clientEvent{Sequence: 1, PlayerJoined: &playerJoined{Name: "p1"}}),
+		toJsonBytes(clientEvent{Sequence: 2, SpiesRevealed: &spiesRevealed{}}),
+		toJsonBytes(clientEvent{Sequence: 3, PlayerVotedOnTeam: &playerVotedOnTeam{Player: "p1"}}),
+	}))
+}
+
+func Test_Replayer_ReplaysOnlyMissedEventsSinceSequence(t *testing.T) {
+	mockEventSender := &mockEventSender{shouldTrackAll: true}
+	replayer := NewEventReplayer(mockEventSender)
+	replayer.Send([]byte("m1"))
+	replayer.SendToPlayer("p1", []byte("m2"))
+	replayer.SendToPlayer("p2", []byte("m3"))
+	replayer.Send([]byte("m4"))
+	mockEventSender.clearAllReceivedMessages()
+
+	replayer.Consume(messagebus.PlayerConnected{Player: "p1", Since: 1})
+
+	expectedReplayStarted, _ := json.Marshal(clientEvent{EventsReplayStarted: &eventsReplayStarted{Player: "p1", Since: 1}})
+	g := NewWithT(t)
+	g.Expect(mockEventSender.allReceivedMessages).To(Equal([][]byte{
+		expectedReplayStarted,
+		[]byte("m2"),
+		[]byte("m4"),
+		expectedReplayEnded,
+	}))
+}
+
+func Test_Replayer_FullReplayIfSinceIsAheadOfHistory(t *testing.T) {
+	mockEventSender := &mockEventSender{shouldTrackAll: true}
+	replayer := NewEventReplayer(mockEventSender)
+	replayer.Send([]byte("m1"))
+	replayer.Send([]byte("m2"))
+	mockEventSender.clearAllReceivedMessages()
+
+	replayer.Consume(messagebus.PlayerConnected{Player: "p1", Since: 10})
+
+	expectedReplayStarted, _ := json.Marshal(clientEvent{EventsReplayStarted: &eventsReplayStarted{Player: "p1"}})
+	g := NewWithT(t)
+	g.Expect(mockEventSender.allReceivedMessages).To(Equal([][]byte{
+		expectedReplayStarted,
+		[]byte("m1"),
+		[]byte("m2"),
+		expectedReplayEnded,
+	}))
+}
+
+func Test_Replayer_CompactsConnectionChurnAndFallsBackToFullReplay(t *testing.T) {
+	mockEventSender := &mockEventSender{shouldTrackAll: true}
+	replayer := NewEventReplayer(mockEventSender)
+	replayer.compactionThreshold = 5
+
+	replayer.Send(toJsonBytes(clientEvent{PlayerJoined: &playerJoined{Name: "p1"}}))
+	replayer.Send(toJsonBytes(clientEvent{PlayerConnected: &playerConnected{Name: "p1"}}))
+	replayer.Send(toJsonBytes(clientEvent{PlayerDisconnected: &playerDisconnected{Name: "p1"}}))
+	replayer.Send(toJsonBytes(clientEvent{PlayerConnected: &playerConnected{Name: "p1"}}))
+	replayer.Send(toJsonBytes(clientEvent{PlayerJoined: &playerJoined{Name: "p2"}}))
+	mockEventSender.clearAllReceivedMessages()
+
+	replayer.Consume(messagebus.PlayerConnected{Player: "p1", Since: 2})
+
+	expectedReplayStarted, _ := json.Marshal(clientEvent{EventsReplayStarted: &eventsReplayStarted{Player: "p1"}})
+	g := NewWithT(t)
+	g.Expect(mockEventSender.allReceivedMessages).To(Equal([][]byte{
+		expectedReplayStarted,
+		toJsonBytes(clientEvent{Sequence: 1, PlayerJoined: &playerJoined{Name: "p1"}}),
+		toJsonBytes(clientEvent{Sequence: 4, PlayerConnected: &playerConnected{Name: "p1"}}),
+		toJsonBytes(clientEvent{Sequence: 5, PlayerJoined: &playerJoined{Name: "p2"}}),
+		expectedReplayEnded,
+	}))
+	mockEventSender.clearAllReceivedMessages()
+
+	replayer.Send([]byte("m6"))
+	mockEventSender.clearAllReceivedMessages()
+	replayer.Consume(messagebus.PlayerConnected{Player: "p1", Since: 5})
+
+	expectedReplayStarted, _ = json.Marshal(clientEvent{EventsReplayStarted: &eventsReplayStarted{Player: "p1", Since: 5}})
+	g.Expect(mockEventSender.allReceivedMessages).To(Equal([][]byte{
+		expectedReplayStarted,
+		[]byte("m6"),
+		expectedReplayEnded,
+	}))
+}
+
+func Test_Replayer_PollReturnsEventsVisibleToPlayer(t *testing.T) {
+	replayer := NewEventReplayer(&mockEventSender{})
+	replayer.Send([]byte("m1"))
+	replayer.SendToPlayer("p1", []byte("m2"))
+	replayer.SendToPlayer("p2", []byte("m3"))
+	replayer.SendToAllButPlayer("p1", []byte("m4"))
+	replayer.Send([]byte("m5"))
+
+	g := NewWithT(t)
+	g.Expect(replayer.Poll("p1", 0, time.Second)).To(Equal(PolledEvents{
+		Events: []json.RawMessage{json.RawMessage("m1"), json.RawMessage("m2"), json.RawMessage("m5")},
+		Next:   5,
+	}))
+	g.Expect(replayer.Cursor("p1")).To(Equal(5))
+	g.Expect(replayer.Cursor("p2")).To(Equal(0))
+
+	g.Expect(replayer.Poll("p2", 2, time.Second)).To(Equal(PolledEvents{
+		Events: []json.RawMessage{json.RawMessage("m3"), json.RawMessage("m4"), json.RawMessage("m5")},
+		Next:   5,
+	}))
+}
+
+func Test_Replayer_PollTimesOutWithoutNewEvents(t *testing.T) {
+	replayer := NewEventReplayer(&mockEventSender{})
+	replayer.Send([]byte("m1"))
+	replayer.SendToPlayer("p2", []byte("m2"))
+
+	g := NewWithT(t)
+	g.Expect(replayer.Poll("p1", 1, 20*time.Millisecond)).To(Equal(PolledEvents{
+		Events: []json.RawMessage{},
+		Next:   2,
+	}))
+}
+
+func Test_Replayer_PollWaitsForNextEvent(t *testing.T) {
+	replayer := NewEventReplayer(&mockEventSender{})
+	replayer.Send([]byte("m1"))
+
+	polled := make(chan PolledEvents)
+	go func() {
+		polled <- replayer.Poll("p1", 1, 5*time.Second)
+	}()
+	time.Sleep(20 * time.Millisecond)
+	replayer.Send([]byte("m2"))
+
+	g := NewWithT(t)
+	g.Eventually(polled).Should(Receive(Equal(PolledEvents{
+		Events: []json.RawMessage{json.RawMessage("m2")},
+		Next:   2,
+	})))
+}
+
+func Test_Replayer_PollResetsWhenSinceIsUnknown(t *testing.T) {
+	replayer := NewEventReplayer(&mockEventSender{})
+	replayer.Send([]byte("m1"))
+
+	g := NewWithT(t)
+	g.Expect(replayer.Poll("p1", 10, time.Second)).To(Equal(PolledEvents{
+		Events: []json.RawMessage{json.RawMessage("m1")},
+		Next:   1,
+		Reset:  true,
+	}))
+}
+
+func Test_Replayer_LateJoinerReceivesChatHistory(t *testing.T) {
+	mockEventSender := &mockEventSender{shouldTrackAll: true}
+	replayer := NewEventReplayer(mockEventSender)
+	broker := NewClientEventBroker(replayer)
+	sentAt := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	broker.Consume(messagebus.ChatMessageSent{Player: "p1", Channel: messagebus.PublicChannel, Message: "it's Bob", SentAt: sentAt})
+	broker.Consume(messagebus.ChatMessageRejected{Player: "p1", Reason: "too many messages"})
+	mockEventSender.clearAllReceivedMessages()
+
+	replayer.Consume(messagebus.PlayerConnected{Player: "p2"})
+
+	expectedReplayStarted, _ := json.Marshal(clientEvent{EventsReplayStarted: &eventsReplayStarted{Player: "p2"}})
+	expectedChatMessage, _ := json.Marshal(clientEvent{Sequence: 1, ChatMessageSent: &chatMessageSent{Player: "p1", Channel: "public", Message: "it's Bob", SentAt: sentAt}})
+
+	g := NewWithT(t)
+	g.Expect(mockEventSender.allReceivedMessages).To(Equal([][]byte{
+		expectedReplayStarted,
+		expectedChatMessage,
+		expectedReplayEnded,
+	}))
+}
+
+func Test_Replayer_SpyChatOnlyReplayedToSpiesUntilRevealed(t *testing.T) {
+	mockEventSender := &mockEventSender{shouldTrackAll: true}
+	replayer := NewEventReplayer(mockEventSender)
+	broker := NewClientEventBroker(replayer)
+	sentAt := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	spyMessage := messagebus.ChatMessageSent{Player: "spy1", Channel: messagebus.SpyChannel, Recipients: []string{"spy1", "spy2"}, Message: "fail it", SentAt: sentAt}
+	broker.Consume(spyMessage)
+	mockEventSender.clearAllReceivedMessages()
+
+	replayer.Consume(messagebus.PlayerConnected{Player: "resistance"})
+	replayer.Consume(messagebus.PlayerConnected{Player: "spy2"})
+
+	expectedResistanceReplayStarted, _ := json.Marshal(clientEvent{EventsReplayStarted: &eventsReplayStarted{Player: "resistance"}})
+	expectedSpyReplayStarted, _ := json.Marshal(clientEvent{EventsReplayStarted: &eventsReplayStarted{Player: "spy2"}})
+	expectedSpyMessage, _ := json.Marshal(clientEvent{Sequence: 2, ChatMessageSent: &chatMessageSent{Player: "spy1", Channel: "spies", Message: "fail it", SentAt: sentAt}})
+
+	g := NewWithT(t)
+	g.Expect(mockEventSender.allReceivedMessages).To(Equal([][]byte{
+		expectedResistanceReplayStarted,
+		expectedReplayEnded,
+		expectedSpyReplayStarted,
+		expectedSpyMessage,
+		expectedReplayEnded,
+	}))
+
+	broker.Consume(messagebus.SpyChatRevealed{Messages: []messagebus.ChatMessageSent{spyMessage}})
+	mockEventSender.clearAllReceivedMessages()
+	replayer.Consume(messagebus.PlayerConnected{Player: "resistance"})
+
+	expectedReveal, _ := json.Marshal(clientEvent{Sequence: 3, SpyChatRevealed: &spyChatRevealed{Messages: []chatMessageSent{
+		{Player: "spy1", Channel: "spies", Message: "fail it", SentAt: sentAt},
+	}}})
+	g.Expect(mockEventSender.allReceivedMessages).To(Equal([][]byte{
+		expectedResistanceReplayStarted,
+		expectedReveal,
+		expectedReplayEnded,
+	}))
+}
+
+func Test_Replayer_ReplayRewoundDropsPreviousMessages(t *testing.T) {
+	mockEventSender := &mockEventSender{shouldTrackAll: true}
+	replayer := NewEventReplayer(mockEventSender)
+	broker := NewClientEventBroker(replayer)
+	broker.Consume(messagebus.PlayerJoined{Player: "p1"})
+	broker.Consume(messagebus.PlayerJoined{Player: "p2"})
+	broker.Consume(messagebus.ReplayRewound{})
+	broker.Consume(messagebus.PlayerJoined{Player: "p1"})
+	mockEventSender.clearAllReceivedMessages()
+
+	replayer.Consume(messagebus.PlayerConnected{Player: "p1"})
+
+	expectedReplayStarted, _ := json.Marshal(clientEvent{EventsReplayStarted: &eventsReplayStarted{Player: "p1"}})
+	expectedRewound, _ := json.Marshal(clientEvent{Sequence: 3, ReplayRewound: &replayRewound{}})
+	expectedJoined, _ := json.Marshal(clientEvent{Sequence: 4, PlayerJoined: &playerJoined{Name: "p1"}})
+
+	g := NewWithT(t)
+	g.Expect(mockEventSender.allReceivedMessages).To(Equal([][]byte{
+		expectedReplayStarted,
+		expectedRewound,
+		expectedJoined,
+		expectedReplayEnded,
+	}))
+	g.Expect(replayer.Poll("p1", 1, time.Second)).To(Equal(PolledEvents{
+		Events: []json.RawMessage{expectedRewound, expectedJoined},
+		Next:   4,
+		Reset:  true,
+	}))
+	g.Expect(replayer.Poll("p2", 2, time.Second)).To(Equal(PolledEvents{
+		Events: []json.RawMessage{expectedRewound, expectedJoined},
+		Next:   4,
+		Reset:  false,
+	}))
+}
+
+func Test_Replayer_RewindResyncsConnectedPlayersOnceItEnds(t *testing.T) {
+	mockEventSender := &mockEventSender{shouldTrackAll: true, connectedPlayers: []string{"p1"}}
+	replayer := NewEventReplayer(mockEventSender)
+	broker := NewClientEventBroker(replayer)
+	broker.Consume(messagebus.PlayerJoined{Player: "p1"})
+	broker.Consume(messagebus.PlayerJoined{Player: "p2"})
+	mockEventSender.clearAllReceivedMessages()
+
+	broker.Consume(messagebus.ReplayRewound{})
+	broker.Consume(messagebus.PlayerJoined{Player: "p1"})
+
+	g := NewWithT(t)
+	g.Expect(mockEventSender.allReceivedMessages).To(BeNil())
+
+	broker.Consume(messagebus.ReplayRewindEnded{})
+	broker.Consume(messagebus.PlayerJoined{Player: "p2"})
+
+	expectedReplayStarted, _ := json.Marshal(clientEvent{EventsReplayStarted: &eventsReplayStarted{Player: "p1"}})
+	expectedRewound, _ := json.Marshal(clientEvent{Sequence: 3, ReplayRewound: &replayRewound{}})
+	expectedJoined, _ := json.Marshal(clientEvent{Sequence: 4, PlayerJoined: &playerJoined{Name: "p1"}})
+	expectedLiveJoined, _ := json.Marshal(clientEvent{Sequence: 5, PlayerJoined: &playerJoined{Name: "p2"}})
+	g.Expect(mockEventSender.allReceivedMessages).To(Equal([][]byte{
+		expectedReplayStarted,
+		expectedRewound,
+		expectedJoined,
+		expectedReplayEnded,
+		expectedLiveJoined,
+	}))
+}
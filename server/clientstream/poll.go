@@ -0,0 +1,37 @@
+package clientstream
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type eventPoller interface {
+	Cursor(playerName string) int
+	Poll(playerName string, since int, timeout time.Duration) PolledEvents
+}
+
+type pollServer struct {
+	eventPoller eventPoller
+	timeout     time.Duration
+}
+
+func RegisterPoll(engine *gin.Engine, sessionGetter sessionGetter, eventPoller eventPoller, timeout time.Duration) {
+	pollServer := pollServer{
+		eventPoller: eventPoller,
+		timeout:     timeout,
+	}
+
+	engine.GET("/events/poll", checkHttpSession(sessionGetter), pollServer.poll)
+}
+
+func (p pollServer) poll(c *gin.Context) {
+	playerName := c.GetString(playerNameKey)
+
+	since := p.eventPoller.Cursor(playerName)
+	if value, exists := c.GetQuery("since"); exists {
+		since = parseSince(value)
+	}
+
+	c.JSON(200, p.eventPoller.Poll(playerName, since, p.timeout))
+}
@@ -0,0 +1,86 @@
+package clientstream
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/gomega"
+)
+
+type mockEventPoller struct {
+	cursor          int
+	receivedPlayer  string
+	receivedSince   int
+	receivedTimeout time.Duration
+}
+
+func (m *mockEventPoller) Cursor(playerName string) int {
+	return m.cursor
+}
+
+func (m *mockEventPoller) Poll(playerName string, since int, timeout time.Duration) PolledEvents {
+	m.receivedPlayer = playerName
+	m.receivedSince = since
+	m.receivedTimeout = timeout
+	return PolledEvents{Events: []json.RawMessage{json.RawMessage(`{"Sequence":5}`)}, Next: 5}
+}
+
+func makePollCall(req *http.Request, sessionGetter *mockSessionGetter, eventPoller *mockEventPoller) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	ginEngine := gin.New()
+	RegisterPoll(ginEngine, sessionGetter, eventPoller, 3*time.Second)
+
+	w := httptest.NewRecorder()
+	ginEngine.ServeHTTP(w, req)
+	return w
+}
+
+func Test_Poll(t *testing.T) {
+	eventPoller := &mockEventPoller{cursor: 2}
+	req, _ := http.NewRequest("GET", "/events/poll?since=4", nil)
+	req.AddCookie(&http.Cookie{Name: "session", Value: "testSession"})
+	w := makePollCall(req, &mockSessionGetter{}, eventPoller)
+
+	g := NewWithT(t)
+	g.Expect(w.Code).To(Equal(200))
+	g.Expect(w.Body.String()).To(Equal(`{"events":[{"Sequence":5}],"next":5,"reset":false}`))
+	g.Expect(eventPoller.receivedPlayer).To(Equal("testName"))
+	g.Expect(eventPoller.receivedSince).To(Equal(4))
+	g.Expect(eventPoller.receivedTimeout).To(Equal(3 * time.Second))
+}
+
+func Test_Poll_UsesPlayerCursorWithoutSince(t *testing.T) {
+	eventPoller := &mockEventPoller{cursor: 2}
+	req, _ := http.NewRequest("GET", "/events/poll", nil)
+	req.AddCookie(&http.Cookie{Name: "session", Value: "testSession"})
+	makePollCall(req, &mockSessionGetter{}, eventPoller)
+
+	g := NewWithT(t)
+	g.Expect(eventPoller.receivedSince).To(Equal(2))
+}
+
+func Test_Poll_Return401IfNoSessionCookie(t *testing.T) {
+	eventPoller := &mockEventPoller{}
+	req, _ := http.NewRequest("GET", "/events/poll", nil)
+	w := makePollCall(req, &mockSessionGetter{}, eventPoller)
+
+	g := NewWithT(t)
+	g.Expect(w.Code).To(Equal(401))
+	g.Expect(eventPoller.receivedPlayer).To(BeEmpty())
+}
+
+func Test_Poll_Return403IfSessionIsInvalid(t *testing.T) {
+	eventPoller := &mockEventPoller{}
+	req, _ := http.NewRequest("GET", "/events/poll", nil)
+	req.AddCookie(&http.Cookie{Name: "session", Value: "testSession"})
+	w := makePollCall(req, &mockSessionGetter{getError: fmt.Errorf("invalid session")}, eventPoller)
+
+	g := NewWithT(t)
+	g.Expect(w.Code).To(Equal(403))
+	g.Expect(eventPoller.receivedPlayer).To(BeEmpty())
+}
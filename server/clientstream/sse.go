@@ -0,0 +1,75 @@
+package clientstream
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/damien-springuel/bomb-canary/server/sessions"
+	"github.com/gin-gonic/gin"
+)
+
+func checkHttpSession(sessionGetter sessionGetter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		session, err := sessions.FromRequest(c.Request)
+		if err != nil {
+			c.AbortWithStatus(401)
+			return
+		}
+
+		playerName, err := sessionGetter.Get(session)
+		if err != nil {
+			c.AbortWithStatus(403)
+			return
+		}
+
+		c.Set(playerNameKey, playerName)
+
+		c.Next()
+	}
+}
+
+func sequenceOf(message []byte) int {
+	event := clientEvent{}
+	_ = json.Unmarshal(message, &event)
+	return event.Sequence
+}
+
+func (s clientStreamServer) streamServerSentEvents(c *gin.Context) {
+	since := c.GetHeader("Last-Event-ID")
+	if since == "" {
+		since = c.Query("since")
+	}
+	out, closeClientStream := s.clientBroker.Add(c.GetString(playerNameKey), parseSince(since))
+	defer closeClientStream()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Status(200)
+	c.Writer.Flush()
+
+	ticker := time.NewTicker(s.heartbeat.PingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+
+		case <-ticker.C:
+			fmt.Fprint(c.Writer, ": ping\n\n")
+			c.Writer.Flush()
+
+		case messageToSend, ok := <-out:
+			if !ok || len(messageToSend) == 0 {
+				return
+			}
+			sequence := sequenceOf(messageToSend)
+			if sequence > 0 {
+				fmt.Fprintf(c.Writer, "id: %d\n", sequence)
+			}
+			fmt.Fprintf(c.Writer, "data: %s\n\n", messageToSend)
+			c.Writer.Flush()
+		}
+	}
+}
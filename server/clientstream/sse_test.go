@@ -0,0 +1,112 @@
+package clientstream
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/gomega"
+)
+
+func makeSseCall(req *http.Request, sessionGetter *mockSessionGetter, clientBroker *mockClientBroker) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	ginEngine := gin.New()
+	Register(ginEngine, sessionGetter, clientBroker, &mockActionHandler{}, DefaultHeartbeat(), nil)
+
+	w := httptest.NewRecorder()
+	ginEngine.ServeHTTP(w, req)
+	return w
+}
+
+func Test_StreamServerSentEvents(t *testing.T) {
+	clientOut := make(chan []byte, 3)
+	clientOut <- toJsonBytes(clientEvent{EventsReplayStarted: &eventsReplayStarted{Player: "testName"}})
+	clientOut <- toJsonBytes(clientEvent{Sequence: 7, PlayerJoined: &playerJoined{Name: "p1"}})
+	clientOut <- toJsonBytes(clientEvent{EventsReplayEnded: &eventsReplayEnded{}})
+	close(clientOut)
+	clientBroker := &mockClientBroker{channelToReturn: clientOut}
+	sessionGetter := &mockSessionGetter{}
+
+	req, _ := http.NewRequest("GET", "/events/sse", nil)
+	req.AddCookie(&http.Cookie{Name: "session", Value: "testSession"})
+	w := makeSseCall(req, sessionGetter, clientBroker)
+
+	g := NewWithT(t)
+	g.Expect(w.Code).To(Equal(200))
+	g.Expect(w.Header().Get("Content-Type")).To(Equal("text/event-stream"))
+	g.Expect(w.Body.String()).To(Equal(
+		`data: {"EventsReplayStarted":{"Player":"testName"}}` + "\n\n" +
+			"id: 7\n" +
+			`data: {"Sequence":7,"PlayerJoined":{"Name":"p1"}}` + "\n\n" +
+			`data: {"EventsReplayEnded":{}}` + "\n\n",
+	))
+	g.Expect(sessionGetter.receivedSession).To(Equal("testSession"))
+	g.Expect(clientBroker.receivedName).To(Equal("testName"))
+	g.Expect(clientBroker.receivedSince).To(Equal(0))
+	g.Expect(clientBroker.wasCloserCalled()).To(BeTrue())
+}
+
+func Test_StreamServerSentEvents_ResumesFromLastEventId(t *testing.T) {
+	clientOut := make(chan []byte)
+	close(clientOut)
+	clientBroker := &mockClientBroker{channelToReturn: clientOut}
+
+	req, _ := http.NewRequest("GET", "/events/sse?since=3", nil)
+	req.AddCookie(&http.Cookie{Name: "session", Value: "testSession"})
+	req.Header.Set("Last-Event-ID", "42")
+	makeSseCall(req, &mockSessionGetter{}, clientBroker)
+
+	g := NewWithT(t)
+	g.Expect(clientBroker.receivedSince).To(Equal(42))
+}
+
+func Test_StreamServerSentEvents_FallsBackToSinceQuery(t *testing.T) {
+	clientOut := make(chan []byte)
+	close(clientOut)
+	clientBroker := &mockClientBroker{channelToReturn: clientOut}
+
+	req, _ := http.NewRequest("GET", "/events/sse?since=3", nil)
+	req.AddCookie(&http.Cookie{Name: "session", Value: "testSession"})
+	makeSseCall(req, &mockSessionGetter{}, clientBroker)
+
+	g := NewWithT(t)
+	g.Expect(clientBroker.receivedSince).To(Equal(3))
+}
+
+func Test_StreamServerSentEvents_EndsStreamWhenEvicted(t *testing.T) {
+	clientOut := make(chan []byte, 2)
+	clientOut <- []byte("m1")
+	clientOut <- evictedNotice
+	clientBroker := &mockClientBroker{channelToReturn: clientOut}
+
+	req, _ := http.NewRequest("GET", "/events/sse", nil)
+	req.AddCookie(&http.Cookie{Name: "session", Value: "testSession"})
+	w := makeSseCall(req, &mockSessionGetter{}, clientBroker)
+
+	g := NewWithT(t)
+	g.Expect(w.Body.String()).To(Equal("data: m1\n\n"))
+	g.Expect(clientBroker.wasCloserCalled()).To(BeTrue())
+}
+
+func Test_StreamServerSentEvents_Return401IfNoSessionCookie(t *testing.T) {
+	clientBroker := &mockClientBroker{}
+	req, _ := http.NewRequest("GET", "/events/sse", nil)
+	w := makeSseCall(req, &mockSessionGetter{}, clientBroker)
+
+	g := NewWithT(t)
+	g.Expect(w.Code).To(Equal(401))
+	g.Expect(clientBroker.receivedName).To(BeEmpty())
+}
+
+func Test_StreamServerSentEvents_Return403IfSessionIsInvalid(t *testing.T) {
+	clientBroker := &mockClientBroker{}
+	req, _ := http.NewRequest("GET", "/events/sse", nil)
+	req.AddCookie(&http.Cookie{Name: "session", Value: "testSession"})
+	w := makeSseCall(req, &mockSessionGetter{getError: fmt.Errorf("invalid session")}, clientBroker)
+
+	g := NewWithT(t)
+	g.Expect(w.Code).To(Equal(403))
+	g.Expect(clientBroker.receivedName).To(BeEmpty())
+}
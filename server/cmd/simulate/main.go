@@ -0,0 +1,81 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/damien-springuel/bomb-canary/server/simulation"
+)
+
+func parsePlayerCounts(value string) ([]int, error) {
+	counts := []int{}
+	for _, part := range strings.Split(value, ",") {
+		bounds := strings.SplitN(strings.TrimSpace(part), "-", 2)
+		from, err := strconv.Atoi(bounds[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid player count %q: %w", part, err)
+		}
+		to := from
+		if len(bounds) == 2 {
+			to, err = strconv.Atoi(bounds[1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid player count %q: %w", part, err)
+			}
+		}
+		for count := from; count <= to; count++ {
+			counts = append(counts, count)
+		}
+	}
+	return counts, nil
+}
+
+func parseStrategies(value string) ([]simulation.Strategy, error) {
+	strategies := []simulation.Strategy{}
+	for _, name := range strings.Split(value, ",") {
+		strategy, err := simulation.StrategyByName(strings.TrimSpace(name))
+		if err != nil {
+			return nil, err
+		}
+		strategies = append(strategies, strategy)
+	}
+	return strategies, nil
+}
+
+func main() {
+	gamesFlag := flag.Int("games", 1000, "number of games to simulate")
+	playersFlag := flag.String("players", "5-10", "player counts to simulate, e.g. 5,7 or 5-10")
+	strategiesFlag := flag.String("strategies", strings.Join(simulation.StrategyNames(), ","), "comma separated bot strategies to seat at random")
+	seedFlag := flag.Int64("seed", time.Now().UnixNano(), "seed for allegiances, seating and bot decisions")
+	workersFlag := flag.Int("workers", runtime.NumCPU(), "number of games simulated in parallel")
+	flag.Parse()
+
+	playerCounts, err := parsePlayerCounts(*playersFlag)
+	if err != nil {
+		log.Fatalf("can't parse players: %v\n", err)
+	}
+
+	strategies, err := parseStrategies(*strategiesFlag)
+	if err != nil {
+		log.Fatalf("can't parse strategies: %v\n", err)
+	}
+
+	results, err := simulation.Run(simulation.Config{
+		NbGames:      *gamesFlag,
+		PlayerCounts: playerCounts,
+		Strategies:   strategies,
+		Seed:         *seedFlag,
+		Workers:      *workersFlag,
+	})
+	if err != nil {
+		log.Fatalf("can't run simulation: %v\n", err)
+	}
+
+	fmt.Printf("seed: %d\n", *seedFlag)
+	results.WriteTables(os.Stdout)
+}
@@ -1,8 +1,15 @@
 package main
 
 import (
+	"errors"
 	"flag"
+	"os"
 	"strconv"
+	"strings"
+	"time"
+
+	"github.com/damien-springuel/bomb-canary/server/clientstream"
+	"github.com/damien-springuel/bomb-canary/server/tournament"
 )
 
 var IsProd string
@@ -12,30 +19,140 @@ type config struct {
 	port               int
 	allowedOrigins     []string
 	frontendBundlePath string
+	analysisEnabled    bool
+	competitive        bool
+	pingInterval       time.Duration
+	pongTimeout        time.Duration
+	writeTimeout       time.Duration
+	pollTimeout        time.Duration
+	partyName          string
+	sessionKeys        []string
+	sessionTTL         time.Duration
+	spyChatEnabled     bool
+	revealMissionCards bool
+	historyDir         string
+	ratingsFile        string
+	seriesGames        int
+	seriesScoring      tournament.Scoring
+	rematchDelay       time.Duration
+	replayFile         string
+	replayInterval     time.Duration
+}
+
+func splitList(value string) []string {
+	list := []string{}
+	for _, item := range strings.Split(value, ",") {
+		if strings.TrimSpace(item) != "" {
+			list = append(list, strings.TrimSpace(item))
+		}
+	}
+	return list
+}
+
+func (c config) variants() []string {
+	variants := []string{}
+	if c.competitive {
+		variants = append(variants, "competitive")
+	}
+	if c.spyChatEnabled {
+		variants = append(variants, "spy-chat")
+	}
+	if c.revealMissionCards {
+		variants = append(variants, "reveal-mission-cards")
+	}
+	if c.seriesGames > 0 {
+		variants = append(variants, "series")
+	}
+	return variants
+}
+
+func (c config) heartbeat() clientstream.Heartbeat {
+	return clientstream.Heartbeat{
+		PingInterval: c.pingInterval,
+		PongTimeout:  c.pongTimeout,
+		WriteTimeout: c.writeTimeout,
+	}
+}
+
+func (c config) validate() error {
+	if c.pingInterval <= 0 || c.pongTimeout <= 0 || c.writeTimeout <= 0 {
+		return errors.New("-ping-interval, -pong-timeout and -write-timeout must be positive")
+	}
+	if c.pingInterval >= c.pongTimeout {
+		return errors.New("-ping-interval must be shorter than -pong-timeout, or connections are dropped between two pings")
+	}
+	return nil
 }
 
-func GetConfig() config {
+func GetConfig() (config, error) {
 	isProd, err := strconv.ParseBool(IsProd)
 	if err != nil {
 		isProd = false
 	}
 
 	portFlag := flag.Int("port", 44333, "server port")
+	analysisFlag := flag.Bool("analysis", false, "expose the spy probability analysis endpoint")
+	competitiveFlag := flag.Bool("competitive", false, "competitive game, disables player assistance like the analysis endpoint")
+	defaultHeartbeat := clientstream.DefaultHeartbeat()
+	pingIntervalFlag := flag.Duration("ping-interval", defaultHeartbeat.PingInterval, "interval between websocket pings")
+	pongTimeoutFlag := flag.Duration("pong-timeout", defaultHeartbeat.PongTimeout, "time without a pong before a websocket is considered dead")
+	writeTimeoutFlag := flag.Duration("write-timeout", defaultHeartbeat.WriteTimeout, "time allowed to write a message to a websocket")
+	allowedOriginsFlag := flag.String("allowed-origins", "", "comma separated origins allowed to call the api and open the event stream, same origin is always allowed")
+	pollTimeoutFlag := flag.Duration("poll-timeout", 25*time.Second, "how long a long-poll request waits for new events")
+	partyFlag := flag.String("party", "bomb-canary", "party name embedded in session tokens")
+	sessionKeysFlag := flag.String("session-keys", os.Getenv("BOMB_CANARY_SESSION_KEYS"), "comma separated keys signing session tokens, the first one signs and the others are only accepted, defaults to $BOMB_CANARY_SESSION_KEYS")
+	sessionTTLFlag := flag.Duration("session-ttl", 5*time.Hour, "session lifetime, renewed when a session past half its lifetime is used")
+	spyChatFlag := flag.Bool("spy-chat", false, "open a spy only chat channel, revealed to everyone when the game ends")
+	revealMissionCardsFlag := flag.Bool("reveal-mission-cards", false, "show who played the fail cards in the end of game summary")
+	historyDirFlag := flag.String("history-dir", "game-history", "directory where finished games are archived")
+	ratingsFileFlag := flag.String("ratings-file", "ratings.json", "file where the players ratings are kept")
+	seriesGamesFlag := flag.Int("series-games", 0, "play a series of this many games among the same players, with an automatic rematch between games")
+	pointsPerWinFlag := flag.Int("points-per-win", 1, "series points given to each player of the winning team")
+	resistanceWinBonusFlag := flag.Int("resistance-win-bonus", 0, "extra series points for winning as a resistance agent")
+	spyWinBonusFlag := flag.Int("spy-win-bonus", 0, "extra series points for winning as a spy")
+	rematchDelayFlag := flag.Duration("rematch-delay", 20*time.Second, "time between the end of a series game and the rematch")
+	replayFlag := flag.String("replay", "", "archived game to replay in a read-only party instead of hosting a game")
+	replayIntervalFlag := flag.Duration("replay-interval", 2*time.Second, "time between two events when a replay is playing")
 	flag.Parse()
 	port := *portFlag
 
 	frontendBundlePath := "."
-	allowedOrigins := []string{"*"}
+	allowedOrigins := splitList(*allowedOriginsFlag)
 	if !isProd {
 		frontendBundlePath = "../client/dist"
 		port = 44324
-		allowedOrigins = []string{"http://localhost:44322"}
+		if len(allowedOrigins) == 0 {
+			allowedOrigins = []string{"http://localhost:44322"}
+		}
 	}
 
-	return config{
+	c := config{
 		isProd:             isProd,
 		port:               port,
 		allowedOrigins:     allowedOrigins,
 		frontendBundlePath: frontendBundlePath,
+		analysisEnabled:    *analysisFlag,
+		competitive:        *competitiveFlag,
+		pingInterval:       *pingIntervalFlag,
+		pongTimeout:        *pongTimeoutFlag,
+		writeTimeout:       *writeTimeoutFlag,
+		pollTimeout:        *pollTimeoutFlag,
+		partyName:          *partyFlag,
+		sessionKeys:        splitList(*sessionKeysFlag),
+		sessionTTL:         *sessionTTLFlag,
+		spyChatEnabled:     *spyChatFlag,
+		revealMissionCards: *revealMissionCardsFlag,
+		historyDir:         *historyDirFlag,
+		ratingsFile:        *ratingsFileFlag,
+		seriesGames:        *seriesGamesFlag,
+		seriesScoring: tournament.Scoring{
+			PointsPerWin:       *pointsPerWinFlag,
+			ResistanceWinBonus: *resistanceWinBonusFlag,
+			SpyWinBonus:        *spyWinBonusFlag,
+		},
+		rematchDelay:   *rematchDelayFlag,
+		replayFile:     *replayFlag,
+		replayInterval: *replayIntervalFlag,
 	}
+	return c, c.validate()
 }
@@ -30,8 +30,14 @@ func (s *gameHub) Consume(m messagebus.Message) {
 	switch m.(type) {
 	case messagebus.JoinParty:
 		handler = s.handleJoinPartyCommand
+	case messagebus.MarkPlayerReady:
+		handler = s.handleMarkPlayerReady
+	case messagebus.MarkPlayerNotReady:
+		handler = s.handleMarkPlayerNotReady
 	case messagebus.StartGame:
 		handler = s.handleStartGameCommand
+	case messagebus.StartRematch:
+		handler = s.handleStartRematchCommand
 	case messagebus.LeaderSelectsMember:
 		handler = s.handleLeaderSelectsMember
 	case messagebus.LeaderDeselectsMember:
@@ -68,49 +74,113 @@ func (s gameHub) handleJoinPartyCommand(currentGame gamerules.Game, message mess
 				Player: joinPartyCommand.Player,
 			},
 		)
+	} else {
+		messagesToDispatch = append(messagesToDispatch,
+			messagebus.JoinRefused{
+				Player: joinPartyCommand.Player,
+				Reason: err.Error(),
+			},
+		)
 	}
 	return
 }
 
-func (s gameHub) handleStartGameCommand(currentGame gamerules.Game, message messagebus.Message) (updatedGame gamerules.Game, messagesToDispatch []messagebus.Message) {
-	updatedGame, playerAllegiancesByName, missionRequirementsByMission, err := currentGame.Start(s.allegianceGenerator)
+func (s gameHub) handleMarkPlayerReady(currentGame gamerules.Game, message messagebus.Message) (updatedGame gamerules.Game, messagesToDispatch []messagebus.Message) {
+	markPlayerReadyCommand := message.(messagebus.MarkPlayerReady)
+	updatedGame, err := currentGame.MarkPlayerReady(markPlayerReadyCommand.Player)
 
 	if err == nil {
-
-		missionRequirements := make([]messagebus.MissionRequirement, len(missionRequirementsByMission))
-		for i := range missionRequirements {
-			requirement := missionRequirementsByMission[gamerules.Mission(i+1)]
-			missionRequirements[i] = messagebus.MissionRequirement{
-				NbPeopleOnMission:        requirement.NbOfPeopleToGo,
-				NbFailuresRequiredToFail: requirement.NbFailuresRequiredToFailMission,
-			}
-		}
-
 		messagesToDispatch = append(messagesToDispatch,
-			messagebus.GameStarted{
-				MissionRequirements: missionRequirements,
+			messagebus.PlayerReady{
+				Player: markPlayerReadyCommand.Player,
 			},
 		)
+	}
+	return
+}
 
-		allegiances := make(map[string]messagebus.Allegiance)
-		for name, allegiance := range playerAllegiancesByName {
-			allegiances[name] = messagebus.Allegiance(allegiance)
-		}
+func (s gameHub) handleMarkPlayerNotReady(currentGame gamerules.Game, message messagebus.Message) (updatedGame gamerules.Game, messagesToDispatch []messagebus.Message) {
+	markPlayerNotReadyCommand := message.(messagebus.MarkPlayerNotReady)
+	updatedGame, err := currentGame.MarkPlayerNotReady(markPlayerNotReadyCommand.Player)
 
+	if err == nil {
 		messagesToDispatch = append(messagesToDispatch,
-			messagebus.AllegianceRevealed{
-				AllegianceByPlayer: allegiances,
-			},
-		)
-		messagesToDispatch = append(messagesToDispatch,
-			messagebus.LeaderStartedToSelectMembers{
-				Leader: updatedGame.Leader(),
+			messagebus.PlayerNotReady{
+				Player: markPlayerNotReadyCommand.Player,
 			},
 		)
 	}
 	return
 }
 
+func (s gameHub) handleStartGameCommand(currentGame gamerules.Game, message messagebus.Message) (updatedGame gamerules.Game, messagesToDispatch []messagebus.Message) {
+	startGameCommand := message.(messagebus.StartGame)
+
+	var playerAllegiancesByName map[string]gamerules.Allegiance
+	var missionRequirementsByMission map[gamerules.Mission]gamerules.MissionRequirement
+	var err error
+	if startGameCommand.Force {
+		updatedGame, playerAllegiancesByName, missionRequirementsByMission, err = currentGame.ForceStartBy(startGameCommand.Player, s.allegianceGenerator)
+	} else {
+		updatedGame, playerAllegiancesByName, missionRequirementsByMission, err = currentGame.Start(s.allegianceGenerator)
+	}
+
+	if err == nil {
+		messagesToDispatch = gameStartedMessages(updatedGame, playerAllegiancesByName, missionRequirementsByMission)
+	}
+	return
+}
+
+func (s gameHub) handleStartRematchCommand(currentGame gamerules.Game, message messagebus.Message) (updatedGame gamerules.Game, messagesToDispatch []messagebus.Message) {
+	rematch, err := currentGame.Rematch()
+	if err != nil {
+		updatedGame = currentGame
+		return
+	}
+
+	updatedGame, playerAllegiancesByName, missionRequirementsByMission, err := rematch.Start(s.allegianceGenerator)
+	if err != nil {
+		updatedGame = currentGame
+		return
+	}
+
+	messagesToDispatch = append(messagesToDispatch,
+		messagebus.RematchStarted{
+			Players: updatedGame.Players(),
+		},
+	)
+	messagesToDispatch = append(messagesToDispatch, gameStartedMessages(updatedGame, playerAllegiancesByName, missionRequirementsByMission)...)
+	return
+}
+
+func gameStartedMessages(startedGame gamerules.Game, playerAllegiancesByName map[string]gamerules.Allegiance, missionRequirementsByMission map[gamerules.Mission]gamerules.MissionRequirement) []messagebus.Message {
+	missionRequirements := make([]messagebus.MissionRequirement, len(missionRequirementsByMission))
+	for i := range missionRequirements {
+		requirement := missionRequirementsByMission[gamerules.Mission(i+1)]
+		missionRequirements[i] = messagebus.MissionRequirement{
+			NbPeopleOnMission:        requirement.NbOfPeopleToGo,
+			NbFailuresRequiredToFail: requirement.NbFailuresRequiredToFailMission,
+		}
+	}
+
+	allegiances := make(map[string]messagebus.Allegiance)
+	for name, allegiance := range playerAllegiancesByName {
+		allegiances[name] = messagebus.Allegiance(allegiance)
+	}
+
+	return []messagebus.Message{
+		messagebus.GameStarted{
+			MissionRequirements: missionRequirements,
+		},
+		messagebus.AllegianceRevealed{
+			AllegianceByPlayer: allegiances,
+		},
+		messagebus.LeaderStartedToSelectMembers{
+			Leader: startedGame.Leader(),
+		},
+	}
+}
+
 func (s gameHub) handleLeaderSelectsMember(currentGame gamerules.Game, message messagebus.Message) (updatedGame gamerules.Game, messagesToDispatch []messagebus.Message) {
 	leaderSelectsMemberCommand := message.(messagebus.LeaderSelectsMember)
 
@@ -54,7 +54,7 @@ func newlyStartedGame(hub *gameHub) gamerules.Game {
 	hub.Consume(JoinParty{Player: "Charlie"})
 	hub.Consume(JoinParty{Player: "Dan"})
 	hub.Consume(JoinParty{Player: "Edith"})
-	hub.Consume(StartGame{})
+	hub.Consume(StartGame{Player: "Alice", Force: true})
 
 	game := gamerules.NewGame()
 	game, _ = game.AddPlayer("Alice")
@@ -62,7 +62,7 @@ func newlyStartedGame(hub *gameHub) gamerules.Game {
 	game, _ = game.AddPlayer("Charlie")
 	game, _ = game.AddPlayer("Dan")
 	game, _ = game.AddPlayer("Edith")
-	game, _, _, _ = game.Start(spiesFirstGenerator{})
+	game, _, _, _ = game.ForceStartBy("Alice", spiesFirstGenerator{})
 	return game
 }
 
@@ -72,7 +72,7 @@ func newlyConfirmedTeam(hub *gameHub) gamerules.Game {
 	hub.Consume(JoinParty{Player: "Charlie"})
 	hub.Consume(JoinParty{Player: "Dan"})
 	hub.Consume(JoinParty{Player: "Edith"})
-	hub.Consume(StartGame{})
+	hub.Consume(StartGame{Player: "Alice", Force: true})
 	hub.Consume(LeaderSelectsMember{Leader: "Alice", MemberToSelect: "Alice"})
 	hub.Consume(LeaderSelectsMember{Leader: "Alice", MemberToSelect: "Bob"})
 	hub.Consume(LeaderConfirmsTeamSelection{Leader: "Alice"})
@@ -83,7 +83,7 @@ func newlyConfirmedTeam(hub *gameHub) gamerules.Game {
 	game, _ = game.AddPlayer("Charlie")
 	game, _ = game.AddPlayer("Dan")
 	game, _ = game.AddPlayer("Edith")
-	game, _, _, _ = game.Start(spiesFirstGenerator{})
+	game, _, _, _ = game.ForceStartBy("Alice", spiesFirstGenerator{})
 	game, _ = game.LeaderSelectsMember("Alice")
 	game, _ = game.LeaderSelectsMember("Bob")
 	game, _ = game.LeaderConfirmsTeamSelection()
@@ -96,7 +96,7 @@ func fiveFailedVoteInARow(hub *gameHub) gamerules.Game {
 	hub.Consume(JoinParty{Player: "Charlie"})
 	hub.Consume(JoinParty{Player: "Dan"})
 	hub.Consume(JoinParty{Player: "Edith"})
-	hub.Consume(StartGame{})
+	hub.Consume(StartGame{Player: "Alice", Force: true})
 
 	// #1
 	hub.Consume(LeaderSelectsMember{Leader: "Alice", MemberToSelect: "Alice"})
@@ -153,7 +153,7 @@ func fiveFailedVoteInARow(hub *gameHub) gamerules.Game {
 	game, _ = game.AddPlayer("Charlie")
 	game, _ = game.AddPlayer("Dan")
 	game, _ = game.AddPlayer("Edith")
-	game, _, _, _ = game.Start(spiesFirstGenerator{})
+	game, _, _, _ = game.ForceStartBy("Alice", spiesFirstGenerator{})
 
 	// #1
 	game, _ = game.LeaderSelectsMember("Alice")
@@ -212,7 +212,7 @@ func newlyConductingMission(hub *gameHub) gamerules.Game {
 	hub.Consume(JoinParty{Player: "Charlie"})
 	hub.Consume(JoinParty{Player: "Dan"})
 	hub.Consume(JoinParty{Player: "Edith"})
-	hub.Consume(StartGame{})
+	hub.Consume(StartGame{Player: "Alice", Force: true})
 	hub.Consume(LeaderSelectsMember{Leader: "Alice", MemberToSelect: "Alice"})
 	hub.Consume(LeaderSelectsMember{Leader: "Alice", MemberToSelect: "Bob"})
 	hub.Consume(LeaderConfirmsTeamSelection{Leader: "Alice"})
@@ -228,7 +228,7 @@ func newlyConductingMission(hub *gameHub) gamerules.Game {
 	game, _ = game.AddPlayer("Charlie")
 	game, _ = game.AddPlayer("Dan")
 	game, _ = game.AddPlayer("Edith")
-	game, _, _, _ = game.Start(spiesFirstGenerator{})
+	game, _, _, _ = game.ForceStartBy("Alice", spiesFirstGenerator{})
 	game, _ = game.LeaderSelectsMember("Alice")
 	game, _ = game.LeaderSelectsMember("Bob")
 	game, _ = game.LeaderConfirmsTeamSelection()
@@ -246,7 +246,7 @@ func almostThreeSuccessfulMissions(hub *gameHub) gamerules.Game {
 	hub.Consume(JoinParty{Player: "Charlie"})
 	hub.Consume(JoinParty{Player: "Dan"})
 	hub.Consume(JoinParty{Player: "Edith"})
-	hub.Consume(StartGame{})
+	hub.Consume(StartGame{Player: "Alice", Force: true})
 
 	// #1
 	hub.Consume(LeaderSelectsMember{Leader: "Alice", MemberToSelect: "Alice"})
@@ -290,7 +290,7 @@ func almostThreeSuccessfulMissions(hub *gameHub) gamerules.Game {
 	game, _ = game.AddPlayer("Charlie")
 	game, _ = game.AddPlayer("Dan")
 	game, _ = game.AddPlayer("Edith")
-	game, _, _, _ = game.Start(spiesFirstGenerator{})
+	game, _, _, _ = game.ForceStartBy("Alice", spiesFirstGenerator{})
 
 	// #1
 	game, _ = game.LeaderSelectsMember("Alice")
@@ -336,7 +336,7 @@ func almostThreeFailedMissions(hub *gameHub) gamerules.Game {
 	hub.Consume(JoinParty{Player: "Charlie"})
 	hub.Consume(JoinParty{Player: "Dan"})
 	hub.Consume(JoinParty{Player: "Edith"})
-	hub.Consume(StartGame{})
+	hub.Consume(StartGame{Player: "Alice", Force: true})
 
 	// #1
 	hub.Consume(LeaderSelectsMember{Leader: "Alice", MemberToSelect: "Alice"})
@@ -380,7 +380,7 @@ func almostThreeFailedMissions(hub *gameHub) gamerules.Game {
 	game, _ = game.AddPlayer("Charlie")
 	game, _ = game.AddPlayer("Dan")
 	game, _ = game.AddPlayer("Edith")
-	game, _, _, _ = game.Start(spiesFirstGenerator{})
+	game, _, _, _ = game.ForceStartBy("Alice", spiesFirstGenerator{})
 
 	// #1
 	game, _ = game.LeaderSelectsMember("Alice")
@@ -426,7 +426,7 @@ func twoSuccessTwoFailuresLastMission(hub *gameHub) gamerules.Game {
 	hub.Consume(JoinParty{Player: "Charlie"})
 	hub.Consume(JoinParty{Player: "Dan"})
 	hub.Consume(JoinParty{Player: "Edith"})
-	hub.Consume(StartGame{})
+	hub.Consume(StartGame{Player: "Alice", Force: true})
 
 	// #1
 	hub.Consume(LeaderSelectsMember{Leader: "Alice", MemberToSelect: "Alice"})
@@ -497,7 +497,7 @@ func twoSuccessTwoFailuresLastMission(hub *gameHub) gamerules.Game {
 	game, _ = game.AddPlayer("Charlie")
 	game, _ = game.AddPlayer("Dan")
 	game, _ = game.AddPlayer("Edith")
-	game, _, _, _ = game.Start(spiesFirstGenerator{})
+	game, _, _, _ = game.ForceStartBy("Alice", spiesFirstGenerator{})
 
 	// #1
 	game, _ = game.LeaderSelectsMember("Alice")
@@ -576,7 +576,7 @@ func Test_HandleJoinPartyCommand(t *testing.T) {
 	g.Expect(hub.game).To(Equal(expectedGame))
 }
 
-func Test_HandleJoinPartyCommand_IgnoreIfInvalid(t *testing.T) {
+func Test_HandleJoinPartyCommand_RefuseIfInvalid(t *testing.T) {
 	messageDispatcher, hub := setupHub()
 	expectedGame := newlyStartedGame(hub)
 
@@ -584,7 +584,22 @@ func Test_HandleJoinPartyCommand_IgnoreIfInvalid(t *testing.T) {
 	hub.Consume(JoinParty{Player: "Fred"})
 
 	g := NewWithT(t)
-	g.Expect(messageDispatcher.receivedMessages).To(BeEmpty())
+	g.Expect(messageDispatcher.receivedMessages).To(Equal([]Message{
+		JoinRefused{Player: "Fred", Reason: "invalid state for action: can only add player during notStarted state, state was selectingTeam"},
+	}))
+	g.Expect(hub.game).To(Equal(expectedGame))
+}
+
+func Test_HandleJoinPartyCommand_RefuseDuplicateName(t *testing.T) {
+	messageDispatcher, hub := setupHub()
+	hub.Consume(JoinParty{Player: "Alice"})
+	hub.Consume(JoinParty{Player: "Alice"})
+
+	expectedGame := gamerules.NewGame()
+	expectedGame, _ = expectedGame.AddPlayer("Alice")
+
+	g := NewWithT(t)
+	g.Expect(messageDispatcher.lastMessage()).To(Equal(JoinRefused{Player: "Alice", Reason: "player already in group"}))
 	g.Expect(hub.game).To(Equal(expectedGame))
 }
 
@@ -636,6 +651,114 @@ func Test_HandleStartGameCommand_IgnoreIfInvalid(t *testing.T) {
 	g.Expect(hub.game).To(Equal(expectedGame))
 }
 
+func joinFivePlayers(hub *gameHub) gamerules.Game {
+	hub.Consume(JoinParty{Player: "Alice"})
+	hub.Consume(JoinParty{Player: "Bob"})
+	hub.Consume(JoinParty{Player: "Charlie"})
+	hub.Consume(JoinParty{Player: "Dan"})
+	hub.Consume(JoinParty{Player: "Edith"})
+
+	game := gamerules.NewGame()
+	game, _ = game.AddPlayer("Alice")
+	game, _ = game.AddPlayer("Bob")
+	game, _ = game.AddPlayer("Charlie")
+	game, _ = game.AddPlayer("Dan")
+	game, _ = game.AddPlayer("Edith")
+	return game
+}
+
+func Test_HandleMarkPlayerReady(t *testing.T) {
+	messageDispatcher, hub := setupHub()
+	expectedGame := joinFivePlayers(hub)
+	hub.Consume(MarkPlayerReady{Player: "Bob"})
+
+	g := NewWithT(t)
+	g.Expect(messageDispatcher.lastMessage()).To(Equal(PlayerReady{Player: "Bob"}))
+
+	expectedGame, _ = expectedGame.MarkPlayerReady("Bob")
+	g.Expect(hub.game).To(Equal(expectedGame))
+}
+
+func Test_HandleMarkPlayerReady_IgnoreIfInvalid(t *testing.T) {
+	messageDispatcher, hub := setupHub()
+	expectedGame := joinFivePlayers(hub)
+
+	messageDispatcher.clearReceivedMessages()
+	hub.Consume(MarkPlayerReady{Player: "Frank"})
+
+	g := NewWithT(t)
+	g.Expect(messageDispatcher.receivedMessages).To(BeEmpty())
+	g.Expect(hub.game).To(Equal(expectedGame))
+}
+
+func Test_HandleMarkPlayerNotReady(t *testing.T) {
+	messageDispatcher, hub := setupHub()
+	expectedGame := joinFivePlayers(hub)
+	hub.Consume(MarkPlayerReady{Player: "Bob"})
+	hub.Consume(MarkPlayerNotReady{Player: "Bob"})
+
+	g := NewWithT(t)
+	g.Expect(messageDispatcher.lastMessage()).To(Equal(PlayerNotReady{Player: "Bob"}))
+
+	expectedGame, _ = expectedGame.MarkPlayerReady("Bob")
+	expectedGame, _ = expectedGame.MarkPlayerNotReady("Bob")
+	g.Expect(hub.game).To(Equal(expectedGame))
+}
+
+func Test_HandleMarkPlayerNotReady_IgnoreIfInvalid(t *testing.T) {
+	messageDispatcher, hub := setupHub()
+	expectedGame := joinFivePlayers(hub)
+
+	messageDispatcher.clearReceivedMessages()
+	hub.Consume(MarkPlayerNotReady{Player: "Bob"})
+
+	g := NewWithT(t)
+	g.Expect(messageDispatcher.receivedMessages).To(BeEmpty())
+	g.Expect(hub.game).To(Equal(expectedGame))
+}
+
+func Test_HandleStartGameCommand_WhenEveryoneIsReady(t *testing.T) {
+	messageDispatcher, hub := setupHub()
+	expectedGame := joinFivePlayers(hub)
+	for _, player := range []string{"Alice", "Bob", "Charlie", "Dan", "Edith"} {
+		hub.Consume(MarkPlayerReady{Player: player})
+		expectedGame, _ = expectedGame.MarkPlayerReady(player)
+	}
+	hub.Consume(StartGame{Player: "Bob"})
+
+	g := NewWithT(t)
+	g.Expect(messageDispatcher.lastMessage()).To(Equal(LeaderStartedToSelectMembers{Leader: "Alice"}))
+
+	expectedGame, _, _, _ = expectedGame.Start(spiesFirstGenerator{})
+	g.Expect(hub.game).To(Equal(expectedGame))
+}
+
+func Test_HandleStartGameCommand_IgnoreIfNotEveryoneIsReady(t *testing.T) {
+	messageDispatcher, hub := setupHub()
+	expectedGame := joinFivePlayers(hub)
+	hub.Consume(MarkPlayerReady{Player: "Alice"})
+	expectedGame, _ = expectedGame.MarkPlayerReady("Alice")
+
+	messageDispatcher.clearReceivedMessages()
+	hub.Consume(StartGame{Player: "Alice"})
+
+	g := NewWithT(t)
+	g.Expect(messageDispatcher.receivedMessages).To(BeEmpty())
+	g.Expect(hub.game).To(Equal(expectedGame))
+}
+
+func Test_HandleStartGameCommand_IgnoreForceStartIfNotHost(t *testing.T) {
+	messageDispatcher, hub := setupHub()
+	expectedGame := joinFivePlayers(hub)
+
+	messageDispatcher.clearReceivedMessages()
+	hub.Consume(StartGame{Player: "Bob", Force: true})
+
+	g := NewWithT(t)
+	g.Expect(messageDispatcher.receivedMessages).To(BeEmpty())
+	g.Expect(hub.game).To(Equal(expectedGame))
+}
+
 func Test_HandleLeaderSelectsMember(t *testing.T) {
 	messageDispatcher, hub := setupHub()
 	expectedGame := newlyStartedGame(hub)
@@ -1158,3 +1281,42 @@ func Test_HandleFailMission_MissionCompleted_ThirdFailure(t *testing.T) {
 	expectedGame, _, _ = expectedGame.FailMissionBy("Bob")
 	g.Expect(hub.game).To(Equal(expectedGame))
 }
+
+func Test_HandleStartRematchCommand(t *testing.T) {
+	messageDispatcher, hub := setupHub()
+	finishedGame := fiveFailedVoteInARow(hub)
+	hub.Consume(RejectTeam{Player: "Edith"})
+	finishedGame, _, _ = finishedGame.RejectTeamBy("Edith")
+	messageDispatcher.clearReceivedMessages()
+
+	hub.Consume(StartRematch{})
+
+	expectedGame, _ := finishedGame.Rematch()
+	expectedGame, _, _, _ = expectedGame.Start(spiesFirstGenerator{})
+
+	g := NewWithT(t)
+	g.Expect(messageDispatcher.receivedMessages).To(HaveLen(4))
+	g.Expect(messageDispatcher.receivedMessages[0]).To(Equal(RematchStarted{Players: []string{"Bob", "Charlie", "Dan", "Edith", "Alice"}}))
+	g.Expect(messageDispatcher.receivedMessages[1]).To(BeAssignableToTypeOf(GameStarted{}))
+	g.Expect(messageDispatcher.receivedMessages[2]).To(Equal(AllegianceRevealed{AllegianceByPlayer: map[string]Allegiance{
+		"Alice":   Resistance,
+		"Bob":     Spy,
+		"Charlie": Spy,
+		"Dan":     Resistance,
+		"Edith":   Resistance,
+	}}))
+	g.Expect(messageDispatcher.receivedMessages[3]).To(Equal(LeaderStartedToSelectMembers{Leader: "Bob"}))
+	g.Expect(hub.game).To(Equal(expectedGame))
+}
+
+func Test_HandleStartRematchCommand_IgnoreIfGameIsNotOver(t *testing.T) {
+	messageDispatcher, hub := setupHub()
+	expectedGame := newlyStartedGame(hub)
+	messageDispatcher.clearReceivedMessages()
+
+	hub.Consume(StartRematch{})
+
+	g := NewWithT(t)
+	g.Expect(messageDispatcher.receivedMessages).To(BeEmpty())
+	g.Expect(hub.game).To(Equal(expectedGame))
+}
@@ -17,6 +17,8 @@ var (
 	errNotEnoughPlayers          = fmt.Errorf("new at least %d players in game", minNumberOfPlayers)
 	errTeamIsFull                = errors.New("team is maxed out")
 	errTeamIsIncomplete          = errors.New("team is imcomplete")
+	errPlayersNotReady           = errors.New("not all players are ready")
+	errOnlyHostCanForceStart     = errors.New("only the host can force the game to start")
 )
 
 type State string
@@ -114,6 +116,7 @@ var (
 type Game struct {
 	state           State
 	players         players
+	readyPlayers    players
 	leader          string
 	currentTeam     players
 	currentMission  Mission
@@ -157,10 +160,55 @@ func (g Game) removePlayer(name string) (Game, error) {
 	}
 
 	g.players = p
+	g.readyPlayers, _ = g.readyPlayers.remove(name)
+	return g, nil
+}
+
+func (g Game) MarkPlayerReady(name string) (Game, error) {
+	if g.state != NotStarted {
+		return g, fmt.Errorf("%w: can only mark player ready during %s state, state was %s", errInvalidStateForAction, NotStarted, g.state)
+	}
+
+	if !g.players.exists(name) {
+		return g, errPlayerNotFound
+	}
+
+	readyPlayers, err := g.readyPlayers.add(name)
+	if err != nil {
+		return g, err
+	}
+
+	g.readyPlayers = readyPlayers
+	return g, nil
+}
+
+func (g Game) MarkPlayerNotReady(name string) (Game, error) {
+	if g.state != NotStarted {
+		return g, fmt.Errorf("%w: can only mark player not ready during %s state, state was %s", errInvalidStateForAction, NotStarted, g.state)
+	}
+
+	readyPlayers, err := g.readyPlayers.remove(name)
+	if err != nil {
+		return g, err
+	}
+
+	g.readyPlayers = readyPlayers
 	return g, nil
 }
 
 func (g Game) Start(allegianceGenerator AllegianceGenerator) (Game, map[string]Allegiance, map[Mission]MissionRequirement, error) {
+	return g.start(allegianceGenerator, false)
+}
+
+func (g Game) ForceStartBy(name string, allegianceGenerator AllegianceGenerator) (Game, map[string]Allegiance, map[Mission]MissionRequirement, error) {
+	if name != g.Host() {
+		return g, nil, nil, errOnlyHostCanForceStart
+	}
+
+	return g.start(allegianceGenerator, true)
+}
+
+func (g Game) start(allegianceGenerator AllegianceGenerator, force bool) (Game, map[string]Allegiance, map[Mission]MissionRequirement, error) {
 	if g.state != NotStarted {
 		return g, nil, nil, fmt.Errorf("%w: can only start the game during %s state, state was %s", errInvalidStateForAction, NotStarted, g.state)
 	}
@@ -169,6 +217,10 @@ func (g Game) Start(allegianceGenerator AllegianceGenerator) (Game, map[string]A
 		return g, nil, nil, errNotEnoughPlayers
 	}
 
+	if !force && g.readyPlayers.count() != g.players.count() {
+		return g, nil, nil, errPlayersNotReady
+	}
+
 	g.state = SelectingTeam
 	g.leader = g.players[0]
 	g.currentMission = First
@@ -185,6 +237,22 @@ func (g Game) Start(allegianceGenerator AllegianceGenerator) (Game, map[string]A
 	return g, playerAllegiance, g.getMissionRequirements(), nil
 }
 
+func (g Game) Rematch() (Game, error) {
+	if g.state != GameOver {
+		return g, fmt.Errorf("%w: can only start a rematch during %s state, state was %s", errInvalidStateForAction, GameOver, g.state)
+	}
+
+	rematch := NewGame()
+	rematch.players = append(players{}, g.players[1:]...)
+	rematch.players = append(rematch.players, g.players[0])
+	rematch.readyPlayers = append(players{}, rematch.players...)
+	return rematch, nil
+}
+
+func (g Game) Players() []string {
+	return append([]string{}, g.players...)
+}
+
 func (g Game) getMissionRequirements() map[Mission]MissionRequirement {
 	requirements := make(map[Mission]MissionRequirement, len(missionRequirementsByNumberOfPlayer[g.players.count()]))
 	for mission, req := range missionRequirementsByNumberOfPlayer[g.players.count()] {
@@ -340,6 +408,13 @@ func (g Game) FailMissionBy(name string) (Game, map[string]bool, error) {
 	return g.workOnMissionBy(name, g.missionOutcomes.rejectBy)
 }
 
+func (g Game) Host() string {
+	if g.players.count() == 0 {
+		return ""
+	}
+	return g.players[0]
+}
+
 func (g Game) Leader() string {
 	return g.leader
 }
@@ -20,6 +20,13 @@ func (s spiesFirstGenerator) Generate(nbPlayers, nbSpies int) []Allegiance {
 	return allegiances
 }
 
+func markEveryoneReady(game Game) Game {
+	for _, player := range game.players {
+		game, _ = game.MarkPlayerReady(player)
+	}
+	return game
+}
+
 func createNewlyStartedGame() Game {
 	newGame := NewGame()
 	newGame, _ = newGame.AddPlayer("Alice")
@@ -27,6 +34,7 @@ func createNewlyStartedGame() Game {
 	newGame, _ = newGame.AddPlayer("Charlie")
 	newGame, _ = newGame.AddPlayer("Dan")
 	newGame, _ = newGame.AddPlayer("Edith")
+	newGame = markEveryoneReady(newGame)
 	newGame, _, _, _ = newGame.Start(spiesFirstGenerator{})
 	return newGame
 }
@@ -71,6 +79,7 @@ func Test_AddPlayer_ShouldErrorIfGameHasStarted(t *testing.T) {
 	newGame, _ = newGame.AddPlayer("Dan")
 	newGame, _ = newGame.AddPlayer("Edith")
 
+	newGame = markEveryoneReady(newGame)
 	newGame, _, _, _ = newGame.Start(spiesFirstGenerator{})
 
 	_, err := newGame.AddPlayer("Frank")
@@ -134,6 +143,7 @@ func Test_RemovePlayer_ShouldErrorIfGameHasStarted(t *testing.T) {
 	newGame, _ = newGame.AddPlayer("Dan")
 	newGame, _ = newGame.AddPlayer("Edith")
 
+	newGame = markEveryoneReady(newGame)
 	newGame, _, _, _ = newGame.Start(spiesFirstGenerator{})
 
 	_, err := newGame.removePlayer("Bob")
@@ -175,6 +185,7 @@ func Test_StartGame(t *testing.T) {
 	newGame, _ = newGame.AddPlayer("Edith")
 
 	spyGenerator := &spyGenerator{}
+	newGame = markEveryoneReady(newGame)
 	newGame, actualPlayerAllegiance, actualMissionRequirements, err := newGame.Start(spyGenerator)
 
 	g := NewWithT(t)
@@ -210,6 +221,7 @@ func Test_StartGame_ShouldErrorIfGameHasStarted(t *testing.T) {
 	newGame, _ = newGame.AddPlayer("Dan")
 	newGame, _ = newGame.AddPlayer("Edith")
 
+	newGame = markEveryoneReady(newGame)
 	newGame, _, _, _ = newGame.Start(spiesFirstGenerator{})
 	_, _, _, err := newGame.Start(spiesFirstGenerator{})
 
@@ -217,6 +229,124 @@ func Test_StartGame_ShouldErrorIfGameHasStarted(t *testing.T) {
 	g.Expect(err).To(MatchError(errInvalidStateForAction))
 }
 
+func createFivePlayerLobby() Game {
+	newGame := NewGame()
+	newGame, _ = newGame.AddPlayer("Alice")
+	newGame, _ = newGame.AddPlayer("Bob")
+	newGame, _ = newGame.AddPlayer("Charlie")
+	newGame, _ = newGame.AddPlayer("Dan")
+	newGame, _ = newGame.AddPlayer("Edith")
+	return newGame
+}
+
+func Test_MarkPlayerReady(t *testing.T) {
+	newGame := createFivePlayerLobby()
+	newGame, err := newGame.MarkPlayerReady("Bob")
+
+	g := NewWithT(t)
+	g.Expect(err).To(BeNil())
+	g.Expect(newGame.readyPlayers).To(Equal(players{"Bob"}))
+}
+
+func Test_MarkPlayerReady_ShouldErrorIfPlayerNotInGame(t *testing.T) {
+	newGame := createFivePlayerLobby()
+	_, err := newGame.MarkPlayerReady("Frank")
+
+	g := NewWithT(t)
+	g.Expect(err).To(MatchError(errPlayerNotFound))
+}
+
+func Test_MarkPlayerReady_ShouldErrorIfAlreadyReady(t *testing.T) {
+	newGame := createFivePlayerLobby()
+	newGame, _ = newGame.MarkPlayerReady("Bob")
+	_, err := newGame.MarkPlayerReady("Bob")
+
+	g := NewWithT(t)
+	g.Expect(err).To(MatchError(errPlayerAlreadyInGroup))
+}
+
+func Test_MarkPlayerReady_ShouldErrorIfGameHasStarted(t *testing.T) {
+	newGame := createNewlyStartedGame()
+	_, err := newGame.MarkPlayerReady("Bob")
+
+	g := NewWithT(t)
+	g.Expect(err).To(MatchError(errInvalidStateForAction))
+}
+
+func Test_MarkPlayerNotReady(t *testing.T) {
+	newGame := createFivePlayerLobby()
+	newGame, _ = newGame.MarkPlayerReady("Bob")
+	newGame, _ = newGame.MarkPlayerReady("Dan")
+	newGame, err := newGame.MarkPlayerNotReady("Bob")
+
+	g := NewWithT(t)
+	g.Expect(err).To(BeNil())
+	g.Expect(newGame.readyPlayers).To(Equal(players{"Dan"}))
+}
+
+func Test_MarkPlayerNotReady_ShouldErrorIfPlayerWasNotReady(t *testing.T) {
+	newGame := createFivePlayerLobby()
+	_, err := newGame.MarkPlayerNotReady("Bob")
+
+	g := NewWithT(t)
+	g.Expect(err).To(MatchError(errPlayerNotFound))
+}
+
+func Test_RemovePlayer_ShouldForgetReadiness(t *testing.T) {
+	newGame := createFivePlayerLobby()
+	newGame, _ = newGame.MarkPlayerReady("Bob")
+	newGame, _ = newGame.removePlayer("Bob")
+
+	g := NewWithT(t)
+	g.Expect(newGame.readyPlayers).To(BeEmpty())
+}
+
+func Test_StartGame_ShouldErrorIfNotEveryoneIsReady(t *testing.T) {
+	newGame := createFivePlayerLobby()
+	newGame, _ = newGame.MarkPlayerReady("Alice")
+	newGame, _ = newGame.MarkPlayerReady("Bob")
+	newGame, _ = newGame.MarkPlayerReady("Charlie")
+	newGame, _ = newGame.MarkPlayerReady("Dan")
+
+	newGame, _, _, err := newGame.Start(spiesFirstGenerator{})
+
+	g := NewWithT(t)
+	g.Expect(err).To(MatchError(errPlayersNotReady))
+	g.Expect(newGame.State()).To(Equal(NotStarted))
+}
+
+func Test_ForceStartBy_Host(t *testing.T) {
+	newGame := createFivePlayerLobby()
+	newGame, _ = newGame.MarkPlayerReady("Bob")
+
+	newGame, _, _, err := newGame.ForceStartBy("Alice", spiesFirstGenerator{})
+
+	g := NewWithT(t)
+	g.Expect(err).To(BeNil())
+	g.Expect(newGame.Host()).To(Equal("Alice"))
+	g.Expect(newGame.State()).To(Equal(SelectingTeam))
+}
+
+func Test_ForceStartBy_ShouldErrorIfNotHost(t *testing.T) {
+	newGame := createFivePlayerLobby()
+
+	newGame, _, _, err := newGame.ForceStartBy("Bob", spiesFirstGenerator{})
+
+	g := NewWithT(t)
+	g.Expect(err).To(MatchError(errOnlyHostCanForceStart))
+	g.Expect(newGame.State()).To(Equal(NotStarted))
+}
+
+func Test_ForceStartBy_ShouldErrorIfFewerThan5Players(t *testing.T) {
+	newGame := NewGame()
+	newGame, _ = newGame.AddPlayer("Alice")
+
+	_, _, _, err := newGame.ForceStartBy("Alice", spiesFirstGenerator{})
+
+	g := NewWithT(t)
+	g.Expect(err).To(MatchError(errNotEnoughPlayers))
+}
+
 func Test_LeaderSelectsAMember(t *testing.T) {
 	newGame := createNewlyStartedGame()
 
@@ -727,6 +857,7 @@ func Test_SucceedFailMission_ShouldSometimesNeedTwoFailureToFailTheMission(t *te
 	newGame, _ = newGame.AddPlayer("Edith")
 	newGame, _ = newGame.AddPlayer("Fred")
 	newGame, _ = newGame.AddPlayer("Gordon")
+	newGame = markEveryoneReady(newGame)
 	newGame, _, _, _ = newGame.Start(spiesFirstGenerator{})
 
 	// First turn
@@ -822,3 +953,29 @@ func Test_Winner_ReturnsEmptyStringIfNotGameOver(t *testing.T) {
 	g := NewWithT(t)
 	g.Expect(newGame.Winner()).To(Equal(Allegiance("")))
 }
+
+func Test_Rematch(t *testing.T) {
+	finishedGame := createNewlyStartedGame()
+	finishedGame.state = GameOver
+
+	rematch, err := finishedGame.Rematch()
+
+	g := NewWithT(t)
+	g.Expect(err).To(BeNil())
+	g.Expect(rematch.State()).To(Equal(NotStarted))
+	g.Expect(rematch.Players()).To(Equal([]string{"Bob", "Charlie", "Dan", "Edith", "Alice"}))
+	g.Expect(rematch.Host()).To(Equal("Bob"))
+
+	rematch, _, _, err = rematch.Start(spiesFirstGenerator{})
+	g.Expect(err).To(BeNil())
+	g.Expect(rematch.Leader()).To(Equal("Bob"))
+	g.Expect(rematch.Spies()).To(Equal([]string{"Bob", "Charlie"}))
+}
+
+func Test_Rematch_ShouldErrorIfGameIsNotOver(t *testing.T) {
+	newGame := createNewlyStartedGame()
+	_, err := newGame.Rematch()
+
+	g := NewWithT(t)
+	g.Expect(err).To(MatchError(errInvalidStateForAction))
+}
@@ -0,0 +1,54 @@
+package gamestate
+
+import (
+	"github.com/damien-springuel/bomb-canary/server/sessions"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	playerNameKey = "playerName"
+)
+
+type sessionGetter interface {
+	Get(session string) (name string, err error)
+}
+
+type stateGetter interface {
+	State(player string) PlayerState
+}
+
+type gameStateServer struct {
+	sessionGetter sessionGetter
+	stateGetter   stateGetter
+}
+
+func Register(engine *gin.Engine, sessionGetter sessionGetter, stateGetter stateGetter) {
+	gameStateServer := gameStateServer{
+		sessionGetter: sessionGetter,
+		stateGetter:   stateGetter,
+	}
+
+	engine.GET("/game/state", gameStateServer.checkSession, gameStateServer.state)
+}
+
+func (g gameStateServer) checkSession(c *gin.Context) {
+	session, err := sessions.FromRequest(c.Request)
+	if err != nil {
+		c.AbortWithStatus(401)
+		return
+	}
+
+	playerName, err := g.sessionGetter.Get(session)
+	if err != nil {
+		c.AbortWithStatus(403)
+		return
+	}
+
+	c.Set(playerNameKey, playerName)
+
+	c.Next()
+}
+
+func (g gameStateServer) state(c *gin.Context) {
+	c.JSON(200, g.stateGetter.State(c.GetString(playerNameKey)))
+}
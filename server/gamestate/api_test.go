@@ -0,0 +1,89 @@
+package gamestate
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/damien-springuel/bomb-canary/server/gamerules"
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/gomega"
+)
+
+type mockSessionGetter struct {
+	receivedSession string
+	getError        error
+}
+
+func (m *mockSessionGetter) Get(session string) (name string, err error) {
+	m.receivedSession = session
+	return "testName", m.getError
+}
+
+type mockStateGetter struct {
+	receivedPlayer string
+}
+
+func (m *mockStateGetter) State(player string) PlayerState {
+	m.receivedPlayer = player
+	return PlayerState{Phase: gamerules.SelectingTeam, Leader: "testName", Allegiance: "spy"}
+}
+
+func makeCall(req *http.Request, sessionGetter *mockSessionGetter, stateGetter *mockStateGetter) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	ginEngine := gin.New()
+	Register(ginEngine, sessionGetter, stateGetter)
+
+	w := httptest.NewRecorder()
+	ginEngine.ServeHTTP(w, req)
+	return w
+}
+
+func Test_GameState(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/game/state", nil)
+	req.AddCookie(&http.Cookie{Name: "session", Value: "testSession"})
+	sessionGetter := &mockSessionGetter{}
+	stateGetter := &mockStateGetter{}
+	w := makeCall(req, sessionGetter, stateGetter)
+
+	g := NewWithT(t)
+	g.Expect(w.Code).To(Equal(200))
+	g.Expect(w.Body.String()).To(Equal(`{"phase":"selectingTeam","players":null,"readyPlayers":null,"connectedPlayers":null,"missionRequirements":null,"currentMission":0,"leader":"testName","currentTeam":null,"playersWhoVoted":null,"playersWhoWorkedOnMission":null,"missionResults":null,"voteFailures":0,"allegiance":"spy"}`))
+	g.Expect(sessionGetter.receivedSession).To(Equal("testSession"))
+	g.Expect(stateGetter.receivedPlayer).To(Equal("testName"))
+}
+
+func Test_GameState_AcceptsBearerToken(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/game/state", nil)
+	req.Header.Set("Authorization", "Bearer testSession")
+	sessionGetter := &mockSessionGetter{}
+	stateGetter := &mockStateGetter{}
+	w := makeCall(req, sessionGetter, stateGetter)
+
+	g := NewWithT(t)
+	g.Expect(w.Code).To(Equal(200))
+	g.Expect(sessionGetter.receivedSession).To(Equal("testSession"))
+	g.Expect(stateGetter.receivedPlayer).To(Equal("testName"))
+}
+
+func Test_GameState_Returns401IfNoSessionCookie(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/game/state", nil)
+	stateGetter := &mockStateGetter{}
+	w := makeCall(req, &mockSessionGetter{}, stateGetter)
+
+	g := NewWithT(t)
+	g.Expect(w.Code).To(Equal(401))
+	g.Expect(stateGetter.receivedPlayer).To(BeEmpty())
+}
+
+func Test_GameState_Returns403IfSessionInvalid(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/game/state", nil)
+	req.AddCookie(&http.Cookie{Name: "session", Value: "testSession"})
+	stateGetter := &mockStateGetter{}
+	w := makeCall(req, &mockSessionGetter{getError: errors.New("invalid")}, stateGetter)
+
+	g := NewWithT(t)
+	g.Expect(w.Code).To(Equal(403))
+	g.Expect(stateGetter.receivedPlayer).To(BeEmpty())
+}
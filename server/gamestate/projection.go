@@ -0,0 +1,245 @@
+package gamestate
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/damien-springuel/bomb-canary/server/gamerules"
+	"github.com/damien-springuel/bomb-canary/server/messagebus"
+)
+
+type MissionRequirement struct {
+	NbPeopleOnMission        int `json:"nbPeopleOnMission"`
+	NbFailuresRequiredToFail int `json:"nbFailuresRequiredToFail"`
+}
+
+type MissionResult struct {
+	Success bool `json:"success"`
+	NbFails int  `json:"nbFails"`
+}
+
+type PlayerState struct {
+	Phase                     gamerules.State      `json:"phase"`
+	Players                   []string             `json:"players"`
+	ReadyPlayers              []string             `json:"readyPlayers"`
+	ConnectedPlayers          []string             `json:"connectedPlayers"`
+	MissionRequirements       []MissionRequirement `json:"missionRequirements"`
+	CurrentMission            int                  `json:"currentMission"`
+	Leader                    string               `json:"leader"`
+	CurrentTeam               []string             `json:"currentTeam"`
+	PlayersWhoVoted           []string             `json:"playersWhoVoted"`
+	YourVote                  *bool                `json:"yourVote,omitempty"`
+	LastTeamVote              map[string]bool      `json:"lastTeamVote,omitempty"`
+	PlayersWhoWorkedOnMission []string             `json:"playersWhoWorkedOnMission"`
+	YourMissionOutcome        *bool                `json:"yourMissionOutcome,omitempty"`
+	MissionResults            []MissionResult      `json:"missionResults"`
+	VoteFailures              int                  `json:"voteFailures"`
+	Allegiance                string               `json:"allegiance,omitempty"`
+	KnownSpies                []string             `json:"knownSpies,omitempty"`
+	Winner                    string               `json:"winner,omitempty"`
+	Spies                     []string             `json:"spies,omitempty"`
+}
+
+type projection struct {
+	mut                 *sync.RWMutex
+	phase               gamerules.State
+	players             []string
+	ready               map[string]bool
+	connected           map[string]bool
+	missionRequirements []MissionRequirement
+	leader              string
+	team                []string
+	votes               map[string]bool
+	lastTeamVote        map[string]bool
+	missionOutcomes     map[string]bool
+	missionResults      []MissionResult
+	voteFailures        int
+	allegiances         map[string]messagebus.Allegiance
+	winner              messagebus.Allegiance
+	spies               []string
+}
+
+func NewProjection() *projection {
+	return &projection{
+		mut:             &sync.RWMutex{},
+		phase:           gamerules.NotStarted,
+		ready:           make(map[string]bool),
+		connected:       make(map[string]bool),
+		votes:           make(map[string]bool),
+		missionOutcomes: make(map[string]bool),
+		allegiances:     make(map[string]messagebus.Allegiance),
+	}
+}
+
+func (p *projection) Consume(m messagebus.Message) {
+	p.mut.Lock()
+	defer p.mut.Unlock()
+
+	switch m := m.(type) {
+	case messagebus.PlayerConnected:
+		p.connected[m.Player] = true
+
+	case messagebus.PlayerDisconnected:
+		delete(p.connected, m.Player)
+
+	case messagebus.PlayerJoined:
+		p.players = append(p.players, m.Player)
+
+	case messagebus.PlayerReady:
+		p.ready[m.Player] = true
+
+	case messagebus.PlayerNotReady:
+		delete(p.ready, m.Player)
+
+	case messagebus.ReplayRewound:
+		rewound := NewProjection()
+		rewound.mut = p.mut
+		rewound.connected = p.connected
+		*p = *rewound
+
+	case messagebus.RematchStarted:
+		p.phase = gamerules.NotStarted
+		p.players = append([]string{}, m.Players...)
+		p.ready = make(map[string]bool)
+		for _, name := range m.Players {
+			p.ready[name] = true
+		}
+		p.missionRequirements = nil
+		p.leader = ""
+		p.team = nil
+		p.votes = make(map[string]bool)
+		p.lastTeamVote = nil
+		p.missionOutcomes = make(map[string]bool)
+		p.missionResults = nil
+		p.voteFailures = 0
+		p.allegiances = make(map[string]messagebus.Allegiance)
+		p.winner = ""
+		p.spies = nil
+
+	case messagebus.GameStarted:
+		p.phase = gamerules.SelectingTeam
+		p.missionRequirements = make([]MissionRequirement, len(m.MissionRequirements))
+		for i, requirement := range m.MissionRequirements {
+			p.missionRequirements[i] = MissionRequirement{
+				NbPeopleOnMission:        requirement.NbPeopleOnMission,
+				NbFailuresRequiredToFail: requirement.NbFailuresRequiredToFail,
+			}
+		}
+
+	case messagebus.AllegianceRevealed:
+		for name, allegiance := range m.AllegianceByPlayer {
+			p.allegiances[name] = allegiance
+		}
+
+	case messagebus.LeaderStartedToSelectMembers:
+		p.phase = gamerules.SelectingTeam
+		p.leader = m.Leader
+		p.team = nil
+
+	case messagebus.LeaderSelectedMember:
+		p.team = append(p.team, m.SelectedMember)
+
+	case messagebus.LeaderDeselectedMember:
+		p.team = without(p.team, m.DeselectedMember)
+
+	case messagebus.LeaderConfirmedSelection:
+		p.phase = gamerules.VotingOnTeam
+		p.votes = make(map[string]bool)
+
+	case messagebus.PlayerVotedOnTeam:
+		p.votes[m.Player] = m.Approved
+
+	case messagebus.AllPlayerVotedOnTeam:
+		p.voteFailures = m.VoteFailures
+		p.lastTeamVote = m.PlayerVotes
+		p.votes = make(map[string]bool)
+
+	case messagebus.MissionStarted:
+		p.phase = gamerules.ConductingMission
+		p.missionOutcomes = make(map[string]bool)
+
+	case messagebus.PlayerWorkedOnMission:
+		p.missionOutcomes[m.Player] = m.Success
+
+	case messagebus.MissionCompleted:
+		p.missionResults = append(p.missionResults, MissionResult{Success: m.Success, NbFails: m.Outcomes[false]})
+		p.missionOutcomes = make(map[string]bool)
+
+	case messagebus.GameEnded:
+		p.phase = gamerules.GameOver
+		p.winner = m.Winner
+		p.spies = m.Spies
+	}
+}
+
+func (p *projection) State(player string) PlayerState {
+	p.mut.RLock()
+	defer p.mut.RUnlock()
+
+	state := PlayerState{
+		Phase:                     p.phase,
+		Players:                   copyOf(p.players),
+		ReadyPlayers:              sortedKeys(p.ready),
+		ConnectedPlayers:          sortedKeys(p.connected),
+		MissionRequirements:       p.missionRequirements,
+		Leader:                    p.leader,
+		CurrentTeam:               copyOf(p.team),
+		PlayersWhoVoted:           sortedKeys(p.votes),
+		LastTeamVote:              p.lastTeamVote,
+		PlayersWhoWorkedOnMission: sortedKeys(p.missionOutcomes),
+		MissionResults:            append([]MissionResult{}, p.missionResults...),
+		VoteFailures:              p.voteFailures,
+		Allegiance:                string(p.allegiances[player]),
+		Winner:                    string(p.winner),
+		Spies:                     p.spies,
+	}
+
+	if p.phase != gamerules.NotStarted {
+		state.CurrentMission = len(p.missionResults) + 1
+		if p.phase == gamerules.GameOver && len(p.missionResults) > 0 {
+			state.CurrentMission = len(p.missionResults)
+		}
+	}
+
+	if vote, voted := p.votes[player]; voted {
+		state.YourVote = &vote
+	}
+
+	if outcome, worked := p.missionOutcomes[player]; worked {
+		state.YourMissionOutcome = &outcome
+	}
+
+	if p.allegiances[player] == messagebus.Spy {
+		for name, allegiance := range p.allegiances {
+			if allegiance == messagebus.Spy {
+				state.KnownSpies = append(state.KnownSpies, name)
+			}
+		}
+		sort.Strings(state.KnownSpies)
+	}
+
+	return state
+}
+
+func copyOf(names []string) []string {
+	return append([]string{}, names...)
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func without(names []string, name string) []string {
+	remaining := []string{}
+	for _, n := range names {
+		if n != name {
+			remaining = append(remaining, n)
+		}
+	}
+	return remaining
+}
@@ -0,0 +1,212 @@
+package gamestate
+
+import (
+	"testing"
+
+	"github.com/damien-springuel/bomb-canary/server/gamerules"
+	mb "github.com/damien-springuel/bomb-canary/server/messagebus"
+	. "github.com/onsi/gomega"
+)
+
+func boolP(b bool) *bool {
+	return &b
+}
+
+func startedGameProjection() *projection {
+	p := NewProjection()
+	p.Consume(mb.PlayerJoined{Player: "Alice"})
+	p.Consume(mb.PlayerJoined{Player: "Bob"})
+	p.Consume(mb.PlayerJoined{Player: "Charlie"})
+	p.Consume(mb.PlayerJoined{Player: "Dan"})
+	p.Consume(mb.PlayerJoined{Player: "Edith"})
+	p.Consume(mb.PlayerConnected{Player: "Bob"})
+	p.Consume(mb.PlayerConnected{Player: "Alice"})
+	p.Consume(mb.GameStarted{MissionRequirements: []mb.MissionRequirement{
+		{NbPeopleOnMission: 2, NbFailuresRequiredToFail: 1},
+		{NbPeopleOnMission: 3, NbFailuresRequiredToFail: 1},
+	}})
+	p.Consume(mb.AllegianceRevealed{AllegianceByPlayer: map[string]mb.Allegiance{
+		"Alice":   mb.Spy,
+		"Bob":     mb.Spy,
+		"Charlie": mb.Resistance,
+		"Dan":     mb.Resistance,
+		"Edith":   mb.Resistance,
+	}})
+	p.Consume(mb.LeaderStartedToSelectMembers{Leader: "Alice"})
+	return p
+}
+
+func Test_State_Lobby(t *testing.T) {
+	p := NewProjection()
+	p.Consume(mb.PlayerJoined{Player: "Alice"})
+	p.Consume(mb.PlayerConnected{Player: "Alice"})
+
+	g := NewWithT(t)
+	g.Expect(p.State("Alice")).To(Equal(PlayerState{
+		Phase:                     gamerules.NotStarted,
+		Players:                   []string{"Alice"},
+		ReadyPlayers:              []string{},
+		ConnectedPlayers:          []string{"Alice"},
+		CurrentTeam:               []string{},
+		PlayersWhoVoted:           []string{},
+		PlayersWhoWorkedOnMission: []string{},
+		MissionResults:            []MissionResult{},
+	}))
+}
+
+func Test_State_LobbyReadiness(t *testing.T) {
+	p := NewProjection()
+	p.Consume(mb.PlayerJoined{Player: "Alice"})
+	p.Consume(mb.PlayerJoined{Player: "Bob"})
+	p.Consume(mb.PlayerJoined{Player: "Charlie"})
+	p.Consume(mb.PlayerReady{Player: "Charlie"})
+	p.Consume(mb.PlayerReady{Player: "Alice"})
+	p.Consume(mb.PlayerReady{Player: "Bob"})
+	p.Consume(mb.PlayerNotReady{Player: "Bob"})
+
+	g := NewWithT(t)
+	g.Expect(p.State("Bob").ReadyPlayers).To(Equal([]string{"Alice", "Charlie"}))
+}
+
+func Test_State_SpyKnowsOtherSpies(t *testing.T) {
+	p := startedGameProjection()
+
+	g := NewWithT(t)
+	state := p.State("Alice")
+	g.Expect(state.Phase).To(Equal(gamerules.SelectingTeam))
+	g.Expect(state.CurrentMission).To(Equal(1))
+	g.Expect(state.Leader).To(Equal("Alice"))
+	g.Expect(state.ConnectedPlayers).To(Equal([]string{"Alice", "Bob"}))
+	g.Expect(state.MissionRequirements).To(Equal([]MissionRequirement{
+		{NbPeopleOnMission: 2, NbFailuresRequiredToFail: 1},
+		{NbPeopleOnMission: 3, NbFailuresRequiredToFail: 1},
+	}))
+	g.Expect(state.Allegiance).To(Equal("spy"))
+	g.Expect(state.KnownSpies).To(Equal([]string{"Alice", "Bob"}))
+
+	state = p.State("Charlie")
+	g.Expect(state.Allegiance).To(Equal("resistance"))
+	g.Expect(state.KnownSpies).To(BeNil())
+}
+
+func Test_State_Voting(t *testing.T) {
+	p := startedGameProjection()
+	p.Consume(mb.LeaderSelectedMember{SelectedMember: "Charlie"})
+	p.Consume(mb.LeaderSelectedMember{SelectedMember: "Dan"})
+	p.Consume(mb.LeaderDeselectedMember{DeselectedMember: "Charlie"})
+	p.Consume(mb.LeaderSelectedMember{SelectedMember: "Edith"})
+	p.Consume(mb.LeaderConfirmedSelection{})
+	p.Consume(mb.PlayerVotedOnTeam{Player: "Dan", Approved: false})
+	p.Consume(mb.PlayerVotedOnTeam{Player: "Bob", Approved: true})
+
+	g := NewWithT(t)
+	state := p.State("Dan")
+	g.Expect(state.Phase).To(Equal(gamerules.VotingOnTeam))
+	g.Expect(state.CurrentTeam).To(Equal([]string{"Dan", "Edith"}))
+	g.Expect(state.PlayersWhoVoted).To(Equal([]string{"Bob", "Dan"}))
+	g.Expect(state.YourVote).To(Equal(boolP(false)))
+
+	g.Expect(p.State("Edith").YourVote).To(BeNil())
+}
+
+func Test_State_TeamRejected(t *testing.T) {
+	p := startedGameProjection()
+	p.Consume(mb.LeaderSelectedMember{SelectedMember: "Dan"})
+	p.Consume(mb.LeaderConfirmedSelection{})
+	p.Consume(mb.PlayerVotedOnTeam{Player: "Dan", Approved: false})
+	p.Consume(mb.AllPlayerVotedOnTeam{Approved: false, VoteFailures: 1, PlayerVotes: map[string]bool{"Dan": false}})
+	p.Consume(mb.LeaderStartedToSelectMembers{Leader: "Bob"})
+
+	g := NewWithT(t)
+	state := p.State("Dan")
+	g.Expect(state.Phase).To(Equal(gamerules.SelectingTeam))
+	g.Expect(state.Leader).To(Equal("Bob"))
+	g.Expect(state.CurrentTeam).To(BeEmpty())
+	g.Expect(state.PlayersWhoVoted).To(BeEmpty())
+	g.Expect(state.YourVote).To(BeNil())
+	g.Expect(state.VoteFailures).To(Equal(1))
+	g.Expect(state.LastTeamVote).To(Equal(map[string]bool{"Dan": false}))
+}
+
+func Test_State_ConductingMissionAndCompleted(t *testing.T) {
+	p := startedGameProjection()
+	p.Consume(mb.LeaderSelectedMember{SelectedMember: "Alice"})
+	p.Consume(mb.LeaderSelectedMember{SelectedMember: "Dan"})
+	p.Consume(mb.LeaderConfirmedSelection{})
+	p.Consume(mb.AllPlayerVotedOnTeam{Approved: true, PlayerVotes: map[string]bool{"Alice": true}})
+	p.Consume(mb.MissionStarted{})
+	p.Consume(mb.PlayerWorkedOnMission{Player: "Alice", Success: false})
+
+	g := NewWithT(t)
+	state := p.State("Alice")
+	g.Expect(state.Phase).To(Equal(gamerules.ConductingMission))
+	g.Expect(state.PlayersWhoWorkedOnMission).To(Equal([]string{"Alice"}))
+	g.Expect(state.YourMissionOutcome).To(Equal(boolP(false)))
+	g.Expect(p.State("Dan").YourMissionOutcome).To(BeNil())
+
+	p.Consume(mb.PlayerWorkedOnMission{Player: "Dan", Success: true})
+	p.Consume(mb.MissionCompleted{Success: false, Outcomes: map[bool]int{false: 1, true: 1}})
+	p.Consume(mb.LeaderStartedToSelectMembers{Leader: "Bob"})
+
+	state = p.State("Alice")
+	g.Expect(state.CurrentMission).To(Equal(2))
+	g.Expect(state.MissionResults).To(Equal([]MissionResult{{Success: false, NbFails: 1}}))
+	g.Expect(state.PlayersWhoWorkedOnMission).To(BeEmpty())
+}
+
+func Test_State_GameEnded(t *testing.T) {
+	p := startedGameProjection()
+	p.Consume(mb.GameEnded{Winner: mb.Spy, Spies: []string{"Alice", "Bob"}})
+
+	g := NewWithT(t)
+	state := p.State("Charlie")
+	g.Expect(state.Phase).To(Equal(gamerules.GameOver))
+	g.Expect(state.Winner).To(Equal("spy"))
+	g.Expect(state.Spies).To(Equal([]string{"Alice", "Bob"}))
+}
+
+func Test_State_Rematch(t *testing.T) {
+	p := startedGameProjection()
+	p.Consume(mb.MissionCompleted{Success: false, Outcomes: map[bool]int{false: 1}})
+	p.Consume(mb.GameEnded{Winner: mb.Spy, Spies: []string{"Alice", "Bob"}})
+	p.Consume(mb.RematchStarted{Players: []string{"Bob", "Charlie", "Dan", "Edith", "Alice"}})
+	p.Consume(mb.GameStarted{MissionRequirements: []mb.MissionRequirement{{NbPeopleOnMission: 2, NbFailuresRequiredToFail: 1}}})
+	p.Consume(mb.AllegianceRevealed{AllegianceByPlayer: map[string]mb.Allegiance{
+		"Alice":   mb.Resistance,
+		"Bob":     mb.Spy,
+		"Charlie": mb.Spy,
+		"Dan":     mb.Resistance,
+		"Edith":   mb.Resistance,
+	}})
+	p.Consume(mb.LeaderStartedToSelectMembers{Leader: "Bob"})
+
+	g := NewWithT(t)
+	state := p.State("Alice")
+	g.Expect(state.Phase).To(Equal(gamerules.SelectingTeam))
+	g.Expect(state.Players).To(Equal([]string{"Bob", "Charlie", "Dan", "Edith", "Alice"}))
+	g.Expect(state.ReadyPlayers).To(Equal([]string{"Alice", "Bob", "Charlie", "Dan", "Edith"}))
+	g.Expect(state.ConnectedPlayers).To(Equal([]string{"Alice", "Bob"}))
+	g.Expect(state.CurrentMission).To(Equal(1))
+	g.Expect(state.MissionResults).To(BeEmpty())
+	g.Expect(state.Leader).To(Equal("Bob"))
+	g.Expect(state.Allegiance).To(Equal("resistance"))
+	g.Expect(state.KnownSpies).To(BeEmpty())
+	g.Expect(state.Winner).To(Equal(""))
+	g.Expect(state.Spies).To(BeEmpty())
+}
+
+func Test_State_ReplayRewound(t *testing.T) {
+	p := startedGameProjection()
+	p.Consume(mb.ReplayRewound{})
+	p.Consume(mb.PlayerJoined{Player: "Alice"})
+
+	g := NewWithT(t)
+	state := p.State("Alice")
+	g.Expect(state.Phase).To(Equal(gamerules.NotStarted))
+	g.Expect(state.Players).To(Equal([]string{"Alice"}))
+	g.Expect(state.ReadyPlayers).To(BeEmpty())
+	g.Expect(state.ConnectedPlayers).To(Equal([]string{"Alice", "Bob"}))
+	g.Expect(state.MissionRequirements).To(BeEmpty())
+	g.Expect(state.Leader).To(Equal(""))
+	g.Expect(state.Allegiance).To(Equal(""))
+}
@@ -0,0 +1,73 @@
+package history
+
+import (
+	"errors"
+
+	"github.com/damien-springuel/bomb-canary/server/sessions"
+	"github.com/gin-gonic/gin"
+)
+
+type sessionGetter interface {
+	Get(session string) (name string, err error)
+}
+
+type gameArchive interface {
+	List() ([]GameListing, error)
+	Get(id string) (Game, error)
+}
+
+type historyServer struct {
+	sessionGetter sessionGetter
+	gameArchive   gameArchive
+}
+
+func Register(engine *gin.Engine, sessionGetter sessionGetter, gameArchive gameArchive) {
+	historyServer := historyServer{
+		sessionGetter: sessionGetter,
+		gameArchive:   gameArchive,
+	}
+
+	historyGroup := engine.Group("/history", historyServer.checkSession)
+	historyGroup.GET("", historyServer.list)
+	historyGroup.GET("/:id", historyServer.get)
+}
+
+func (h historyServer) checkSession(c *gin.Context) {
+	session, err := sessions.FromRequest(c.Request)
+	if err != nil {
+		c.AbortWithStatus(401)
+		return
+	}
+
+	_, err = h.sessionGetter.Get(session)
+	if err != nil {
+		c.AbortWithStatus(403)
+		return
+	}
+
+	c.Next()
+}
+
+func (h historyServer) list(c *gin.Context) {
+	listings, err := h.gameArchive.List()
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, gin.H{"games": listings})
+}
+
+func (h historyServer) get(c *gin.Context) {
+	game, err := h.gameArchive.Get(c.Param("id"))
+	if errors.Is(err, errGameNotFound) {
+		c.AbortWithStatusJSON(404, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.AbortWithStatusJSON(500, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, game)
+}
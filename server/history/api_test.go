@@ -0,0 +1,129 @@
+package history
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/gomega"
+)
+
+type mockSessionGetter struct {
+	receivedSession string
+	getError        error
+}
+
+func (m *mockSessionGetter) Get(session string) (name string, err error) {
+	m.receivedSession = session
+	return "testName", m.getError
+}
+
+type mockGameArchive struct {
+	receivedId string
+	listError  error
+	getError   error
+}
+
+func (m *mockGameArchive) List() ([]GameListing, error) {
+	return []GameListing{
+		{ID: "1000", EndedAt: time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC), Players: []string{"Alice", "Bob"}, Winner: "spy", Variants: []string{"spy-chat"}},
+	}, m.listError
+}
+
+func (m *mockGameArchive) Get(id string) (Game, error) {
+	m.receivedId = id
+	return Game{
+		GameListing: GameListing{ID: id, EndedAt: time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC), Players: []string{"Alice"}, Winner: "spy", Variants: []string{}},
+		Events:      []LoggedEvent{{Type: "GameEnded", At: time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC), Event: gin.H{"Winner": "spy"}}},
+	}, m.getError
+}
+
+func makeCall(req *http.Request, gameArchive *mockGameArchive) *httptest.ResponseRecorder {
+	req.AddCookie(&http.Cookie{Name: "session", Value: "testSession"})
+	return makeCallWithSession(req, &mockSessionGetter{}, gameArchive)
+}
+
+func makeCallWithSession(req *http.Request, sessionGetter *mockSessionGetter, gameArchive *mockGameArchive) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	ginEngine := gin.New()
+	Register(ginEngine, sessionGetter, gameArchive)
+
+	w := httptest.NewRecorder()
+	ginEngine.ServeHTTP(w, req)
+	return w
+}
+
+func Test_List(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/history", nil)
+	w := makeCall(req, &mockGameArchive{})
+
+	g := NewWithT(t)
+	g.Expect(w.Code).To(Equal(200))
+	g.Expect(w.Body.String()).To(Equal(`{"games":[{"id":"1000","endedAt":"2021-03-04T05:06:07Z","players":["Alice","Bob"],"winner":"spy","variants":["spy-chat"]}]}`))
+}
+
+func Test_List_Returns500OnError(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/history", nil)
+	w := makeCall(req, &mockGameArchive{listError: errors.New("disk error")})
+
+	g := NewWithT(t)
+	g.Expect(w.Code).To(Equal(500))
+	g.Expect(w.Body.String()).To(Equal(`{"error":"disk error"}`))
+}
+
+func Test_Get(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/history/1000", nil)
+	gameArchive := &mockGameArchive{}
+	w := makeCall(req, gameArchive)
+
+	g := NewWithT(t)
+	g.Expect(w.Code).To(Equal(200))
+	g.Expect(w.Body.String()).To(Equal(`{"id":"1000","endedAt":"2021-03-04T05:06:07Z","players":["Alice"],"winner":"spy","variants":[],` +
+		`"events":[{"type":"GameEnded","at":"2021-03-04T05:06:07Z","event":{"Winner":"spy"}}]}`))
+	g.Expect(gameArchive.receivedId).To(Equal("1000"))
+}
+
+func Test_Get_Returns404IfUnknown(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/history/999", nil)
+	w := makeCall(req, &mockGameArchive{getError: errGameNotFound})
+
+	g := NewWithT(t)
+	g.Expect(w.Code).To(Equal(404))
+	g.Expect(w.Body.String()).To(Equal(`{"error":"game not found"}`))
+}
+
+func Test_Get_Returns500OnError(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/history/1000", nil)
+	w := makeCall(req, &mockGameArchive{getError: errors.New("disk error")})
+
+	g := NewWithT(t)
+	g.Expect(w.Code).To(Equal(500))
+}
+
+func Test_History_Returns401IfNoSession(t *testing.T) {
+	g := NewWithT(t)
+	for _, path := range []string{"/history", "/history/1000"} {
+		req, _ := http.NewRequest("GET", path, nil)
+		gameArchive := &mockGameArchive{}
+		w := makeCallWithSession(req, &mockSessionGetter{}, gameArchive)
+
+		g.Expect(w.Code).To(Equal(401))
+		g.Expect(gameArchive.receivedId).To(BeEmpty())
+	}
+}
+
+func Test_History_Returns403IfSessionInvalid(t *testing.T) {
+	g := NewWithT(t)
+	for _, path := range []string{"/history", "/history/1000"} {
+		req, _ := http.NewRequest("GET", path, nil)
+		req.AddCookie(&http.Cookie{Name: "session", Value: "testSession"})
+		gameArchive := &mockGameArchive{}
+		w := makeCallWithSession(req, &mockSessionGetter{getError: errors.New("invalid")}, gameArchive)
+
+		g.Expect(w.Code).To(Equal(403))
+		g.Expect(gameArchive.receivedId).To(BeEmpty())
+	}
+}
@@ -0,0 +1,130 @@
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/damien-springuel/bomb-canary/server/messagebus"
+)
+
+var errGameNotFound = errors.New("game not found")
+
+const gameFileExtension = ".json"
+
+type fileArchive struct {
+	mut *sync.RWMutex
+	dir string
+}
+
+func NewFileArchive(dir string) (*fileArchive, error) {
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return nil, err
+	}
+
+	return &fileArchive{
+		mut: &sync.RWMutex{},
+		dir: dir,
+	}, nil
+}
+
+func (f *fileArchive) Save(game Game) error {
+	f.mut.Lock()
+	defer f.mut.Unlock()
+
+	content, err := json.Marshal(game)
+	if err != nil {
+		return err
+	}
+
+	tmp := f.path(game.ID) + ".tmp"
+	err = os.WriteFile(tmp, content, 0o644)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, f.path(game.ID))
+}
+
+func (f *fileArchive) List() ([]GameListing, error) {
+	f.mut.RLock()
+	defer f.mut.RUnlock()
+
+	entries, err := os.ReadDir(f.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	listings := []GameListing{}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), gameFileExtension) {
+			continue
+		}
+
+		content, err := os.ReadFile(filepath.Join(f.dir, entry.Name()))
+		if err != nil {
+			log.Printf("skipping archived game %s: %v", entry.Name(), err)
+			continue
+		}
+
+		var listing GameListing
+		err = json.Unmarshal(content, &listing)
+		if err != nil {
+			log.Printf("skipping archived game %s: %v", entry.Name(), err)
+			continue
+		}
+		listings = append(listings, listing)
+	}
+
+	sort.Slice(listings, func(i, j int) bool {
+		return listings[i].EndedAt.After(listings[j].EndedAt)
+	})
+	return listings, nil
+}
+
+func (f *fileArchive) Get(id string) (Game, error) {
+	f.mut.RLock()
+	defer f.mut.RUnlock()
+
+	if _, err := strconv.ParseInt(id, 10, 64); err != nil {
+		return Game{}, errGameNotFound
+	}
+
+	game, err := ReadGame(f.path(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return Game{}, errGameNotFound
+	}
+	return game, err
+}
+
+func (f *fileArchive) Summaries() ([]messagebus.GameSummarized, error) {
+	listings, err := f.List()
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := []messagebus.GameSummarized{}
+	for i := len(listings) - 1; i >= 0; i-- {
+		game, err := f.Get(listings[i].ID)
+		if err != nil {
+			log.Printf("skipping archived game %s: %v", listings[i].ID, err)
+			continue
+		}
+		summary, err := game.Summary()
+		if err != nil {
+			continue
+		}
+		summaries = append(summaries, summary)
+	}
+	return summaries, nil
+}
+
+func (f *fileArchive) path(id string) string {
+	return filepath.Join(f.dir, id+gameFileExtension)
+}
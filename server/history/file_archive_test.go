@@ -0,0 +1,108 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/damien-springuel/bomb-canary/server/messagebus"
+	. "github.com/onsi/gomega"
+)
+
+func game(id string, endedAt time.Time) Game {
+	return Game{
+		GameListing: GameListing{
+			ID:       id,
+			EndedAt:  endedAt,
+			Players:  []string{"Alice", "Bob"},
+			Winner:   "spy",
+			Variants: []string{},
+		},
+		Events: []LoggedEvent{{Type: "GameEnded", At: endedAt, Event: map[string]interface{}{"Winner": "spy"}}},
+	}
+}
+
+func Test_FileArchive_SaveAndGet(t *testing.T) {
+	archive, err := NewFileArchive(filepath.Join(t.TempDir(), "history"))
+	g := NewWithT(t)
+	g.Expect(err).To(BeNil())
+
+	saved := game("1000", time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC))
+	g.Expect(archive.Save(saved)).To(Succeed())
+
+	retrieved, err := archive.Get("1000")
+	g.Expect(err).To(BeNil())
+	g.Expect(retrieved).To(Equal(saved))
+}
+
+func Test_FileArchive_ListNewestFirst(t *testing.T) {
+	dir := t.TempDir()
+	archive, _ := NewFileArchive(dir)
+	older := game("1000", time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC))
+	newer := game("2000", time.Date(2021, 3, 5, 5, 6, 7, 0, time.UTC))
+	archive.Save(older)
+	archive.Save(newer)
+	os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not a game"), 0o644)
+	os.WriteFile(filepath.Join(dir, "3000.json"), []byte("{not json"), 0o644)
+
+	listings, err := archive.List()
+
+	g := NewWithT(t)
+	g.Expect(err).To(BeNil())
+	g.Expect(listings).To(Equal([]GameListing{newer.GameListing, older.GameListing}))
+}
+
+func Test_FileArchive_ListEmpty(t *testing.T) {
+	archive, _ := NewFileArchive(t.TempDir())
+	listings, err := archive.List()
+
+	g := NewWithT(t)
+	g.Expect(err).To(BeNil())
+	g.Expect(listings).To(Equal([]GameListing{}))
+}
+
+func Test_FileArchive_GetUnknownGame(t *testing.T) {
+	archive, _ := NewFileArchive(t.TempDir())
+
+	g := NewWithT(t)
+	_, err := archive.Get("1000")
+	g.Expect(err).To(Equal(errGameNotFound))
+	_, err = archive.Get("../secrets")
+	g.Expect(err).To(Equal(errGameNotFound))
+}
+
+func Test_FileArchive_SummariesOldestFirstSkippingUnreadableGames(t *testing.T) {
+	dir := t.TempDir()
+	archive, _ := NewFileArchive(dir)
+	withSummary := func(id string, endedAt time.Time, winner messagebus.Allegiance) Game {
+		game := game(id, endedAt)
+		game.Events = append(game.Events, LoggedEvent{Type: "GameSummarized", At: endedAt, Event: messagebus.GameSummarized{
+			Winner:             winner,
+			AllegianceByPlayer: map[string]messagebus.Allegiance{"Alice": messagebus.Spy, "Bob": messagebus.Resistance},
+			Missions:           []messagebus.MissionSummary{{Mission: 1, Team: []string{"Alice", "Bob"}, NbFails: 1}},
+		}})
+		return game
+	}
+	archive.Save(withSummary("2000", time.Date(2021, 3, 5, 5, 6, 7, 0, time.UTC), messagebus.Resistance))
+	archive.Save(withSummary("1000", time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC), messagebus.Spy))
+	archive.Save(game("3000", time.Date(2021, 3, 6, 5, 6, 7, 0, time.UTC)))
+	os.WriteFile(filepath.Join(dir, "4000.json"), []byte("{not json"), 0o644)
+
+	summaries, err := archive.Summaries()
+
+	g := NewWithT(t)
+	g.Expect(err).To(BeNil())
+	g.Expect(summaries).To(Equal([]messagebus.GameSummarized{
+		{
+			Winner:             messagebus.Spy,
+			AllegianceByPlayer: map[string]messagebus.Allegiance{"Alice": messagebus.Spy, "Bob": messagebus.Resistance},
+			Missions:           []messagebus.MissionSummary{{Mission: 1, Team: []string{"Alice", "Bob"}, NbFails: 1}},
+		},
+		{
+			Winner:             messagebus.Resistance,
+			AllegianceByPlayer: map[string]messagebus.Allegiance{"Alice": messagebus.Spy, "Bob": messagebus.Resistance},
+			Missions:           []messagebus.MissionSummary{{Mission: 1, Team: []string{"Alice", "Bob"}, NbFails: 1}},
+		},
+	}))
+}
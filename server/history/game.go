@@ -0,0 +1,135 @@
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"reflect"
+	"time"
+
+	"github.com/damien-springuel/bomb-canary/server/messagebus"
+)
+
+const (
+	summaryEventType          = "GameSummarized"
+	missionCompletedEventType = "MissionCompleted"
+)
+
+var errNoSummary = errors.New("game has no summary")
+
+type LoggedEvent struct {
+	Type  string      `json:"type"`
+	At    time.Time   `json:"at"`
+	Event interface{} `json:"event"`
+}
+
+type GameListing struct {
+	ID       string    `json:"id"`
+	EndedAt  time.Time `json:"endedAt"`
+	Players  []string  `json:"players"`
+	Winner   string    `json:"winner"`
+	Variants []string  `json:"variants"`
+}
+
+type Game struct {
+	GameListing
+	Events []LoggedEvent `json:"events"`
+}
+
+var replayableEvents = eventTypes(
+	messagebus.PlayerJoined{},
+	messagebus.PlayerReady{},
+	messagebus.PlayerNotReady{},
+	messagebus.ChatMessageSent{},
+	messagebus.SpyChannelOpened{},
+	messagebus.SpyChatRevealed{},
+	messagebus.GameStarted{},
+	messagebus.RematchStarted{},
+	messagebus.AllegianceRevealed{},
+	messagebus.LeaderStartedToSelectMembers{},
+	messagebus.LeaderSelectedMember{},
+	messagebus.LeaderDeselectedMember{},
+	messagebus.LeaderConfirmedSelection{},
+	messagebus.PlayerVotedOnTeam{},
+	messagebus.AllPlayerVotedOnTeam{},
+	messagebus.MissionStarted{},
+	messagebus.PlayerWorkedOnMission{},
+	messagebus.GameEnded{},
+	messagebus.GameSummarized{},
+)
+
+func eventTypes(events ...messagebus.Message) map[string]reflect.Type {
+	types := make(map[string]reflect.Type)
+	for _, event := range events {
+		eventType := reflect.TypeOf(event)
+		types[eventType.Name()] = eventType
+	}
+	return types
+}
+
+func (g Game) Summary() (messagebus.GameSummarized, error) {
+	for _, loggedEvent := range g.Events {
+		if loggedEvent.Type != summaryEventType {
+			continue
+		}
+		var summary messagebus.GameSummarized
+		err := decodeEvent(loggedEvent.Event, &summary)
+		return summary, err
+	}
+	return messagebus.GameSummarized{}, errNoSummary
+}
+
+func (g Game) Messages() ([]messagebus.Message, error) {
+	messages := []messagebus.Message{}
+	for _, loggedEvent := range g.Events {
+		if loggedEvent.Type == missionCompletedEventType {
+			var completed missionCompleted
+			err := decodeEvent(loggedEvent.Event, &completed)
+			if err != nil {
+				return nil, err
+			}
+			messages = append(messages, messagebus.MissionCompleted{
+				Success:  completed.Success,
+				Outcomes: map[bool]int{true: completed.NbSuccesses, false: completed.NbFails},
+			})
+			continue
+		}
+
+		eventType, replayable := replayableEvents[loggedEvent.Type]
+		if !replayable {
+			continue
+		}
+		event := reflect.New(eventType)
+		err := decodeEvent(loggedEvent.Event, event.Interface())
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, event.Elem().Interface().(messagebus.Message))
+	}
+	return messages, nil
+}
+
+func decodeEvent(event interface{}, target interface{}) error {
+	decoded := reflect.ValueOf(event)
+	if decoded.IsValid() && decoded.Type() == reflect.TypeOf(target).Elem() {
+		reflect.ValueOf(target).Elem().Set(decoded)
+		return nil
+	}
+
+	content, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(content, target)
+}
+
+func ReadGame(path string) (Game, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return Game{}, err
+	}
+
+	var game Game
+	err = json.Unmarshal(content, &game)
+	return game, err
+}
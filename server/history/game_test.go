@@ -0,0 +1,71 @@
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/damien-springuel/bomb-canary/server/messagebus"
+	. "github.com/onsi/gomega"
+)
+
+func recordedGame() []messagebus.Message {
+	return []messagebus.Message{
+		messagebus.PlayerJoined{Player: "Alice"},
+		messagebus.PlayerJoined{Player: "Bob"},
+		messagebus.PlayerReady{Player: "Alice"},
+		messagebus.GameStarted{MissionRequirements: []messagebus.MissionRequirement{{NbPeopleOnMission: 2, NbFailuresRequiredToFail: 1}}},
+		messagebus.AllegianceRevealed{AllegianceByPlayer: map[string]messagebus.Allegiance{"Alice": messagebus.Spy, "Bob": messagebus.Resistance}},
+		messagebus.LeaderStartedToSelectMembers{Leader: "Alice"},
+		messagebus.LeaderSelectedMember{SelectedMember: "Bob"},
+		messagebus.LeaderConfirmedSelection{},
+		messagebus.PlayerVotedOnTeam{Player: "Bob", Approved: true},
+		messagebus.AllPlayerVotedOnTeam{Approved: true, PlayerVotes: map[string]bool{"Alice": true, "Bob": true}},
+		messagebus.MissionStarted{},
+		messagebus.PlayerWorkedOnMission{Player: "Alice", Success: false},
+		messagebus.MissionCompleted{Success: false, Outcomes: map[bool]int{true: 1, false: 1}},
+		messagebus.GameEnded{Winner: messagebus.Spy, Spies: []string{"Alice"}},
+		summarized(),
+	}
+}
+
+func Test_Game_Messages(t *testing.T) {
+	archive, _, r := setupRecorder()
+	r.revealMissionCards = true
+	for _, m := range recordedGame() {
+		r.Consume(m)
+	}
+
+	messages, err := archive.savedGames[0].Messages()
+
+	g := NewWithT(t)
+	g.Expect(err).To(BeNil())
+	g.Expect(messages).To(Equal(recordedGame()))
+}
+
+func Test_Game_MessagesFromAnExportedFile(t *testing.T) {
+	archive, err := NewFileArchive(t.TempDir())
+	g := NewWithT(t)
+	g.Expect(err).To(BeNil())
+
+	recorder := NewRecorder(archive, nil, true)
+	recorder.Consume(messagebus.PlayerLoggedOut{Player: "Bob"})
+	for _, m := range recordedGame() {
+		recorder.Consume(m)
+	}
+	listings, err := archive.List()
+	g.Expect(err).To(BeNil())
+
+	game, err := ReadGame(filepath.Join(archive.dir, listings[0].ID+gameFileExtension))
+	g.Expect(err).To(BeNil())
+	messages, err := game.Messages()
+	g.Expect(err).To(BeNil())
+	g.Expect(messages).To(Equal(recordedGame()))
+}
+
+func Test_ReadGame_UnknownFile(t *testing.T) {
+	_, err := ReadGame(filepath.Join(t.TempDir(), "unknown.json"))
+
+	g := NewWithT(t)
+	g.Expect(os.IsNotExist(err)).To(BeTrue())
+}
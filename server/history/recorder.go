@@ -0,0 +1,109 @@
+package history
+
+import (
+	"log"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/damien-springuel/bomb-canary/server/messagebus"
+)
+
+type archive interface {
+	Save(game Game) error
+}
+
+type missionCompleted struct {
+	Success     bool
+	NbSuccesses int
+	NbFails     int
+}
+
+type recorder struct {
+	archive            archive
+	variants           []string
+	revealMissionCards bool
+	now                func() time.Time
+	events             []LoggedEvent
+}
+
+func NewRecorder(archive archive, variants []string, revealMissionCards bool) *recorder {
+	return &recorder{
+		archive:            archive,
+		variants:           append([]string{}, variants...),
+		revealMissionCards: revealMissionCards,
+		now:                time.Now,
+	}
+}
+
+func (r *recorder) Consume(m messagebus.Message) {
+	if m.Type() != messagebus.EventMessage {
+		return
+	}
+
+	switch m := m.(type) {
+	case messagebus.PlayerConnected,
+		messagebus.PlayerDisconnected,
+		messagebus.RejoinCodeIssued,
+		messagebus.JoinRefused,
+		messagebus.ChatMessageRejected:
+		return
+
+	case messagebus.PlayerWorkedOnMission:
+		if !r.revealMissionCards {
+			return
+		}
+
+	case messagebus.GameSummarized:
+		r.log(m)
+		r.archiveGame(m)
+		return
+	}
+
+	r.log(m)
+}
+
+func (r *recorder) log(m messagebus.Message) {
+	var event interface{} = m
+	if completed, ok := m.(messagebus.MissionCompleted); ok {
+		event = missionCompleted{
+			Success:     completed.Success,
+			NbSuccesses: completed.Outcomes[true],
+			NbFails:     completed.Outcomes[false],
+		}
+	}
+
+	r.events = append(r.events, LoggedEvent{
+		Type:  reflect.TypeOf(m).Name(),
+		At:    r.now(),
+		Event: event,
+	})
+}
+
+func (r *recorder) archiveGame(summary messagebus.GameSummarized) {
+	endedAt := r.now()
+	players := make([]string, 0, len(summary.AllegianceByPlayer))
+	for name := range summary.AllegianceByPlayer {
+		players = append(players, name)
+	}
+	sort.Strings(players)
+
+	game := Game{
+		GameListing: GameListing{
+			ID:       strconv.FormatInt(endedAt.UnixMilli(), 10),
+			EndedAt:  endedAt,
+			Players:  players,
+			Winner:   string(summary.Winner),
+			Variants: r.variants,
+		},
+		Events: r.events,
+	}
+
+	err := r.archive.Save(game)
+	if err != nil {
+		log.Printf("can't archive game %s: %v", game.ID, err)
+	}
+
+	r.events = nil
+}
@@ -0,0 +1,108 @@
+package history
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/damien-springuel/bomb-canary/server/messagebus"
+	. "github.com/onsi/gomega"
+)
+
+type mockArchive struct {
+	savedGames []Game
+	saveError  error
+}
+
+func (m *mockArchive) Save(game Game) error {
+	m.savedGames = append(m.savedGames, game)
+	return m.saveError
+}
+
+func setupRecorder() (*mockArchive, time.Time, *recorder) {
+	archive := &mockArchive{}
+	at := time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+	r := NewRecorder(archive, []string{"spy-chat"}, false)
+	r.now = func() time.Time { return at }
+	return archive, at, r
+}
+
+func summarized() messagebus.GameSummarized {
+	return messagebus.GameSummarized{
+		Winner:             messagebus.Resistance,
+		AllegianceByPlayer: map[string]messagebus.Allegiance{"Bob": messagebus.Resistance, "Alice": messagebus.Spy},
+	}
+}
+
+func Test_Recorder_ArchivesGameWhenSummarized(t *testing.T) {
+	archive, at, r := setupRecorder()
+	r.Consume(messagebus.PlayerJoined{Player: "Alice"})
+	r.Consume(messagebus.StartGame{Player: "Alice"})
+	r.Consume(messagebus.MissionCompleted{Success: true, Outcomes: map[bool]int{true: 2}})
+	r.Consume(messagebus.GameEnded{Winner: messagebus.Resistance, Spies: []string{"Alice"}})
+	r.Consume(summarized())
+
+	g := NewWithT(t)
+	g.Expect(archive.savedGames).To(Equal([]Game{
+		{
+			GameListing: GameListing{
+				ID:       "1614834367000",
+				EndedAt:  at,
+				Players:  []string{"Alice", "Bob"},
+				Winner:   "resistance",
+				Variants: []string{"spy-chat"},
+			},
+			Events: []LoggedEvent{
+				{Type: "PlayerJoined", At: at, Event: messagebus.PlayerJoined{Player: "Alice"}},
+				{Type: "MissionCompleted", At: at, Event: missionCompleted{Success: true, NbSuccesses: 2}},
+				{Type: "GameEnded", At: at, Event: messagebus.GameEnded{Winner: messagebus.Resistance, Spies: []string{"Alice"}}},
+				{Type: "GameSummarized", At: at, Event: summarized()},
+			},
+		},
+	}))
+}
+
+func Test_Recorder_SkipsPrivateAndConnectionEvents(t *testing.T) {
+	archive, _, r := setupRecorder()
+	r.Consume(messagebus.PlayerConnected{Player: "Alice"})
+	r.Consume(messagebus.PlayerDisconnected{Player: "Alice"})
+	r.Consume(messagebus.RejoinCodeIssued{Player: "Alice", Code: "ABCD"})
+	r.Consume(messagebus.JoinRefused{Player: "Alice", Reason: "full"})
+	r.Consume(messagebus.ChatMessageRejected{Player: "Alice", Reason: "too many messages"})
+	r.Consume(summarized())
+
+	g := NewWithT(t)
+	g.Expect(archive.savedGames).To(HaveLen(1))
+	g.Expect(archive.savedGames[0].Events).To(HaveLen(1))
+	g.Expect(archive.savedGames[0].Events[0].Type).To(Equal("GameSummarized"))
+}
+
+func Test_Recorder_SkipsMissionCardsUnlessRevealed(t *testing.T) {
+	archive, _, r := setupRecorder()
+	r.Consume(messagebus.PlayerWorkedOnMission{Player: "Alice", Success: false})
+	r.Consume(summarized())
+
+	revealingArchive, at, revealing := setupRecorder()
+	revealing.revealMissionCards = true
+	revealing.Consume(messagebus.PlayerWorkedOnMission{Player: "Alice", Success: false})
+	revealing.Consume(summarized())
+
+	g := NewWithT(t)
+	g.Expect(archive.savedGames[0].Events).To(HaveLen(1))
+	g.Expect(archive.savedGames[0].Events[0].Type).To(Equal("GameSummarized"))
+	g.Expect(revealingArchive.savedGames[0].Events[0]).To(Equal(
+		LoggedEvent{Type: "PlayerWorkedOnMission", At: at, Event: messagebus.PlayerWorkedOnMission{Player: "Alice"}},
+	))
+}
+
+func Test_Recorder_StartsANewLogAfterArchiving(t *testing.T) {
+	archive, _, r := setupRecorder()
+	archive.saveError = errors.New("disk full")
+	r.Consume(messagebus.PlayerJoined{Player: "Alice"})
+	r.Consume(summarized())
+	r.Consume(summarized())
+
+	g := NewWithT(t)
+	g.Expect(archive.savedGames).To(HaveLen(2))
+	g.Expect(archive.savedGames[1].Events).To(HaveLen(1))
+}
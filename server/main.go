@@ -1,23 +1,34 @@
 package main
 
 import (
+	cryptorand "crypto/rand"
 	"fmt"
+	"math/big"
 	"math/rand"
 	"net/http"
-	"sync"
+	"os"
 	"time"
 
+	"github.com/damien-springuel/bomb-canary/server/analysis"
+	"github.com/damien-springuel/bomb-canary/server/chat"
 	"github.com/damien-springuel/bomb-canary/server/clientstream"
+	"github.com/damien-springuel/bomb-canary/server/codegenerator"
 	"github.com/damien-springuel/bomb-canary/server/gamehub"
 	"github.com/damien-springuel/bomb-canary/server/gamerules"
+	"github.com/damien-springuel/bomb-canary/server/gamestate"
+	"github.com/damien-springuel/bomb-canary/server/history"
 	"github.com/damien-springuel/bomb-canary/server/messagebus"
 	"github.com/damien-springuel/bomb-canary/server/messagelogger"
 	"github.com/damien-springuel/bomb-canary/server/party"
 	"github.com/damien-springuel/bomb-canary/server/playeractions"
+	"github.com/damien-springuel/bomb-canary/server/ratings"
 	"github.com/damien-springuel/bomb-canary/server/sessions"
+	"github.com/damien-springuel/bomb-canary/server/stats"
+	"github.com/damien-springuel/bomb-canary/server/summary"
+	"github.com/damien-springuel/bomb-canary/server/tournament"
+	"github.com/damien-springuel/bomb-canary/server/transcript"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 	"github.com/gookit/color"
 )
 
@@ -39,26 +50,24 @@ func (r randomAllegianceGenerator) Generate(nbPlayers, nbSpies int) []gamerules.
 	return allegiances
 }
 
-type uuidV4 struct{}
-
-func (u uuidV4) Create() string {
-	return uuid.New().String()
-}
-
-type easySession struct {
-	mut       *sync.Mutex
-	currentId int
+func randomCodeRune() rune {
+	const alphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+	index, _ := cryptorand.Int(cryptorand.Reader, big.NewInt(int64(len(alphabet))))
+	return rune(alphabet[index.Int64()])
 }
 
-func (e *easySession) Create() string {
-	if e.mut == nil {
-		e.mut = &sync.Mutex{}
+func sessionSecrets(keys []string) [][]byte {
+	secrets := make([][]byte, len(keys))
+	for i, key := range keys {
+		secrets[i] = []byte(key)
+	}
+	if len(secrets) == 0 {
+		blackOnYellow.Println("no session keys configured, sessions won't survive a restart")
+		randomKey := make([]byte, 32)
+		_, _ = cryptorand.Read(randomKey)
+		secrets = append(secrets, randomKey)
 	}
-	e.mut.Lock()
-	defer e.mut.Unlock()
-	e.currentId += 1
-	session := fmt.Sprintf("%d", e.currentId)
-	return session
+	return secrets
 }
 
 var blackOnGreen = color.Style{color.BgLightGreen, color.FgBlack}
@@ -75,40 +84,126 @@ func (c colorPrinter) PrintEvent(m messagebus.Message) {
 }
 
 func main() {
-	config := GetConfig()
+	config, err := GetConfig()
+	if err != nil {
+		blackOnYellow.Printf("invalid configuration %v\n", err)
+		os.Exit(1)
+	}
+	if config.replayFile != "" {
+		serveReplay(config)
+		return
+	}
 
 	bus := messagebus.NewMessageBus()
 	defer bus.Close()
 
-	var sessionCreator interface{ Create() string }
 	if config.isProd {
-		sessionCreator = &uuidV4{}
 		gin.SetMode(gin.ReleaseMode)
 	} else {
 		bus.SubscribeConsumer(messagelogger.New(colorPrinter{}))
-		sessionCreator = &easySession{}
 	}
 
 	hub := gamehub.New(bus, randomAllegianceGenerator{})
 	bus.SubscribeConsumer(hub)
 
+	chatRoom := chat.NewRoom(bus, config.spyChatEnabled)
+	bus.SubscribeConsumer(chatRoom)
+
+	summaryRecorder := summary.NewRecorder(bus, config.revealMissionCards)
+	bus.SubscribeConsumer(summaryRecorder)
+
+	gameArchive, err := history.NewFileArchive(config.historyDir)
+	if err != nil {
+		blackOnYellow.Printf("can't open game history %v\n", err)
+		os.Exit(1)
+	}
+	bus.SubscribeConsumer(history.NewRecorder(gameArchive, config.variants(), config.revealMissionCards))
+
+	archivedSummaries, err := gameArchive.Summaries()
+	if err != nil {
+		blackOnYellow.Printf("can't load game history %v\n", err)
+		os.Exit(1)
+	}
+	statsTracker := stats.NewTracker()
+	statsTracker.Load(archivedSummaries)
+	bus.SubscribeConsumer(statsTracker)
+
+	ratingsLedger, err := ratings.NewLedger(ratings.NewFileStore(config.ratingsFile))
+	if err != nil {
+		blackOnYellow.Printf("can't load ratings %v\n", err)
+		os.Exit(1)
+	}
+	bus.SubscribeConsumer(ratingsLedger)
+
+	series := tournament.NewSeries(bus, config.seriesGames, config.seriesScoring, config.rematchDelay)
+	if config.seriesGames > 0 {
+		bus.SubscribeConsumer(series)
+	}
+
 	clientStreamer := clientstream.NewClientsStreamer(bus)
+	bus.SubscribeConsumer(clientStreamer)
 	eventReplayer := clientstream.NewEventReplayer(clientStreamer)
 	clientEventBroker := clientstream.NewClientEventBroker(eventReplayer)
 	bus.SubscribeConsumer(clientEventBroker)
 	bus.SubscribeConsumer(eventReplayer)
 
-	router := gin.Default()
-	corsConfig := cors.DefaultConfig()
-	corsConfig.AllowCredentials = true
-	corsConfig.AllowOrigins = config.allowedOrigins
-	router.Use(cors.New(corsConfig))
+	gameStateProjection := gamestate.NewProjection()
+	bus.SubscribeConsumer(gameStateProjection)
+
+	analysisTracker := analysis.NewTracker()
+	bus.SubscribeConsumer(analysisTracker)
+
+	transcriptRecorder := transcript.NewRecorder()
+	bus.SubscribeConsumer(transcriptRecorder)
+
+	router := newRouter(config)
+
+	sessions, err := sessions.NewSigned(config.partyName, config.sessionTTL, sessionSecrets(config.sessionKeys)...)
+	if err != nil {
+		blackOnYellow.Printf("can't create sessions %v\n", err)
+		os.Exit(1)
+	}
+	stopSessionCleanUp := sessions.StartCleanUp(time.Minute)
+	defer stopSessionCleanUp()
+	router.Use(party.RenewSession(sessions))
+	partyService := party.NewPartyService(bus, codegenerator.New(randomCodeRune), sessions.TTL())
+	bus.SubscribeConsumer(partyService)
+	party.Register(router, partyService, sessions)
+	actionService := playeractions.NewActionService(bus)
+	playeractions.Register(router, sessions, actionService)
+	clientstream.Register(router, sessions, clientStreamer, playeractions.NewWebsocketActionHandler(actionService), config.heartbeat(), config.allowedOrigins)
+	clientstream.RegisterPoll(router, sessions, eventReplayer, config.pollTimeout)
+	if !config.isProd {
+		clientstream.RegisterStats(router, clientStreamer)
+	}
+	gamestate.Register(router, sessions, gameStateProjection)
+	transcript.Register(router, sessions, transcriptRecorder)
+	history.Register(router, sessions, gameArchive)
+	stats.Register(router, statsTracker)
+	ratings.Register(router, ratingsLedger)
+	if config.seriesGames > 0 {
+		tournament.Register(router, series)
+	}
+	if config.analysisEnabled && !config.competitive {
+		analysis.Register(router, sessions, analysisTracker)
+	}
+
+	serve(router, config)
+}
 
-	sessions := sessions.New(sessionCreator)
-	party.Register(router, party.NewPartyService(bus), sessions)
-	playeractions.Register(router, sessions, playeractions.NewActionService(bus))
-	clientstream.Register(router, sessions, clientStreamer)
+func newRouter(config config) *gin.Engine {
+	router := gin.Default()
+	if len(config.allowedOrigins) > 0 {
+		corsConfig := cors.DefaultConfig()
+		corsConfig.AllowCredentials = true
+		corsConfig.AllowOrigins = config.allowedOrigins
+		corsConfig.ExposeHeaders = []string{"X-Session-Token"}
+		router.Use(cors.New(corsConfig))
+	}
+	return router
+}
 
+func serve(router *gin.Engine, config config) {
 	router.LoadHTMLFiles(config.frontendBundlePath + "/index.html")
 	router.GET("/", func(c *gin.Context) {
 		c.HTML(http.StatusOK, "index.html", nil)
@@ -119,5 +214,6 @@ func main() {
 	blackOnYellow.Printf("serving %s\n", port)
 	if err := http.ListenAndServe(port, router); err != nil {
 		blackOnYellow.Printf("error serving %v\n", err)
+		os.Exit(1)
 	}
 }
@@ -11,8 +11,24 @@ type JoinParty struct {
 	Player string
 }
 
+type MarkPlayerReady struct {
+	Command
+	Player string
+}
+
+type MarkPlayerNotReady struct {
+	Command
+	Player string
+}
+
 type StartGame struct {
 	Command
+	Player string
+	Force  bool
+}
+
+type StartRematch struct {
+	Command
 }
 
 type LeaderSelectsMember struct {
@@ -51,3 +67,10 @@ type FailMission struct {
 	Command
 	Player string
 }
+
+type SendChatMessage struct {
+	Command
+	Player  string
+	Channel ChatChannel
+	Message string
+}
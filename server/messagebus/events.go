@@ -1,5 +1,7 @@
 package messagebus
 
+import "time"
+
 type Event struct{}
 
 func (e Event) Type() Type {
@@ -9,6 +11,7 @@ func (e Event) Type() Type {
 type PlayerConnected struct {
 	Event
 	Player string
+	Since  int
 }
 
 type PlayerDisconnected struct {
@@ -16,11 +19,71 @@ type PlayerDisconnected struct {
 	Player string
 }
 
+type PlayerLoggedOut struct {
+	Event
+	Player string
+	At     time.Time
+}
+
 type PlayerJoined struct {
 	Event
 	Player string
 }
 
+type RejoinCodeIssued struct {
+	Event
+	Player string
+	Code   string
+}
+
+type ChatChannel string
+
+const (
+	PublicChannel ChatChannel = "public"
+	SpyChannel    ChatChannel = "spies"
+)
+
+type ChatMessageSent struct {
+	Event
+	Player     string
+	Channel    ChatChannel
+	Recipients []string
+	Message    string
+	SentAt     time.Time
+}
+
+type SpyChannelOpened struct {
+	Event
+	Spies []string
+}
+
+type SpyChatRevealed struct {
+	Event
+	Messages []ChatMessageSent
+}
+
+type ChatMessageRejected struct {
+	Event
+	Player string
+	Reason string
+}
+
+type JoinRefused struct {
+	Event
+	Player string
+	Reason string
+}
+
+type PlayerReady struct {
+	Event
+	Player string
+}
+
+type PlayerNotReady struct {
+	Event
+	Player string
+}
+
 type MissionRequirement struct {
 	NbPeopleOnMission        int
 	NbFailuresRequiredToFail int
@@ -31,6 +94,11 @@ type GameStarted struct {
 	MissionRequirements []MissionRequirement
 }
 
+type RematchStarted struct {
+	Event
+	Players []string
+}
+
 type AllegianceRevealed struct {
 	Event
 	AllegianceByPlayer map[string]Allegiance
@@ -96,3 +164,42 @@ type GameEnded struct {
 	Winner Allegiance
 	Spies  []string
 }
+
+type TeamProposal struct {
+	Mission  int
+	Leader   string
+	Team     []string
+	Votes    map[string]bool
+	Approved bool
+}
+
+type MissionSummary struct {
+	Mission  int
+	Team     []string
+	Success  bool
+	NbFails  int
+	FailedBy []string
+}
+
+type GameSummarized struct {
+	Event
+	Winner             Allegiance
+	AllegianceByPlayer map[string]Allegiance
+	TeamProposals      []TeamProposal
+	Missions           []MissionSummary
+}
+
+type ReplayRewound struct {
+	Event
+}
+
+type ReplayRewindEnded struct {
+	Event
+}
+
+type ReplayProgressed struct {
+	Event
+	Position int
+	NbEvents int
+	Playing  bool
+}
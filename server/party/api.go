@@ -1,37 +1,76 @@
 package party
 
 import (
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/damien-springuel/bomb-canary/server/sessions"
 	"github.com/gin-gonic/gin"
 )
 
+const (
+	renewedSessionHeader = "X-Session-Token"
+	logoutPath           = "/party/logout"
+)
+
 type joinPartyRequest struct {
 	Name string `json:"name"`
 }
 
+type rejoinPartyRequest struct {
+	Code string `json:"code"`
+}
+
 type partyBroker interface {
-	JoinParty(name string)
+	JoinParty(name string) (rejoinCode string, err error)
+	RejoinWithCode(code string) (name string, err error)
+	Logout(name string)
 }
 
-type sessionCreator interface {
+type sessionStore interface {
 	Create(name string) string
+	Get(session string) (name string, err error)
+	RevokePlayer(name string)
+	TTL() time.Duration
+}
+
+type sessionRenewer interface {
+	Renew(session string) (renewed string, ok bool)
+	TTL() time.Duration
 }
 
 type lobbyServer struct {
-	partyBroker partyBroker
-	session     sessionCreator
+	partyBroker   partyBroker
+	session       sessionStore
+	rejoinLimiter rejoinLimiter
 }
 
-func Register(engine *gin.Engine, partyBroker partyBroker, session sessionCreator) {
+func Register(engine *gin.Engine, partyBroker partyBroker, session sessionStore) {
 	lobbyServer := lobbyServer{
-		partyBroker: partyBroker,
-		session:     session,
+		partyBroker:   partyBroker,
+		session:       session,
+		rejoinLimiter: newRejoinLimiter(),
 	}
 
 	lobbyGroup := engine.Group("/party")
 	lobbyGroup.POST("/join", lobbyServer.joinParty)
+	lobbyGroup.POST("/rejoin", lobbyServer.rejoinParty)
+	lobbyGroup.POST("/logout", lobbyServer.logout)
+}
+
+func RenewSession(sessionRenewer sessionRenewer) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		session, err := sessions.FromRequest(c.Request)
+		if err == nil && c.FullPath() != logoutPath {
+			renewed, ok := sessionRenewer.Renew(session)
+			if ok {
+				setSessionCookie(c, renewed, sessionRenewer.TTL())
+				c.Header(renewedSessionHeader, renewed)
+			}
+		}
+		c.Next()
+	}
 }
 
 func (l lobbyServer) joinParty(c *gin.Context) {
@@ -47,12 +86,75 @@ func (l lobbyServer) joinParty(c *gin.Context) {
 		return
 	}
 
-	l.partyBroker.JoinParty(req.Name)
-	setSessionCookie(c, l.session.Create(req.Name))
+	rejoinCode, err := l.partyBroker.JoinParty(req.Name)
+	if errors.Is(err, errJoinTimedOut) {
+		c.AbortWithStatusJSON(503, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.AbortWithStatusJSON(409, gin.H{"error": err.Error()})
+		return
+	}
+
+	session := l.session.Create(req.Name)
+	setSessionCookie(c, session, l.session.TTL())
+
+	c.JSON(200, gin.H{"token": session, "rejoinCode": rejoinCode})
+}
+
+func (l lobbyServer) rejoinParty(c *gin.Context) {
+	var req rejoinPartyRequest
+	err := c.BindJSON(&req)
+	if err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": fmt.Sprintf("can't bind json: %v", err)})
+		return
+	}
+
+	if req.Code == "" {
+		c.AbortWithStatusJSON(400, gin.H{"error": "code is required"})
+		return
+	}
+
+	if !l.rejoinLimiter.allows(c.ClientIP()) {
+		c.AbortWithStatusJSON(429, gin.H{"error": "too many failed rejoins, try again later"})
+		return
+	}
+
+	name, err := l.partyBroker.RejoinWithCode(req.Code)
+	if err != nil {
+		l.rejoinLimiter.fail(c.ClientIP())
+		c.AbortWithStatusJSON(403, gin.H{"error": err.Error()})
+		return
+	}
+
+	l.session.RevokePlayer(name)
+	l.partyBroker.Logout(name)
+	session := l.session.Create(name)
+	setSessionCookie(c, session, l.session.TTL())
+
+	c.JSON(200, gin.H{"token": session})
+}
+
+func (l lobbyServer) logout(c *gin.Context) {
+	session, err := sessions.FromRequest(c.Request)
+	if err != nil {
+		c.AbortWithStatus(401)
+		return
+	}
+
+	name, err := l.session.Get(session)
+	if err != nil {
+		c.AbortWithStatus(403)
+		return
+	}
+
+	l.session.RevokePlayer(name)
+	l.partyBroker.Logout(name)
+	c.SetCookie("session", "", -1, "/", "", false, true)
 
 	c.JSON(200, gin.H{})
 }
 
-func setSessionCookie(c *gin.Context, session string) {
-	c.SetCookie("session", session, int((5 * time.Hour).Seconds()), "/", "", false, true)
+func setSessionCookie(c *gin.Context, session string, ttl time.Duration) {
+	c.SetCookie("session", session, int(ttl.Seconds()), "/", "", false, true)
 }
@@ -3,6 +3,7 @@ package party
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -10,24 +11,48 @@ import (
 	"testing"
 	"time"
 
+	"github.com/damien-springuel/bomb-canary/server/sessions"
 	"github.com/gin-gonic/gin"
 	. "github.com/onsi/gomega"
 )
 
 type mockPartyBroker struct {
-	givenName string
+	givenName     string
+	joinError     error
+	rejoinError   error
+	rejoinedCode  string
+	loggedOutName string
 }
 
 func (m *mockPartyBroker) CreateParty() string {
 	return "testCode"
 }
 
-func (m *mockPartyBroker) JoinParty(name string) {
+func (m *mockPartyBroker) JoinParty(name string) (rejoinCode string, err error) {
 	m.givenName = name
+	if m.joinError != nil {
+		return "", m.joinError
+	}
+	return "CODE01", nil
+}
+
+func (m *mockPartyBroker) RejoinWithCode(code string) (name string, err error) {
+	m.rejoinedCode = code
+	if m.rejoinError != nil {
+		return "", m.rejoinError
+	}
+	return "testName", nil
+}
+
+func (m *mockPartyBroker) Logout(name string) {
+	m.loggedOutName = name
 }
 
 type mockSession struct {
-	givenName string
+	givenName       string
+	receivedSession string
+	getError        error
+	revokedPlayer   string
 }
 
 func (m *mockSession) Create(name string) string {
@@ -35,19 +60,35 @@ func (m *mockSession) Create(name string) string {
 	return "testSessionId"
 }
 
+func (m *mockSession) Get(session string) (name string, err error) {
+	m.receivedSession = session
+	return "testName", m.getError
+}
+
+func (m *mockSession) RevokePlayer(name string) {
+	m.revokedPlayer = name
+}
+
+func (m *mockSession) TTL() time.Duration {
+	return 5 * time.Hour
+}
+
 func jsonReader(obj interface{}) io.Reader {
 	jsonBytes, _ := json.Marshal(obj)
 	return bytes.NewReader(jsonBytes)
 }
 
 func makeCall(req *http.Request, partyBroker *mockPartyBroker) (*mockPartyBroker, *mockSession, *httptest.ResponseRecorder) {
+	return makeCallWithSession(req, partyBroker, &mockSession{})
+}
+
+func makeCallWithSession(req *http.Request, partyBroker *mockPartyBroker, sessions *mockSession) (*mockPartyBroker, *mockSession, *httptest.ResponseRecorder) {
 	gin.SetMode(gin.TestMode)
 	ginEngine := gin.New()
 
 	if partyBroker == nil {
 		partyBroker = &mockPartyBroker{}
 	}
-	sessions := &mockSession{}
 	Register(ginEngine, partyBroker, sessions)
 
 	w := httptest.NewRecorder()
@@ -62,7 +103,7 @@ func Test_JoinParty(t *testing.T) {
 
 	g := NewWithT(t)
 	g.Expect(w.Code).To(Equal(200))
-	g.Expect(w.Body.String()).To(Equal("{}"))
+	g.Expect(w.Body.String()).To(Equal(`{"rejoinCode":"CODE01","token":"testSessionId"}`))
 
 	actualCookie := w.Result().Cookies()[0]
 	g.Expect(actualCookie.Name).To(Equal("session"))
@@ -97,3 +138,234 @@ func Test_JoinParty_Should400IfMalformedBody(t *testing.T) {
 	g.Expect(*partyBroker).To(Equal(mockPartyBroker{}))
 	g.Expect(*sessions).To(Equal(mockSession{}))
 }
+
+func Test_JoinParty_Should409IfJoinRefused(t *testing.T) {
+	req, _ := http.NewRequest("POST", "/party/join", jsonReader(joinPartyRequest{Name: "testName"}))
+	partyBroker, sessions, w := makeCall(req, &mockPartyBroker{joinError: errors.New("player already in group")})
+
+	g := NewWithT(t)
+	g.Expect(w.Code).To(Equal(409))
+	g.Expect(w.Body.String()).To(Equal(`{"error":"player already in group"}`))
+	g.Expect(w.Result().Cookies()).To(BeEmpty())
+	g.Expect(partyBroker.givenName).To(Equal("testName"))
+	g.Expect(*sessions).To(Equal(mockSession{}))
+}
+
+func Test_JoinParty_Should503IfJoinTimedOut(t *testing.T) {
+	req, _ := http.NewRequest("POST", "/party/join", jsonReader(joinPartyRequest{Name: "testName"}))
+	_, sessions, w := makeCall(req, &mockPartyBroker{joinError: errJoinTimedOut})
+
+	g := NewWithT(t)
+	g.Expect(w.Code).To(Equal(503))
+	g.Expect(w.Result().Cookies()).To(BeEmpty())
+	g.Expect(*sessions).To(Equal(mockSession{}))
+}
+
+func Test_RejoinPartyWithCode(t *testing.T) {
+	req, _ := http.NewRequest("POST", "/party/rejoin", jsonReader(rejoinPartyRequest{Code: "CODE01"}))
+	partyBroker, sessions, w := makeCall(req, nil)
+
+	g := NewWithT(t)
+	g.Expect(w.Code).To(Equal(200))
+	g.Expect(w.Body.String()).To(Equal(`{"token":"testSessionId"}`))
+
+	actualCookie := w.Result().Cookies()[0]
+	g.Expect(actualCookie.Name).To(Equal("session"))
+	g.Expect(actualCookie.Value).To(Equal("testSessionId"))
+
+	g.Expect(partyBroker.rejoinedCode).To(Equal("CODE01"))
+	g.Expect(partyBroker.givenName).To(BeEmpty())
+	g.Expect(partyBroker.loggedOutName).To(Equal("testName"))
+	g.Expect(sessions.revokedPlayer).To(Equal("testName"))
+	g.Expect(sessions.givenName).To(Equal("testName"))
+}
+
+func Test_RejoinPartyWithCode_Should403IfCodeIsInvalid(t *testing.T) {
+	req, _ := http.NewRequest("POST", "/party/rejoin", jsonReader(rejoinPartyRequest{Code: "NOPE00"}))
+	partyBroker, sessions, w := makeCall(req, &mockPartyBroker{rejoinError: errInvalidCode})
+
+	g := NewWithT(t)
+	g.Expect(w.Code).To(Equal(403))
+	g.Expect(w.Body.String()).To(Equal(`{"error":"invalid rejoin code"}`))
+	g.Expect(partyBroker.loggedOutName).To(BeEmpty())
+	g.Expect(*sessions).To(Equal(mockSession{}))
+}
+
+func Test_RejoinPartyWithCode_Should429AfterTooManyFailures(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ginEngine := gin.New()
+	partyBroker := &mockPartyBroker{rejoinError: errInvalidCode}
+	Register(ginEngine, partyBroker, &mockSession{})
+
+	rejoin := func(remoteAddr string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest("POST", "/party/rejoin", jsonReader(rejoinPartyRequest{Code: "NOPE00"}))
+		req.RemoteAddr = remoteAddr
+		w := httptest.NewRecorder()
+		ginEngine.ServeHTTP(w, req)
+		return w
+	}
+
+	g := NewWithT(t)
+	for i := 0; i < defaultFailedRejoinsLimit; i++ {
+		g.Expect(rejoin("10.0.0.1:1234").Code).To(Equal(403))
+	}
+
+	partyBroker.rejoinedCode = ""
+	w := rejoin("10.0.0.1:1234")
+	g.Expect(w.Code).To(Equal(429))
+	g.Expect(w.Body.String()).To(Equal(`{"error":"too many failed rejoins, try again later"}`))
+	g.Expect(partyBroker.rejoinedCode).To(BeEmpty())
+
+	g.Expect(rejoin("10.0.0.2:1234").Code).To(Equal(403))
+}
+
+func Test_RejoinParty_Should400IfCodeAbsent(t *testing.T) {
+	req, _ := http.NewRequest("POST", "/party/rejoin", jsonReader(rejoinPartyRequest{}))
+	partyBroker, sessions, w := makeCall(req, nil)
+
+	g := NewWithT(t)
+	g.Expect(w.Code).To(Equal(400))
+	g.Expect(w.Body.String()).To(Equal(`{"error":"code is required"}`))
+	g.Expect(partyBroker.rejoinedCode).To(BeEmpty())
+	g.Expect(*sessions).To(Equal(mockSession{}))
+}
+
+func Test_Logout(t *testing.T) {
+	req, _ := http.NewRequest("POST", "/party/logout", nil)
+	req.AddCookie(&http.Cookie{Name: "session", Value: "testSession"})
+	partyBroker, sessions, w := makeCall(req, nil)
+
+	g := NewWithT(t)
+	g.Expect(w.Code).To(Equal(200))
+	g.Expect(w.Body.String()).To(Equal("{}"))
+
+	actualCookie := w.Result().Cookies()[0]
+	g.Expect(actualCookie.Name).To(Equal("session"))
+	g.Expect(actualCookie.Value).To(BeEmpty())
+	g.Expect(actualCookie.MaxAge).To(Equal(-1))
+
+	g.Expect(sessions.receivedSession).To(Equal("testSession"))
+	g.Expect(sessions.revokedPlayer).To(Equal("testName"))
+	g.Expect(partyBroker.loggedOutName).To(Equal("testName"))
+}
+
+func Test_Logout_Should401IfNoSession(t *testing.T) {
+	req, _ := http.NewRequest("POST", "/party/logout", nil)
+	partyBroker, sessions, w := makeCall(req, nil)
+
+	g := NewWithT(t)
+	g.Expect(w.Code).To(Equal(401))
+	g.Expect(*partyBroker).To(Equal(mockPartyBroker{}))
+	g.Expect(*sessions).To(Equal(mockSession{}))
+}
+
+func Test_Logout_Should403IfSessionIsInvalid(t *testing.T) {
+	req, _ := http.NewRequest("POST", "/party/logout", nil)
+	req.Header.Set("Authorization", "Bearer testSession")
+	partyBroker, sessions, w := makeCallWithSession(req, nil, &mockSession{getError: errors.New("invalid")})
+
+	g := NewWithT(t)
+	g.Expect(w.Code).To(Equal(403))
+	g.Expect(sessions.revokedPlayer).To(BeEmpty())
+	g.Expect(*partyBroker).To(Equal(mockPartyBroker{}))
+}
+
+type mockSessionRenewer struct {
+	receivedSession string
+	renew           bool
+}
+
+func (m *mockSessionRenewer) Renew(session string) (renewed string, ok bool) {
+	m.receivedSession = session
+	return "renewedSession", m.renew
+}
+
+func (m *mockSessionRenewer) TTL() time.Duration {
+	return time.Hour
+}
+
+func makeRenewCall(req *http.Request, renewer *mockSessionRenewer) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	ginEngine := gin.New()
+	ginEngine.Use(RenewSession(renewer))
+	ginEngine.GET("/anything", func(c *gin.Context) { c.JSON(200, gin.H{}) })
+
+	w := httptest.NewRecorder()
+	ginEngine.ServeHTTP(w, req)
+	return w
+}
+
+func Test_RenewSession(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/anything", nil)
+	req.AddCookie(&http.Cookie{Name: "session", Value: "testSession"})
+	renewer := &mockSessionRenewer{renew: true}
+	w := makeRenewCall(req, renewer)
+
+	g := NewWithT(t)
+	g.Expect(w.Code).To(Equal(200))
+	g.Expect(renewer.receivedSession).To(Equal("testSession"))
+	g.Expect(w.Header().Get("X-Session-Token")).To(Equal("renewedSession"))
+
+	actualCookie := w.Result().Cookies()[0]
+	g.Expect(actualCookie.Value).To(Equal("renewedSession"))
+	g.Expect(actualCookie.MaxAge).To(Equal(int(time.Hour.Seconds())))
+}
+
+func Test_RenewSession_NotDue(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/anything", nil)
+	req.Header.Set("Authorization", "Bearer testSession")
+	renewer := &mockSessionRenewer{renew: false}
+	w := makeRenewCall(req, renewer)
+
+	g := NewWithT(t)
+	g.Expect(w.Code).To(Equal(200))
+	g.Expect(renewer.receivedSession).To(Equal("testSession"))
+	g.Expect(w.Header().Get("X-Session-Token")).To(BeEmpty())
+	g.Expect(w.Result().Cookies()).To(BeEmpty())
+}
+
+func Test_RenewSession_NoSession(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/anything", nil)
+	renewer := &mockSessionRenewer{renew: true}
+	w := makeRenewCall(req, renewer)
+
+	g := NewWithT(t)
+	g.Expect(w.Code).To(Equal(200))
+	g.Expect(renewer.receivedSession).To(BeEmpty())
+	g.Expect(w.Result().Cookies()).To(BeEmpty())
+}
+
+func Test_Logout_RevokesRenewedSessions(t *testing.T) {
+	key := []byte("testKey")
+	// a token from a shorter lived issuer is already past half of the server's ttl
+	shortLived, _ := sessions.NewSigned("testParty", time.Hour, key)
+	server, _ := sessions.NewSigned("testParty", 4*time.Hour, key)
+	oldSession := shortLived.Create("testName")
+
+	gin.SetMode(gin.TestMode)
+	ginEngine := gin.New()
+	ginEngine.Use(RenewSession(server))
+	Register(ginEngine, &mockPartyBroker{}, server)
+	ginEngine.GET("/anything", func(c *gin.Context) { c.JSON(200, gin.H{}) })
+
+	req, _ := http.NewRequest("GET", "/anything", nil)
+	req.Header.Set("Authorization", "Bearer "+oldSession)
+	w := httptest.NewRecorder()
+	ginEngine.ServeHTTP(w, req)
+	renewedSession := w.Header().Get("X-Session-Token")
+
+	req, _ = http.NewRequest("POST", "/party/logout", nil)
+	req.Header.Set("Authorization", "Bearer "+oldSession)
+	w = httptest.NewRecorder()
+	ginEngine.ServeHTTP(w, req)
+
+	g := NewWithT(t)
+	g.Expect(renewedSession).NotTo(BeEmpty())
+	g.Expect(w.Code).To(Equal(200))
+	g.Expect(w.Header().Get("X-Session-Token")).To(BeEmpty())
+
+	_, err := server.Get(oldSession)
+	g.Expect(err).NotTo(BeNil())
+	_, err = server.Get(renewedSession)
+	g.Expect(err).NotTo(BeNil())
+}
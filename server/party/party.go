@@ -1,23 +1,169 @@
 package party
 
 import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
 	"github.com/damien-springuel/bomb-canary/server/messagebus"
 )
 
+const defaultJoinTimeout = 5 * time.Second
+
+var (
+	errJoinTimedOut = errors.New("timed out waiting for the game to accept the join")
+	errInvalidCode  = errors.New("invalid rejoin code")
+)
+
 type dispatcher interface {
 	Dispatch(m messagebus.Message)
 }
 
+type codeGenerator interface {
+	GenerateCode() string
+}
+
 type partyService struct {
-	dispatcher dispatcher
+	dispatcher     dispatcher
+	codeGenerator  codeGenerator
+	joinTimeout    time.Duration
+	now            func() time.Time
+	mut            *sync.Mutex
+	pendingJoins   map[string][]chan error
+	nameByCode     map[string]string
+	codeByName     map[string]string
+	issuedAtByCode map[string]time.Time
+	codeTTL        time.Duration
 }
 
-func NewPartyService(dispatcher dispatcher) partyService {
+func NewPartyService(dispatcher dispatcher, codeGenerator codeGenerator, codeTTL time.Duration) partyService {
 	return partyService{
-		dispatcher: dispatcher,
+		dispatcher:     dispatcher,
+		codeGenerator:  codeGenerator,
+		joinTimeout:    defaultJoinTimeout,
+		now:            time.Now,
+		mut:            &sync.Mutex{},
+		pendingJoins:   make(map[string][]chan error),
+		nameByCode:     make(map[string]string),
+		codeByName:     make(map[string]string),
+		issuedAtByCode: make(map[string]time.Time),
+		codeTTL:        codeTTL,
 	}
 }
 
-func (p partyService) JoinParty(name string) {
+func (p partyService) JoinParty(name string) (rejoinCode string, err error) {
+	decision := make(chan error, 1)
+	p.mut.Lock()
+	p.pendingJoins[name] = append(p.pendingJoins[name], decision)
+	p.mut.Unlock()
+
 	p.dispatcher.Dispatch(messagebus.JoinParty{Player: name})
+
+	select {
+	case err = <-decision:
+	case <-time.After(p.joinTimeout):
+		p.forgetPendingJoin(name, decision)
+		return "", errJoinTimedOut
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return p.issueRejoinCode(name), nil
+}
+
+func (p partyService) issueRejoinCode(name string) string {
+	code := p.codeGenerator.GenerateCode()
+
+	p.mut.Lock()
+	p.forgetCode(p.codeByName[name])
+	p.forgetExpiredCodes()
+	p.nameByCode[code] = name
+	p.codeByName[name] = code
+	p.issuedAtByCode[code] = p.now()
+	p.mut.Unlock()
+
+	p.dispatcher.Dispatch(messagebus.RejoinCodeIssued{Player: name, Code: code})
+	return code
+}
+
+func (p partyService) RejoinWithCode(code string) (name string, err error) {
+	code = strings.ToUpper(code)
+	p.mut.Lock()
+	name, exists := p.nameByCode[code]
+	expired := p.isExpired(code)
+	p.forgetCode(code)
+	p.mut.Unlock()
+	if !exists || expired {
+		return "", errInvalidCode
+	}
+
+	p.issueRejoinCode(name)
+	return name, nil
+}
+
+func (p partyService) isExpired(code string) bool {
+	return !p.now().Before(p.issuedAtByCode[code].Add(p.codeTTL))
+}
+
+func (p partyService) forgetCode(code string) {
+	name, exists := p.nameByCode[code]
+	if exists && p.codeByName[name] == code {
+		delete(p.codeByName, name)
+	}
+	delete(p.nameByCode, code)
+	delete(p.issuedAtByCode, code)
+}
+
+func (p partyService) forgetExpiredCodes() {
+	for code := range p.nameByCode {
+		if p.isExpired(code) {
+			p.forgetCode(code)
+		}
+	}
+}
+
+func (p partyService) Logout(name string) {
+	p.dispatcher.Dispatch(messagebus.PlayerLoggedOut{Player: name, At: p.now()})
+}
+
+func (p partyService) Consume(m messagebus.Message) {
+	switch m := m.(type) {
+	case messagebus.PlayerJoined:
+		p.decideJoin(m.Player, nil)
+	case messagebus.JoinRefused:
+		p.decideJoin(m.Player, errors.New(m.Reason))
+	}
+}
+
+func (p partyService) decideJoin(name string, err error) {
+	p.mut.Lock()
+	defer p.mut.Unlock()
+
+	pending := p.pendingJoins[name]
+	if len(pending) == 0 {
+		return
+	}
+	pending[0] <- err
+	p.pendingJoins[name] = pending[1:]
+	if len(p.pendingJoins[name]) == 0 {
+		delete(p.pendingJoins, name)
+	}
+}
+
+func (p partyService) forgetPendingJoin(name string, decision chan error) {
+	p.mut.Lock()
+	defer p.mut.Unlock()
+
+	pending := p.pendingJoins[name]
+	for i := range pending {
+		if pending[i] == decision {
+			p.pendingJoins[name] = append(pending[:i:i], pending[i+1:]...)
+			break
+		}
+	}
+	if len(p.pendingJoins[name]) == 0 {
+		delete(p.pendingJoins, name)
+	}
 }
@@ -1,25 +1,164 @@
 package party
 
 import (
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/damien-springuel/bomb-canary/server/messagebus"
 	. "github.com/onsi/gomega"
 )
 
 type mockDispatcher struct {
-	receivedMessage messagebus.Message
+	receivedMessage  messagebus.Message
+	receivedMessages []messagebus.Message
+	respond          func(message messagebus.Message)
 }
 
 func (m *mockDispatcher) Dispatch(message messagebus.Message) {
 	m.receivedMessage = message
+	m.receivedMessages = append(m.receivedMessages, message)
+	if m.respond != nil {
+		m.respond(message)
+	}
+}
+
+type mockCodeGenerator struct {
+	codes []string
+}
+
+func (m *mockCodeGenerator) GenerateCode() string {
+	code := m.codes[0]
+	m.codes = m.codes[1:]
+	return code
+}
+
+func newTestPartyService(dispatcher *mockDispatcher) partyService {
+	return NewPartyService(dispatcher, &mockCodeGenerator{codes: []string{"CODE01", "CODE02", "CODE03"}}, time.Hour)
+}
+
+func joinedPartyService(dispatcher *mockDispatcher, name string) partyService {
+	service := newTestPartyService(dispatcher)
+	dispatcher.respond = func(message messagebus.Message) {
+		if join, ok := message.(messagebus.JoinParty); ok {
+			service.Consume(messagebus.PlayerJoined{Player: join.Player})
+		}
+	}
+	_, _ = service.JoinParty(name)
+	return service
 }
 
 func Test_ServiceJoinParty(t *testing.T) {
 	dispatcher := &mockDispatcher{}
-	service := NewPartyService(dispatcher)
+	service := newTestPartyService(dispatcher)
+	dispatcher.respond = func(message messagebus.Message) {
+		if join, ok := message.(messagebus.JoinParty); ok {
+			service.Consume(messagebus.PlayerJoined{Player: join.Player})
+		}
+	}
+
+	rejoinCode, err := service.JoinParty("name")
+
+	g := NewWithT(t)
+	g.Expect(err).To(BeNil())
+	g.Expect(rejoinCode).To(Equal("CODE01"))
+	g.Expect(dispatcher.receivedMessages).To(Equal([]messagebus.Message{
+		messagebus.JoinParty{Player: "name"},
+		messagebus.RejoinCodeIssued{Player: "name", Code: "CODE01"},
+	}))
+	g.Expect(service.pendingJoins).To(BeEmpty())
+}
+
+func Test_ServiceJoinParty_Refused(t *testing.T) {
+	dispatcher := &mockDispatcher{}
+	service := newTestPartyService(dispatcher)
+	dispatcher.respond = func(message messagebus.Message) {
+		service.Consume(messagebus.JoinRefused{Player: "name", Reason: "player already in group"})
+	}
+
+	rejoinCode, err := service.JoinParty("name")
+
+	g := NewWithT(t)
+	g.Expect(err).To(Equal(errors.New("player already in group")))
+	g.Expect(rejoinCode).To(BeEmpty())
+	g.Expect(service.nameByCode).To(BeEmpty())
+	g.Expect(service.pendingJoins).To(BeEmpty())
+}
+
+func Test_ServiceJoinParty_IgnoresDecisionsForOtherPlayers(t *testing.T) {
+	dispatcher := &mockDispatcher{}
+	service := newTestPartyService(dispatcher)
+	service.joinTimeout = 20 * time.Millisecond
+	dispatcher.respond = func(message messagebus.Message) {
+		service.Consume(messagebus.PlayerJoined{Player: "other"})
+	}
+
+	_, err := service.JoinParty("name")
+
+	g := NewWithT(t)
+	g.Expect(err).To(Equal(errJoinTimedOut))
+	g.Expect(service.pendingJoins).To(BeEmpty())
+}
+
+func Test_ServiceRejoinWithCode(t *testing.T) {
+	dispatcher := &mockDispatcher{}
+	service := joinedPartyService(dispatcher, "name")
+
+	name, err := service.RejoinWithCode("code01")
+
+	g := NewWithT(t)
+	g.Expect(err).To(BeNil())
+	g.Expect(name).To(Equal("name"))
+	g.Expect(dispatcher.receivedMessage).To(Equal(messagebus.RejoinCodeIssued{Player: "name", Code: "CODE02"}))
+	g.Expect(service.nameByCode).To(Equal(map[string]string{"CODE02": "name"}))
+
+	_, err = service.RejoinWithCode("CODE01")
+	g.Expect(err).To(Equal(errInvalidCode))
+}
+
+func Test_ServiceRejoinWithCode_UnknownCode(t *testing.T) {
+	service := joinedPartyService(&mockDispatcher{}, "name")
+
+	_, err := service.RejoinWithCode("NOPE00")
+
+	g := NewWithT(t)
+	g.Expect(err).To(Equal(errInvalidCode))
+	g.Expect(service.nameByCode).To(Equal(map[string]string{"CODE01": "name"}))
+}
+
+func Test_ServiceRejoinWithCode_ExpiredCode(t *testing.T) {
+	service := joinedPartyService(&mockDispatcher{}, "name")
+	issuedAt := time.Now()
+	service.now = func() time.Time { return issuedAt.Add(time.Hour) }
+
+	_, err := service.RejoinWithCode("CODE01")
+
+	g := NewWithT(t)
+	g.Expect(err).To(Equal(errInvalidCode))
+	g.Expect(service.nameByCode).To(BeEmpty())
+	g.Expect(service.codeByName).To(BeEmpty())
+}
+
+func Test_ServiceForgetsExpiredCodesWhenIssuingANewOne(t *testing.T) {
+	dispatcher := &mockDispatcher{}
+	service := joinedPartyService(dispatcher, "expired")
+	issuedAt := time.Now()
+	service.now = func() time.Time { return issuedAt.Add(time.Hour) }
+
+	_, _ = service.JoinParty("name")
+
+	g := NewWithT(t)
+	g.Expect(service.nameByCode).To(Equal(map[string]string{"CODE02": "name"}))
+	g.Expect(service.issuedAtByCode).To(HaveLen(1))
+}
+
+func Test_ServiceLogout(t *testing.T) {
+	dispatcher := &mockDispatcher{}
+	service := newTestPartyService(dispatcher)
+	loggedOutAt := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return loggedOutAt }
 
 	g := NewWithT(t)
-	service.JoinParty("name")
-	g.Expect(dispatcher.receivedMessage).To(Equal(messagebus.JoinParty{Player: "name"}))
+	service.Logout("name")
+	g.Expect(dispatcher.receivedMessage).To(Equal(messagebus.PlayerLoggedOut{Player: "name", At: loggedOutAt}))
 }
@@ -0,0 +1,59 @@
+package party
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	defaultFailedRejoinsLimit  = 5
+	defaultFailedRejoinsWindow = time.Minute
+)
+
+type rejoinLimiter struct {
+	mut              *sync.Mutex
+	failuresLimit    int
+	window           time.Duration
+	now              func() time.Time
+	failedAtByClient map[string][]time.Time
+}
+
+func newRejoinLimiter() rejoinLimiter {
+	return rejoinLimiter{
+		mut:              &sync.Mutex{},
+		failuresLimit:    defaultFailedRejoinsLimit,
+		window:           defaultFailedRejoinsWindow,
+		now:              time.Now,
+		failedAtByClient: make(map[string][]time.Time),
+	}
+}
+
+func (r rejoinLimiter) allows(client string) bool {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	recent := r.recentFailures(client)
+	if len(recent) == 0 {
+		delete(r.failedAtByClient, client)
+	} else {
+		r.failedAtByClient[client] = recent
+	}
+	return len(recent) < r.failuresLimit
+}
+
+func (r rejoinLimiter) fail(client string) {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	r.failedAtByClient[client] = append(r.recentFailures(client), r.now())
+}
+
+func (r rejoinLimiter) recentFailures(client string) []time.Time {
+	recent := []time.Time{}
+	for _, failedAt := range r.failedAtByClient[client] {
+		if r.now().Sub(failedAt) < r.window {
+			recent = append(recent, failedAt)
+		}
+	}
+	return recent
+}
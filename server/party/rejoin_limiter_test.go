@@ -0,0 +1,25 @@
+package party
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func Test_RejoinLimiter_AllowsAgainOnceFailuresAreOld(t *testing.T) {
+	limiter := newRejoinLimiter()
+	now := time.Now()
+	limiter.now = func() time.Time { return now }
+	for i := 0; i < defaultFailedRejoinsLimit; i++ {
+		limiter.fail("client")
+	}
+
+	g := NewWithT(t)
+	g.Expect(limiter.allows("client")).To(BeFalse())
+	g.Expect(limiter.allows("other")).To(BeTrue())
+
+	limiter.now = func() time.Time { return now.Add(defaultFailedRejoinsWindow) }
+	g.Expect(limiter.allows("client")).To(BeTrue())
+	g.Expect(limiter.failedAtByClient).To(BeEmpty())
+}
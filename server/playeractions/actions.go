@@ -16,8 +16,37 @@ func NewActionService(messageDispatcher messageDispatcher) actionService {
 	}
 }
 
-func (a actionService) StartGame() {
-	a.messageDispatcher.Dispatch(messagebus.StartGame{})
+func (a actionService) MarkReady(player string) {
+	a.messageDispatcher.Dispatch(
+		messagebus.MarkPlayerReady{
+			Player: player,
+		},
+	)
+}
+
+func (a actionService) MarkNotReady(player string) {
+	a.messageDispatcher.Dispatch(
+		messagebus.MarkPlayerNotReady{
+			Player: player,
+		},
+	)
+}
+
+func (a actionService) StartGame(player string) {
+	a.messageDispatcher.Dispatch(
+		messagebus.StartGame{
+			Player: player,
+		},
+	)
+}
+
+func (a actionService) ForceStartGame(player string) {
+	a.messageDispatcher.Dispatch(
+		messagebus.StartGame{
+			Player: player,
+			Force:  true,
+		},
+	)
 }
 
 func (a actionService) LeaderSelectsMember(leader string, member string) {
@@ -77,3 +106,18 @@ func (a actionService) FailMission(player string) {
 		},
 	)
 }
+
+func (a actionService) SendChatMessage(player string, channel string, message string) {
+	chatChannel := messagebus.ChatChannel(channel)
+	if chatChannel == "" {
+		chatChannel = messagebus.PublicChannel
+	}
+
+	a.messageDispatcher.Dispatch(
+		messagebus.SendChatMessage{
+			Player:  player,
+			Channel: chatChannel,
+			Message: message,
+		},
+	)
+}
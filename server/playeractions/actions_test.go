@@ -19,10 +19,40 @@ func Test_ServiceStartGame(t *testing.T) {
 	dispatcher := &mockDispatcher{}
 	s := NewActionService(dispatcher)
 
-	s.StartGame()
+	s.StartGame("testName")
 
 	g := NewWithT(t)
-	g.Expect(dispatcher.receivedMessage).To(Equal(messagebus.StartGame{}))
+	g.Expect(dispatcher.receivedMessage).To(Equal(messagebus.StartGame{Player: "testName"}))
+}
+
+func Test_ServiceForceStartGame(t *testing.T) {
+	dispatcher := &mockDispatcher{}
+	s := NewActionService(dispatcher)
+
+	s.ForceStartGame("testName")
+
+	g := NewWithT(t)
+	g.Expect(dispatcher.receivedMessage).To(Equal(messagebus.StartGame{Player: "testName", Force: true}))
+}
+
+func Test_ServiceMarkReady(t *testing.T) {
+	dispatcher := &mockDispatcher{}
+	s := NewActionService(dispatcher)
+
+	s.MarkReady("testName")
+
+	g := NewWithT(t)
+	g.Expect(dispatcher.receivedMessage).To(Equal(messagebus.MarkPlayerReady{Player: "testName"}))
+}
+
+func Test_ServiceMarkNotReady(t *testing.T) {
+	dispatcher := &mockDispatcher{}
+	s := NewActionService(dispatcher)
+
+	s.MarkNotReady("testName")
+
+	g := NewWithT(t)
+	g.Expect(dispatcher.receivedMessage).To(Equal(messagebus.MarkPlayerNotReady{Player: "testName"}))
 }
 
 func Test_ServiceLeaderSelectsMember(t *testing.T) {
@@ -124,3 +154,16 @@ func Test_ServiceFailMission(t *testing.T) {
 		},
 	))
 }
+
+func Test_ServiceSendChatMessage(t *testing.T) {
+	dispatcher := &mockDispatcher{}
+	s := NewActionService(dispatcher)
+
+	s.SendChatMessage("testName", "", "it's Bob")
+
+	g := NewWithT(t)
+	g.Expect(dispatcher.receivedMessage).To(Equal(messagebus.SendChatMessage{Player: "testName", Channel: messagebus.PublicChannel, Message: "it's Bob"}))
+
+	s.SendChatMessage("testName", "spies", "fail it")
+	g.Expect(dispatcher.receivedMessage).To(Equal(messagebus.SendChatMessage{Player: "testName", Channel: messagebus.SpyChannel, Message: "fail it"}))
+}
@@ -3,6 +3,8 @@ package playeractions
 import (
 	"fmt"
 
+	"github.com/damien-springuel/bomb-canary/server/chat"
+	"github.com/damien-springuel/bomb-canary/server/sessions"
 	"github.com/gin-gonic/gin"
 )
 
@@ -14,12 +16,20 @@ type leaderSelectionRequest struct {
 	Member string `json:"member"`
 }
 
+type chatRequest struct {
+	Channel string `json:"channel"`
+	Message string `json:"message"`
+}
+
 type sessionGetter interface {
 	Get(session string) (name string, err error)
 }
 
 type actionBroker interface {
-	StartGame()
+	MarkReady(player string)
+	MarkNotReady(player string)
+	StartGame(player string)
+	ForceStartGame(player string)
 	LeaderSelectsMember(leader string, member string)
 	LeaderDeselectsMember(leader string, member string)
 	LeaderConfirmsTeam(leader string)
@@ -27,6 +37,7 @@ type actionBroker interface {
 	RejectTeam(player string)
 	SucceedMission(player string)
 	FailMission(player string)
+	SendChatMessage(player string, channel string, message string)
 }
 
 type playerActionServer struct {
@@ -43,7 +54,10 @@ func Register(engine *gin.Engine, sessionGetter sessionGetter, actionBroker acti
 
 	actions := engine.Group("/actions")
 	actions.Use(playerActionServer.checkSession)
+	actions.POST("/ready", playerActionServer.markReady)
+	actions.POST("/not-ready", playerActionServer.markNotReady)
 	actions.POST("/start-game", playerActionServer.startGame)
+	actions.POST("/force-start-game", playerActionServer.forceStartGame)
 	actions.POST("/leader-selects-member", playerActionServer.leaderSelectsMember)
 	actions.POST("/leader-deselects-member", playerActionServer.leaderDeselectsMember)
 	actions.POST("/leader-confirms-team", playerActionServer.leaderConfirmsTeam)
@@ -51,10 +65,11 @@ func Register(engine *gin.Engine, sessionGetter sessionGetter, actionBroker acti
 	actions.POST("/reject-team", playerActionServer.rejectTeam)
 	actions.POST("/succeed-mission", playerActionServer.succeedMission)
 	actions.POST("/fail-mission", playerActionServer.failMission)
+	actions.POST("/chat", playerActionServer.chat)
 }
 
 func (p playerActionServer) checkSession(c *gin.Context) {
-	session, err := c.Cookie("session")
+	session, err := sessions.FromRequest(c.Request)
 	if err != nil {
 		c.AbortWithStatus(401)
 		return
@@ -80,8 +95,31 @@ func getNameFromContext(c *gin.Context) (name string) {
 	return
 }
 
+func (p playerActionServer) markReady(c *gin.Context) {
+	name := getNameFromContext(c)
+	p.actionBroker.MarkReady(name)
+
+	c.JSON(200, gin.H{})
+}
+
+func (p playerActionServer) markNotReady(c *gin.Context) {
+	name := getNameFromContext(c)
+	p.actionBroker.MarkNotReady(name)
+
+	c.JSON(200, gin.H{})
+}
+
 func (p playerActionServer) startGame(c *gin.Context) {
-	p.actionBroker.StartGame()
+	name := getNameFromContext(c)
+	p.actionBroker.StartGame(name)
+
+	c.JSON(200, gin.H{})
+}
+
+func (p playerActionServer) forceStartGame(c *gin.Context) {
+	name := getNameFromContext(c)
+	p.actionBroker.ForceStartGame(name)
+
 	c.JSON(200, gin.H{})
 }
 
@@ -157,3 +195,23 @@ func (p playerActionServer) failMission(c *gin.Context) {
 
 	c.JSON(200, gin.H{})
 }
+
+func (p playerActionServer) chat(c *gin.Context) {
+	var req chatRequest
+	err := c.BindJSON(&req)
+	if err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": fmt.Sprintf("can't bind json: %v", err)})
+		return
+	}
+
+	err = chat.Validate(req.Message)
+	if err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": err.Error()})
+		return
+	}
+
+	name := getNameFromContext(c)
+	p.actionBroker.SendChatMessage(name, req.Channel, req.Message)
+
+	c.JSON(200, gin.H{})
+}
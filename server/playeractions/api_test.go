@@ -10,6 +10,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/damien-springuel/bomb-canary/server/chat"
 	"github.com/gin-gonic/gin"
 	. "github.com/onsi/gomega"
 )
@@ -25,7 +26,11 @@ func (m *mockSessionGetter) Get(session string) (name string, err error) {
 }
 
 type mockActionBroker struct {
+	receivedPlayerReady      string
+	receivedPlayerNotReady   string
+	receivedStarter          string
 	gameStarted              bool
+	gameForceStarted         bool
 	receivedLeader           string
 	receivedSelectedMember   string
 	receivedDeselectedMember string
@@ -34,12 +39,29 @@ type mockActionBroker struct {
 	receivedPlayerReject     string
 	receivedPlayerSucceed    string
 	receivedPlayerFail       string
+	receivedChatPlayer       string
+	receivedChatChannel      string
+	receivedChatMessage      string
 }
 
-func (m *mockActionBroker) StartGame() {
+func (m *mockActionBroker) MarkReady(player string) {
+	m.receivedPlayerReady = player
+}
+
+func (m *mockActionBroker) MarkNotReady(player string) {
+	m.receivedPlayerNotReady = player
+}
+
+func (m *mockActionBroker) StartGame(player string) {
+	m.receivedStarter = player
 	m.gameStarted = true
 }
 
+func (m *mockActionBroker) ForceStartGame(player string) {
+	m.receivedStarter = player
+	m.gameForceStarted = true
+}
+
 func (m *mockActionBroker) LeaderSelectsMember(leader string, member string) {
 	m.receivedLeader = leader
 	m.receivedSelectedMember = member
@@ -71,6 +93,12 @@ func (m *mockActionBroker) FailMission(player string) {
 	m.receivedPlayerFail = player
 }
 
+func (m *mockActionBroker) SendChatMessage(player string, channel string, message string) {
+	m.receivedChatPlayer = player
+	m.receivedChatChannel = channel
+	m.receivedChatMessage = message
+}
+
 func jsonReader(obj interface{}) io.Reader {
 	jsonBytes, _ := json.Marshal(obj)
 	return bytes.NewReader(jsonBytes)
@@ -114,6 +142,53 @@ func Test_CheckSessionMiddleware_Return403IfGettingSessionReturnsError(t *testin
 	g.Expect(sessionGetter.receivedSession).To(Equal("testSession"))
 }
 
+func Test_CheckSessionMiddleware_AcceptsBearerToken(t *testing.T) {
+	req, _ := http.NewRequest("POST", "/actions/start-game", nil)
+	req.Header.Set("Authorization", "Bearer testSession")
+	sessionGetter, actionBroker, w := makeCall(req, nil)
+
+	g := NewWithT(t)
+	g.Expect(w.Code).To(Equal(200))
+	g.Expect(sessionGetter.receivedSession).To(Equal("testSession"))
+	g.Expect(actionBroker.receivedStarter).To(Equal("testName"))
+	g.Expect(actionBroker.gameStarted).To(BeTrue())
+}
+
+func Test_ForceStartGame(t *testing.T) {
+	req, _ := http.NewRequest("POST", "/actions/force-start-game", nil)
+	req.AddCookie(&http.Cookie{Name: "session", Value: "testSession"})
+	_, actionBroker, w := makeCall(req, nil)
+
+	g := NewWithT(t)
+	g.Expect(w.Code).To(Equal(200))
+	g.Expect(w.Body.String()).To(Equal("{}"))
+	g.Expect(actionBroker.receivedStarter).To(Equal("testName"))
+	g.Expect(actionBroker.gameForceStarted).To(BeTrue())
+	g.Expect(actionBroker.gameStarted).To(BeFalse())
+}
+
+func Test_MarkReady(t *testing.T) {
+	req, _ := http.NewRequest("POST", "/actions/ready", nil)
+	req.AddCookie(&http.Cookie{Name: "session", Value: "testSession"})
+	_, actionBroker, w := makeCall(req, nil)
+
+	g := NewWithT(t)
+	g.Expect(w.Code).To(Equal(200))
+	g.Expect(w.Body.String()).To(Equal("{}"))
+	g.Expect(actionBroker.receivedPlayerReady).To(Equal("testName"))
+}
+
+func Test_MarkNotReady(t *testing.T) {
+	req, _ := http.NewRequest("POST", "/actions/not-ready", nil)
+	req.AddCookie(&http.Cookie{Name: "session", Value: "testSession"})
+	_, actionBroker, w := makeCall(req, nil)
+
+	g := NewWithT(t)
+	g.Expect(w.Code).To(Equal(200))
+	g.Expect(w.Body.String()).To(Equal("{}"))
+	g.Expect(actionBroker.receivedPlayerNotReady).To(Equal("testName"))
+}
+
 func Test_StartGame(t *testing.T) {
 	req, _ := http.NewRequest("POST", "/actions/start-game", nil)
 	req.AddCookie(&http.Cookie{Name: "session", Value: "testSession"})
@@ -271,3 +346,47 @@ func Test_FailMission(t *testing.T) {
 	g.Expect(sessionGetter.receivedSession).To(Equal("testSession"))
 	g.Expect(actionBroker.receivedPlayerFail).To(Equal("testName"))
 }
+
+func Test_Chat(t *testing.T) {
+	req, _ := http.NewRequest("POST", "/actions/chat", jsonReader(chatRequest{Channel: "spies", Message: "it's Bob"}))
+	req.AddCookie(&http.Cookie{Name: "session", Value: "testSession"})
+	sessionGetter, actionBroker, w := makeCall(req, nil)
+
+	g := NewWithT(t)
+	g.Expect(w.Code).To(Equal(200))
+	g.Expect(w.Body.String()).To(Equal("{}"))
+
+	g.Expect(sessionGetter.receivedSession).To(Equal("testSession"))
+	g.Expect(actionBroker.receivedChatPlayer).To(Equal("testName"))
+	g.Expect(actionBroker.receivedChatChannel).To(Equal("spies"))
+	g.Expect(actionBroker.receivedChatMessage).To(Equal("it's Bob"))
+}
+
+func Test_Chat_Returns400IfMessageIsInvalid(t *testing.T) {
+	req, _ := http.NewRequest("POST", "/actions/chat", jsonReader(chatRequest{Message: "  "}))
+	req.AddCookie(&http.Cookie{Name: "session", Value: "testSession"})
+	_, actionBroker, w := makeCall(req, nil)
+
+	g := NewWithT(t)
+	g.Expect(w.Code).To(Equal(400))
+	g.Expect(w.Body.String()).To(Equal(`{"error":"message is required"}`))
+	g.Expect(actionBroker.receivedChatPlayer).To(Equal(""))
+
+	req, _ = http.NewRequest("POST", "/actions/chat", jsonReader(chatRequest{Message: strings.Repeat("a", chat.MaxMessageLength+1)}))
+	req.AddCookie(&http.Cookie{Name: "session", Value: "testSession"})
+	_, actionBroker, w = makeCall(req, nil)
+
+	g.Expect(w.Code).To(Equal(400))
+	g.Expect(w.Body.String()).To(Equal(`{"error":"message can't be longer than 500 characters"}`))
+	g.Expect(actionBroker.receivedChatPlayer).To(Equal(""))
+}
+
+func Test_Chat_Returns400IfBodyMalformed(t *testing.T) {
+	req, _ := http.NewRequest("POST", "/actions/chat", strings.NewReader("garbage"))
+	req.AddCookie(&http.Cookie{Name: "session", Value: "testSession"})
+	_, actionBroker, w := makeCall(req, nil)
+
+	g := NewWithT(t)
+	g.Expect(w.Code).To(Equal(400))
+	g.Expect(actionBroker.receivedChatPlayer).To(Equal(""))
+}
@@ -0,0 +1,106 @@
+package playeractions
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/damien-springuel/bomb-canary/server/chat"
+)
+
+type websocketAction struct {
+	RequestId string `json:"requestId"`
+	Action    string `json:"action"`
+	Member    string `json:"member"`
+	Channel   string `json:"channel"`
+	Message   string `json:"message"`
+}
+
+type actionAcknowledged struct {
+	RequestId string
+}
+
+type actionRejected struct {
+	RequestId string
+	Error     string
+}
+
+type actionReply struct {
+	ActionAcknowledged *actionAcknowledged `json:",omitempty"`
+	ActionRejected     *actionRejected     `json:",omitempty"`
+}
+
+type websocketActionHandler struct {
+	actionBroker actionBroker
+}
+
+func NewWebsocketActionHandler(actionBroker actionBroker) websocketActionHandler {
+	return websocketActionHandler{
+		actionBroker: actionBroker,
+	}
+}
+
+func (w websocketActionHandler) Handle(player string, message []byte) []byte {
+	var action websocketAction
+	err := json.Unmarshal(message, &action)
+	if err != nil {
+		return toReplyBytes(actionReply{ActionRejected: &actionRejected{Error: fmt.Sprintf("can't unmarshal json: %v", err)}})
+	}
+
+	if action.RequestId == "" {
+		return toReplyBytes(actionReply{ActionRejected: &actionRejected{Error: "requestId is required"}})
+	}
+
+	err = w.route(player, action)
+	if err != nil {
+		return toReplyBytes(actionReply{ActionRejected: &actionRejected{RequestId: action.RequestId, Error: err.Error()}})
+	}
+
+	return toReplyBytes(actionReply{ActionAcknowledged: &actionAcknowledged{RequestId: action.RequestId}})
+}
+
+func (w websocketActionHandler) route(player string, action websocketAction) error {
+	switch action.Action {
+	case "ready":
+		w.actionBroker.MarkReady(player)
+	case "not-ready":
+		w.actionBroker.MarkNotReady(player)
+	case "start-game":
+		w.actionBroker.StartGame(player)
+	case "force-start-game":
+		w.actionBroker.ForceStartGame(player)
+	case "leader-selects-member":
+		if action.Member == "" {
+			return fmt.Errorf("member is required")
+		}
+		w.actionBroker.LeaderSelectsMember(player, action.Member)
+	case "leader-deselects-member":
+		if action.Member == "" {
+			return fmt.Errorf("member is required")
+		}
+		w.actionBroker.LeaderDeselectsMember(player, action.Member)
+	case "leader-confirms-team":
+		w.actionBroker.LeaderConfirmsTeam(player)
+	case "approve-team":
+		w.actionBroker.ApproveTeam(player)
+	case "reject-team":
+		w.actionBroker.RejectTeam(player)
+	case "succeed-mission":
+		w.actionBroker.SucceedMission(player)
+	case "fail-mission":
+		w.actionBroker.FailMission(player)
+	case "chat":
+		err := chat.Validate(action.Message)
+		if err != nil {
+			return err
+		}
+		w.actionBroker.SendChatMessage(player, action.Channel, action.Message)
+	default:
+		return fmt.Errorf("unknown action %q", action.Action)
+	}
+	return nil
+}
+
+func toReplyBytes(reply actionReply) []byte {
+	replyBytes, _ := json.Marshal(reply)
+	return replyBytes
+}
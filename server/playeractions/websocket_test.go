@@ -0,0 +1,100 @@
+package playeractions
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func Test_WebsocketActionHandler_StartGame(t *testing.T) {
+	actionBroker := &mockActionBroker{}
+	reply := NewWebsocketActionHandler(actionBroker).Handle("testName", []byte(`{"requestId":"r1","action":"start-game"}`))
+
+	g := NewWithT(t)
+	g.Expect(string(reply)).To(Equal(`{"ActionAcknowledged":{"RequestId":"r1"}}`))
+	g.Expect(actionBroker.receivedStarter).To(Equal("testName"))
+	g.Expect(actionBroker.gameStarted).To(BeTrue())
+}
+
+func Test_WebsocketActionHandler_Readiness(t *testing.T) {
+	actionBroker := &mockActionBroker{}
+	handler := NewWebsocketActionHandler(actionBroker)
+
+	handler.Handle("ready", []byte(`{"requestId":"r1","action":"ready"}`))
+	handler.Handle("notReady", []byte(`{"requestId":"r2","action":"not-ready"}`))
+	reply := handler.Handle("host", []byte(`{"requestId":"r3","action":"force-start-game"}`))
+
+	g := NewWithT(t)
+	g.Expect(string(reply)).To(Equal(`{"ActionAcknowledged":{"RequestId":"r3"}}`))
+	g.Expect(actionBroker.receivedPlayerReady).To(Equal("ready"))
+	g.Expect(actionBroker.receivedPlayerNotReady).To(Equal("notReady"))
+	g.Expect(actionBroker.receivedStarter).To(Equal("host"))
+	g.Expect(actionBroker.gameForceStarted).To(BeTrue())
+}
+
+func Test_WebsocketActionHandler_LeaderSelectsMember(t *testing.T) {
+	actionBroker := &mockActionBroker{}
+	reply := NewWebsocketActionHandler(actionBroker).Handle("testName", []byte(`{"requestId":"r1","action":"leader-selects-member","member":"aMember"}`))
+
+	g := NewWithT(t)
+	g.Expect(string(reply)).To(Equal(`{"ActionAcknowledged":{"RequestId":"r1"}}`))
+	g.Expect(actionBroker.receivedLeader).To(Equal("testName"))
+	g.Expect(actionBroker.receivedSelectedMember).To(Equal("aMember"))
+}
+
+func Test_WebsocketActionHandler_LeaderDeselectsMember(t *testing.T) {
+	actionBroker := &mockActionBroker{}
+	reply := NewWebsocketActionHandler(actionBroker).Handle("testName", []byte(`{"requestId":"r1","action":"leader-deselects-member","member":"aMember"}`))
+
+	g := NewWithT(t)
+	g.Expect(string(reply)).To(Equal(`{"ActionAcknowledged":{"RequestId":"r1"}}`))
+	g.Expect(actionBroker.receivedLeader).To(Equal("testName"))
+	g.Expect(actionBroker.receivedDeselectedMember).To(Equal("aMember"))
+}
+
+func Test_WebsocketActionHandler_PlayerActions(t *testing.T) {
+	actionBroker := &mockActionBroker{}
+	handler := NewWebsocketActionHandler(actionBroker)
+
+	handler.Handle("leader", []byte(`{"requestId":"r1","action":"leader-confirms-team"}`))
+	handler.Handle("approver", []byte(`{"requestId":"r2","action":"approve-team"}`))
+	handler.Handle("rejecter", []byte(`{"requestId":"r3","action":"reject-team"}`))
+	handler.Handle("succeeder", []byte(`{"requestId":"r4","action":"succeed-mission"}`))
+	handler.Handle("failer", []byte(`{"requestId":"r5","action":"fail-mission"}`))
+
+	g := NewWithT(t)
+	g.Expect(actionBroker.receivedLeader).To(Equal("leader"))
+	g.Expect(actionBroker.teamConfirmed).To(BeTrue())
+	g.Expect(actionBroker.receivedPlayerApprove).To(Equal("approver"))
+	g.Expect(actionBroker.receivedPlayerReject).To(Equal("rejecter"))
+	g.Expect(actionBroker.receivedPlayerSucceed).To(Equal("succeeder"))
+	g.Expect(actionBroker.receivedPlayerFail).To(Equal("failer"))
+}
+
+func Test_WebsocketActionHandler_Chat(t *testing.T) {
+	actionBroker := &mockActionBroker{}
+	handler := NewWebsocketActionHandler(actionBroker)
+
+	reply := handler.Handle("testName", []byte(`{"requestId":"r1","action":"chat","channel":"spies","message":"it's Bob"}`))
+	rejected := handler.Handle("testName", []byte(`{"requestId":"r2","action":"chat","message":""}`))
+
+	g := NewWithT(t)
+	g.Expect(string(reply)).To(Equal(`{"ActionAcknowledged":{"RequestId":"r1"}}`))
+	g.Expect(string(rejected)).To(Equal(`{"ActionRejected":{"RequestId":"r2","Error":"message is required"}}`))
+	g.Expect(actionBroker.receivedChatPlayer).To(Equal("testName"))
+	g.Expect(actionBroker.receivedChatChannel).To(Equal("spies"))
+	g.Expect(actionBroker.receivedChatMessage).To(Equal("it's Bob"))
+}
+
+func Test_WebsocketActionHandler_RejectsInvalidMessages(t *testing.T) {
+	actionBroker := &mockActionBroker{}
+	handler := NewWebsocketActionHandler(actionBroker)
+
+	g := NewWithT(t)
+	g.Expect(string(handler.Handle("testName", []byte(`not json`)))).To(HavePrefix(`{"ActionRejected":{"RequestId":"","Error":"can't unmarshal json`))
+	g.Expect(string(handler.Handle("testName", []byte(`{"action":"start-game"}`)))).To(Equal(`{"ActionRejected":{"RequestId":"","Error":"requestId is required"}}`))
+	g.Expect(string(handler.Handle("testName", []byte(`{"requestId":"r1","action":"leader-selects-member"}`)))).To(Equal(`{"ActionRejected":{"RequestId":"r1","Error":"member is required"}}`))
+	g.Expect(string(handler.Handle("testName", []byte(`{"requestId":"r2","action":"dance"}`)))).To(Equal(`{"ActionRejected":{"RequestId":"r2","Error":"unknown action \"dance\""}}`))
+
+	g.Expect(*actionBroker).To(Equal(mockActionBroker{}))
+}
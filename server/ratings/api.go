@@ -0,0 +1,37 @@
+package ratings
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+type ratingsGetter interface {
+	Player(name string) (PlayerRatings, error)
+	All() []PlayerRatings
+}
+
+type ratingsServer struct {
+	ratingsGetter ratingsGetter
+}
+
+func Register(engine *gin.Engine, ratingsGetter ratingsGetter) {
+	ratingsServer := ratingsServer{
+		ratingsGetter: ratingsGetter,
+	}
+
+	engine.GET("/ratings", ratingsServer.all)
+	engine.GET("/ratings/:name", ratingsServer.player)
+}
+
+func (r ratingsServer) all(c *gin.Context) {
+	c.JSON(200, gin.H{"players": r.ratingsGetter.All()})
+}
+
+func (r ratingsServer) player(c *gin.Context) {
+	ratings, err := r.ratingsGetter.Player(c.Param("name"))
+	if err != nil {
+		c.AbortWithStatusJSON(404, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, ratings)
+}
@@ -0,0 +1,68 @@
+package ratings
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/damien-springuel/bomb-canary/server/messagebus"
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/gomega"
+)
+
+type mockRatingsGetter struct {
+	receivedName string
+	playerError  error
+}
+
+func (m *mockRatingsGetter) Player(name string) (PlayerRatings, error) {
+	m.receivedName = name
+	return PlayerRatings{Name: name, Resistance: 1516, Spy: 1500, History: []RatingChange{
+		{At: time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC), Allegiance: messagebus.Resistance, Won: true, Before: 1500, After: 1516},
+	}}, m.playerError
+}
+
+func (m *mockRatingsGetter) All() []PlayerRatings {
+	return []PlayerRatings{{Name: "Alice", Resistance: 1516, Spy: 1500}}
+}
+
+func makeCall(req *http.Request, ratingsGetter *mockRatingsGetter) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	ginEngine := gin.New()
+	Register(ginEngine, ratingsGetter)
+
+	w := httptest.NewRecorder()
+	ginEngine.ServeHTTP(w, req)
+	return w
+}
+
+func Test_All(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/ratings", nil)
+	w := makeCall(req, &mockRatingsGetter{})
+
+	g := NewWithT(t)
+	g.Expect(w.Code).To(Equal(200))
+	g.Expect(w.Body.String()).To(Equal(`{"players":[{"name":"Alice","resistance":1516,"spy":1500}]}`))
+}
+
+func Test_Player(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/ratings/Alice", nil)
+	ratingsGetter := &mockRatingsGetter{}
+	w := makeCall(req, ratingsGetter)
+
+	g := NewWithT(t)
+	g.Expect(w.Code).To(Equal(200))
+	g.Expect(w.Body.String()).To(Equal(`{"name":"Alice","resistance":1516,"spy":1500,` +
+		`"history":[{"at":"2021-03-04T05:06:07Z","allegiance":"resistance","won":true,"before":1500,"after":1516}]}`))
+	g.Expect(ratingsGetter.receivedName).To(Equal("Alice"))
+}
+
+func Test_Player_Returns404IfUnknown(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/ratings/Alice", nil)
+	w := makeCall(req, &mockRatingsGetter{playerError: errUnknownPlayer})
+
+	g := NewWithT(t)
+	g.Expect(w.Code).To(Equal(404))
+	g.Expect(w.Body.String()).To(Equal(`{"error":"player has no rating"}`))
+}
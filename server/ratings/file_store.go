@@ -0,0 +1,50 @@
+package ratings
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+)
+
+type fileStore struct {
+	path string
+}
+
+func NewFileStore(path string) fileStore {
+	return fileStore{path: path}
+}
+
+func (f fileStore) Load() (map[string]PlayerRatings, error) {
+	ratings := make(map[string]PlayerRatings)
+
+	content, err := os.ReadFile(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return ratings, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(content, &ratings)
+	return ratings, err
+}
+
+func (f fileStore) Save(ratings map[string]PlayerRatings) error {
+	content, err := json.Marshal(ratings)
+	if err != nil {
+		return err
+	}
+
+	err = os.MkdirAll(filepath.Dir(f.path), 0o755)
+	if err != nil {
+		return err
+	}
+
+	tmp := f.path + ".tmp"
+	err = os.WriteFile(tmp, content, 0o644)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, f.path)
+}
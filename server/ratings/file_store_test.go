@@ -0,0 +1,34 @@
+package ratings
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/damien-springuel/bomb-canary/server/messagebus"
+	. "github.com/onsi/gomega"
+)
+
+func Test_FileStore_LoadMissingFile(t *testing.T) {
+	store := NewFileStore(filepath.Join(t.TempDir(), "ratings.json"))
+	ratings, err := store.Load()
+
+	g := NewWithT(t)
+	g.Expect(err).To(BeNil())
+	g.Expect(ratings).To(Equal(map[string]PlayerRatings{}))
+}
+
+func Test_FileStore_SaveAndLoad(t *testing.T) {
+	store := NewFileStore(filepath.Join(t.TempDir(), "league", "ratings.json"))
+	saved := map[string]PlayerRatings{
+		"Alice": {Name: "Alice", Resistance: 1516, Spy: 1500, History: []RatingChange{
+			{At: time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC), Allegiance: messagebus.Resistance, Won: true, Before: 1500, After: 1516},
+		}},
+	}
+
+	g := NewWithT(t)
+	g.Expect(store.Save(saved)).To(Succeed())
+	loaded, err := store.Load()
+	g.Expect(err).To(BeNil())
+	g.Expect(loaded).To(Equal(saved))
+}
@@ -0,0 +1,172 @@
+package ratings
+
+import (
+	"errors"
+	"log"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/damien-springuel/bomb-canary/server/messagebus"
+)
+
+const (
+	initialRating = 1500.0
+	kFactor       = 32.0
+)
+
+var errUnknownPlayer = errors.New("player has no rating")
+
+type RatingChange struct {
+	At         time.Time             `json:"at"`
+	Allegiance messagebus.Allegiance `json:"allegiance"`
+	Won        bool                  `json:"won"`
+	Before     float64               `json:"before"`
+	After      float64               `json:"after"`
+}
+
+type PlayerRatings struct {
+	Name       string         `json:"name"`
+	Resistance float64        `json:"resistance"`
+	Spy        float64        `json:"spy"`
+	History    []RatingChange `json:"history,omitempty"`
+}
+
+type store interface {
+	Load() (map[string]PlayerRatings, error)
+	Save(ratings map[string]PlayerRatings) error
+}
+
+type ledger struct {
+	mut         *sync.RWMutex
+	store       store
+	now         func() time.Time
+	ratings     map[string]PlayerRatings
+	allegiances map[string]messagebus.Allegiance
+}
+
+func NewLedger(store store) (*ledger, error) {
+	ratings, err := store.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ledger{
+		mut:     &sync.RWMutex{},
+		store:   store,
+		now:     time.Now,
+		ratings: ratings,
+	}, nil
+}
+
+func (l *ledger) Consume(m messagebus.Message) {
+	switch m := m.(type) {
+	case messagebus.AllegianceRevealed:
+		l.allegiances = m.AllegianceByPlayer
+	case messagebus.GameEnded:
+		l.rateGame(m.Winner)
+	}
+}
+
+func (l *ledger) rateGame(winner messagebus.Allegiance) {
+	l.mut.Lock()
+	defer l.mut.Unlock()
+
+	if len(l.allegiances) == 0 {
+		return
+	}
+
+	resistanceRating := l.teamRating(messagebus.Resistance)
+	spyRating := l.teamRating(messagebus.Spy)
+	expectedResistanceScore := 1 / (1 + math.Pow(10, (spyRating-resistanceRating)/400))
+
+	resistanceScore := 0.0
+	if winner == messagebus.Resistance {
+		resistanceScore = 1
+	}
+	resistanceDelta := kFactor * (resistanceScore - expectedResistanceScore)
+
+	at := l.now()
+	for name, allegiance := range l.allegiances {
+		ratings := l.ratingsOf(name)
+		change := RatingChange{At: at, Allegiance: allegiance, Won: allegiance == winner}
+		if allegiance == messagebus.Spy {
+			change.Before = ratings.Spy
+			ratings.Spy = round(ratings.Spy - resistanceDelta)
+			change.After = ratings.Spy
+		} else {
+			change.Before = ratings.Resistance
+			ratings.Resistance = round(ratings.Resistance + resistanceDelta)
+			change.After = ratings.Resistance
+		}
+		ratings.History = append(ratings.History, change)
+		l.ratings[name] = ratings
+	}
+	l.allegiances = nil
+
+	err := l.store.Save(l.ratings)
+	if err != nil {
+		log.Printf("can't save ratings: %v", err)
+	}
+}
+
+func (l *ledger) teamRating(allegiance messagebus.Allegiance) float64 {
+	total, nbPlayers := 0.0, 0
+	for name, playerAllegiance := range l.allegiances {
+		if playerAllegiance != allegiance {
+			continue
+		}
+		ratings := l.ratingsOf(name)
+		if allegiance == messagebus.Spy {
+			total += ratings.Spy
+		} else {
+			total += ratings.Resistance
+		}
+		nbPlayers += 1
+	}
+
+	if nbPlayers == 0 {
+		return initialRating
+	}
+	return total / float64(nbPlayers)
+}
+
+func (l *ledger) ratingsOf(name string) PlayerRatings {
+	ratings, exists := l.ratings[name]
+	if !exists {
+		ratings = PlayerRatings{Name: name, Resistance: initialRating, Spy: initialRating}
+	}
+	return ratings
+}
+
+func (l *ledger) Player(name string) (PlayerRatings, error) {
+	l.mut.RLock()
+	defer l.mut.RUnlock()
+
+	ratings, exists := l.ratings[name]
+	if !exists {
+		return PlayerRatings{}, errUnknownPlayer
+	}
+	ratings.History = append([]RatingChange{}, ratings.History...)
+	return ratings, nil
+}
+
+func (l *ledger) All() []PlayerRatings {
+	l.mut.RLock()
+	defer l.mut.RUnlock()
+
+	all := make([]PlayerRatings, 0, len(l.ratings))
+	for _, ratings := range l.ratings {
+		ratings.History = nil
+		all = append(all, ratings)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		return all[i].Name < all[j].Name
+	})
+	return all
+}
+
+func round(rating float64) float64 {
+	return math.Round(rating*100) / 100
+}
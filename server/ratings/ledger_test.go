@@ -0,0 +1,136 @@
+package ratings
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/damien-springuel/bomb-canary/server/messagebus"
+	. "github.com/onsi/gomega"
+)
+
+type mockStore struct {
+	loaded    map[string]PlayerRatings
+	loadError error
+	saved     map[string]PlayerRatings
+	nbSaves   int
+}
+
+func (m *mockStore) Load() (map[string]PlayerRatings, error) {
+	if m.loaded == nil {
+		m.loaded = make(map[string]PlayerRatings)
+	}
+	return m.loaded, m.loadError
+}
+
+func (m *mockStore) Save(ratings map[string]PlayerRatings) error {
+	m.saved = ratings
+	m.nbSaves += 1
+	return nil
+}
+
+var gameTime = time.Date(2021, 3, 4, 5, 6, 7, 0, time.UTC)
+
+func setupLedger(store *mockStore) *ledger {
+	l, _ := NewLedger(store)
+	l.now = func() time.Time { return gameTime }
+	return l
+}
+
+func playGame(l *ledger, allegiances map[string]messagebus.Allegiance, winner messagebus.Allegiance) {
+	l.Consume(messagebus.AllegianceRevealed{AllegianceByPlayer: allegiances})
+	l.Consume(messagebus.GameEnded{Winner: winner})
+}
+
+func Test_RatingsUpdatedAfterGameEnded(t *testing.T) {
+	store := &mockStore{}
+	l := setupLedger(store)
+	playGame(l, map[string]messagebus.Allegiance{
+		"Alice":   messagebus.Spy,
+		"Bob":     messagebus.Resistance,
+		"Charlie": messagebus.Resistance,
+	}, messagebus.Resistance)
+
+	g := NewWithT(t)
+	alice, err := l.Player("Alice")
+	g.Expect(err).To(BeNil())
+	g.Expect(alice).To(Equal(PlayerRatings{
+		Name:       "Alice",
+		Resistance: 1500,
+		Spy:        1484,
+		History:    []RatingChange{{At: gameTime, Allegiance: messagebus.Spy, Won: false, Before: 1500, After: 1484}},
+	}))
+
+	bob, _ := l.Player("Bob")
+	g.Expect(bob).To(Equal(PlayerRatings{
+		Name:       "Bob",
+		Resistance: 1516,
+		Spy:        1500,
+		History:    []RatingChange{{At: gameTime, Allegiance: messagebus.Resistance, Won: true, Before: 1500, After: 1516}},
+	}))
+
+	g.Expect(store.nbSaves).To(Equal(1))
+	g.Expect(store.saved["Charlie"].Resistance).To(Equal(1516.0))
+}
+
+func Test_RatingsUseTeamAverageOfAllegianceRating(t *testing.T) {
+	store := &mockStore{loaded: map[string]PlayerRatings{
+		"Alice": {Name: "Alice", Resistance: 1500, Spy: 1700},
+		"Bob":   {Name: "Bob", Resistance: 1300, Spy: 1500},
+		"Dan":   {Name: "Dan", Resistance: 1500, Spy: 1500},
+	}}
+	l := setupLedger(store)
+	playGame(l, map[string]messagebus.Allegiance{
+		"Alice": messagebus.Spy,
+		"Bob":   messagebus.Resistance,
+		"Dan":   messagebus.Resistance,
+	}, messagebus.Resistance)
+
+	g := NewWithT(t)
+	alice, _ := l.Player("Alice")
+	bob, _ := l.Player("Bob")
+	dan, _ := l.Player("Dan")
+	g.Expect(alice.Spy).To(Equal(1672.83))
+	g.Expect(bob.Resistance).To(Equal(1327.17))
+	g.Expect(dan.Resistance).To(Equal(1527.17))
+	g.Expect(bob.Spy).To(Equal(1500.0))
+}
+
+func Test_NoRatingChangeWithoutAllegiances(t *testing.T) {
+	store := &mockStore{}
+	l := setupLedger(store)
+	l.Consume(messagebus.GameEnded{Winner: messagebus.Spy})
+	playGame(l, map[string]messagebus.Allegiance{"Alice": messagebus.Spy, "Bob": messagebus.Resistance}, messagebus.Spy)
+	l.Consume(messagebus.GameEnded{Winner: messagebus.Spy})
+
+	g := NewWithT(t)
+	g.Expect(store.nbSaves).To(Equal(1))
+	alice, _ := l.Player("Alice")
+	g.Expect(alice.History).To(HaveLen(1))
+}
+
+func Test_UnknownPlayer(t *testing.T) {
+	l := setupLedger(&mockStore{})
+
+	g := NewWithT(t)
+	_, err := l.Player("Alice")
+	g.Expect(err).To(Equal(errUnknownPlayer))
+}
+
+func Test_AllSortedByNameWithoutHistory(t *testing.T) {
+	l := setupLedger(&mockStore{})
+	playGame(l, map[string]messagebus.Allegiance{"Bob": messagebus.Spy, "Alice": messagebus.Resistance}, messagebus.Spy)
+
+	g := NewWithT(t)
+	g.Expect(l.All()).To(Equal([]PlayerRatings{
+		{Name: "Alice", Resistance: 1484, Spy: 1500},
+		{Name: "Bob", Resistance: 1500, Spy: 1516},
+	}))
+}
+
+func Test_NewLedgerFailsIfStoreCantLoad(t *testing.T) {
+	_, err := NewLedger(&mockStore{loadError: errors.New("corrupted")})
+
+	g := NewWithT(t)
+	g.Expect(err).To(Equal(errors.New("corrupted")))
+}
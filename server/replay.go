@@ -0,0 +1,77 @@
+package main
+
+import (
+	"os"
+	"time"
+
+	"github.com/damien-springuel/bomb-canary/server/clientstream"
+	"github.com/damien-springuel/bomb-canary/server/gamestate"
+	"github.com/damien-springuel/bomb-canary/server/history"
+	"github.com/damien-springuel/bomb-canary/server/messagebus"
+	"github.com/damien-springuel/bomb-canary/server/messagelogger"
+	"github.com/damien-springuel/bomb-canary/server/party"
+	"github.com/damien-springuel/bomb-canary/server/replay"
+	"github.com/damien-springuel/bomb-canary/server/sessions"
+	"github.com/damien-springuel/bomb-canary/server/transcript"
+	"github.com/gin-gonic/gin"
+)
+
+func serveReplay(config config) {
+	game, err := history.ReadGame(config.replayFile)
+	if err != nil {
+		blackOnYellow.Printf("can't read the game to replay %v\n", err)
+		os.Exit(1)
+	}
+	events, err := game.Messages()
+	if err != nil {
+		blackOnYellow.Printf("can't decode the game to replay %v\n", err)
+		os.Exit(1)
+	}
+
+	bus := messagebus.NewMessageBus()
+	defer bus.Close()
+
+	if config.isProd {
+		gin.SetMode(gin.ReleaseMode)
+	} else {
+		bus.SubscribeConsumer(messagelogger.New(colorPrinter{}))
+	}
+
+	clientStreamer := clientstream.NewClientsStreamer(bus)
+	bus.SubscribeConsumer(clientStreamer)
+	eventReplayer := clientstream.NewEventReplayer(clientStreamer)
+	clientEventBroker := clientstream.NewClientEventBroker(eventReplayer)
+	bus.SubscribeConsumer(clientEventBroker)
+	bus.SubscribeConsumer(eventReplayer)
+
+	gameStateProjection := gamestate.NewProjection()
+	bus.SubscribeConsumer(gameStateProjection)
+
+	transcriptRecorder := transcript.NewRecorder()
+	bus.SubscribeConsumer(transcriptRecorder)
+
+	replayController := replay.NewController(bus, events, config.replayInterval)
+	replayController.Start()
+
+	replayWatchers := replay.NewWatchers()
+	bus.SubscribeConsumer(replayWatchers)
+
+	router := newRouter(config)
+
+	sessions, err := sessions.NewSigned(config.partyName, config.sessionTTL, sessionSecrets(config.sessionKeys)...)
+	if err != nil {
+		blackOnYellow.Printf("can't create sessions %v\n", err)
+		os.Exit(1)
+	}
+	stopSessionCleanUp := sessions.StartCleanUp(time.Minute)
+	defer stopSessionCleanUp()
+	router.Use(party.RenewSession(sessions))
+	replay.Register(router, sessions, replayController, replayWatchers)
+	clientstream.Register(router, sessions, clientStreamer, replay.NewReadOnlyActionHandler(), config.heartbeat(), config.allowedOrigins)
+	clientstream.RegisterPoll(router, sessions, eventReplayer, config.pollTimeout)
+	gamestate.Register(router, sessions, gameStateProjection)
+	transcript.Register(router, sessions, transcriptRecorder)
+
+	blackOnYellow.Printf("replaying game %s, %d events\n", game.ID, len(events))
+	serve(router, config)
+}
@@ -0,0 +1,32 @@
+package replay
+
+import (
+	"encoding/json"
+)
+
+type websocketAction struct {
+	RequestId string `json:"requestId"`
+}
+
+type actionRejected struct {
+	RequestId string
+	Error     string
+}
+
+type actionReply struct {
+	ActionRejected *actionRejected
+}
+
+type readOnlyActions struct{}
+
+func NewReadOnlyActionHandler() readOnlyActions {
+	return readOnlyActions{}
+}
+
+func (r readOnlyActions) Handle(player string, message []byte) []byte {
+	var action websocketAction
+	_ = json.Unmarshal(message, &action)
+
+	reply, _ := json.Marshal(actionReply{ActionRejected: &actionRejected{RequestId: action.RequestId, Error: "a replay is read-only"}})
+	return reply
+}
@@ -0,0 +1,14 @@
+package replay
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func Test_ReadOnlyActionHandler_RejectsEveryAction(t *testing.T) {
+	reply := NewReadOnlyActionHandler().Handle("Alice", []byte(`{"requestId":"r1","action":"approve-team"}`))
+
+	g := NewWithT(t)
+	g.Expect(string(reply)).To(Equal(`{"ActionRejected":{"RequestId":"r1","Error":"a replay is read-only"}}`))
+}
@@ -0,0 +1,114 @@
+package replay
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/damien-springuel/bomb-canary/server/sessions"
+	"github.com/gin-gonic/gin"
+)
+
+type watchRequest struct {
+	Name string `json:"name"`
+}
+
+type sessionStore interface {
+	Create(name string) string
+	Get(session string) (name string, err error)
+	TTL() time.Duration
+}
+
+type replayController interface {
+	IsPlayer(name string) bool
+	Status() Status
+	Forward() (Status, error)
+	Back() (Status, error)
+	Play() (Status, error)
+	Pause() Status
+}
+
+type seatWatchers interface {
+	IsWatched(name string) bool
+}
+
+type replayServer struct {
+	session    sessionStore
+	controller replayController
+	watchers   seatWatchers
+}
+
+func Register(engine *gin.Engine, session sessionStore, controller replayController, watchers seatWatchers) {
+	replayServer := replayServer{
+		session:    session,
+		controller: controller,
+		watchers:   watchers,
+	}
+
+	engine.POST("/party/join", replayServer.watch)
+
+	replayGroup := engine.Group("/replay", replayServer.checkSession)
+	replayGroup.GET("", replayServer.status)
+	replayGroup.POST("/forward", replayServer.step(controller.Forward))
+	replayGroup.POST("/back", replayServer.step(controller.Back))
+	replayGroup.POST("/play", replayServer.step(controller.Play))
+	replayGroup.POST("/pause", replayServer.pause)
+}
+
+func (r replayServer) watch(c *gin.Context) {
+	var req watchRequest
+	err := c.BindJSON(&req)
+	if err != nil {
+		c.AbortWithStatusJSON(400, gin.H{"error": fmt.Sprintf("can't bind json: %v", err)})
+		return
+	}
+
+	if !r.controller.IsPlayer(req.Name) {
+		c.AbortWithStatusJSON(409, gin.H{"error": "the replay is watched from the seat of one of its players"})
+		return
+	}
+
+	if r.watchers.IsWatched(req.Name) {
+		c.AbortWithStatusJSON(409, gin.H{"error": "this seat is already watched"})
+		return
+	}
+
+	session := r.session.Create(req.Name)
+	c.SetCookie("session", session, int(r.session.TTL().Seconds()), "/", "", false, true)
+
+	c.JSON(200, gin.H{"token": session})
+}
+
+func (r replayServer) checkSession(c *gin.Context) {
+	session, err := sessions.FromRequest(c.Request)
+	if err != nil {
+		c.AbortWithStatus(401)
+		return
+	}
+
+	_, err = r.session.Get(session)
+	if err != nil {
+		c.AbortWithStatus(403)
+		return
+	}
+
+	c.Next()
+}
+
+func (r replayServer) status(c *gin.Context) {
+	c.JSON(200, r.controller.Status())
+}
+
+func (r replayServer) step(move func() (Status, error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		status, err := move()
+		if err != nil {
+			c.AbortWithStatusJSON(409, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(200, status)
+	}
+}
+
+func (r replayServer) pause(c *gin.Context) {
+	c.JSON(200, r.controller.Pause())
+}
@@ -0,0 +1,197 @@
+package replay
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/damien-springuel/bomb-canary/server/messagebus"
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/gomega"
+)
+
+type mockSession struct {
+	givenName       string
+	receivedSession string
+	getError        error
+}
+
+func (m *mockSession) Create(name string) string {
+	m.givenName = name
+	return "testSessionId"
+}
+
+func (m *mockSession) Get(session string) (name string, err error) {
+	m.receivedSession = session
+	return "testName", m.getError
+}
+
+func (m *mockSession) TTL() time.Duration {
+	return time.Hour
+}
+
+type mockController struct {
+	calledWith string
+	moveError  error
+}
+
+func (m *mockController) IsPlayer(name string) bool {
+	return name == "Alice"
+}
+
+func (m *mockController) Status() Status {
+	m.calledWith = "status"
+	return Status{Position: 1, NbEvents: 3}
+}
+
+func (m *mockController) Forward() (Status, error) {
+	m.calledWith = "forward"
+	return Status{Position: 2, NbEvents: 3}, m.moveError
+}
+
+func (m *mockController) Back() (Status, error) {
+	m.calledWith = "back"
+	return Status{Position: 0, NbEvents: 3}, m.moveError
+}
+
+func (m *mockController) Play() (Status, error) {
+	m.calledWith = "play"
+	return Status{Position: 1, NbEvents: 3, Playing: true}, m.moveError
+}
+
+func (m *mockController) Pause() Status {
+	m.calledWith = "pause"
+	return Status{Position: 1, NbEvents: 3}
+}
+
+func makeCall(req *http.Request, session *mockSession, controller *mockController) *httptest.ResponseRecorder {
+	return makeCallWithWatchers(req, session, controller, NewWatchers())
+}
+
+func makeCallWithWatchers(req *http.Request, session *mockSession, controller *mockController, watchers *watchers) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	ginEngine := gin.New()
+	Register(ginEngine, session, controller, watchers)
+
+	w := httptest.NewRecorder()
+	ginEngine.ServeHTTP(w, req)
+	return w
+}
+
+func Test_Watch(t *testing.T) {
+	req, _ := http.NewRequest("POST", "/party/join", strings.NewReader(`{"name":"Alice"}`))
+	session := &mockSession{}
+	w := makeCall(req, session, &mockController{})
+
+	g := NewWithT(t)
+	g.Expect(w.Code).To(Equal(200))
+	g.Expect(w.Body.String()).To(Equal(`{"token":"testSessionId"}`))
+	g.Expect(w.Header().Get("Set-Cookie")).To(Equal("session=testSessionId; Path=/; Max-Age=3600; HttpOnly"))
+	g.Expect(session.givenName).To(Equal("Alice"))
+}
+
+func Test_Watch_Returns409IfNotAPlayerOfTheGame(t *testing.T) {
+	req, _ := http.NewRequest("POST", "/party/join", strings.NewReader(`{"name":"Charlie"}`))
+	session := &mockSession{}
+	w := makeCall(req, session, &mockController{})
+
+	g := NewWithT(t)
+	g.Expect(w.Code).To(Equal(409))
+	g.Expect(w.Body.String()).To(Equal(`{"error":"the replay is watched from the seat of one of its players"}`))
+	g.Expect(session.givenName).To(BeEmpty())
+}
+
+func Test_Watch_Returns409IfTheSeatIsAlreadyWatched(t *testing.T) {
+	watchers := NewWatchers()
+	firstReq, _ := http.NewRequest("POST", "/party/join", strings.NewReader(`{"name":"Alice"}`))
+	first := makeCallWithWatchers(firstReq, &mockSession{}, &mockController{}, watchers)
+	watchers.Consume(messagebus.PlayerConnected{Player: "Alice"})
+
+	secondReq, _ := http.NewRequest("POST", "/party/join", strings.NewReader(`{"name":"Alice"}`))
+	secondSession := &mockSession{}
+	second := makeCallWithWatchers(secondReq, secondSession, &mockController{}, watchers)
+
+	g := NewWithT(t)
+	g.Expect(first.Code).To(Equal(200))
+	g.Expect(second.Code).To(Equal(409))
+	g.Expect(second.Body.String()).To(Equal(`{"error":"this seat is already watched"}`))
+	g.Expect(secondSession.givenName).To(BeEmpty())
+
+	watchers.Consume(messagebus.PlayerDisconnected{Player: "Alice"})
+	thirdReq, _ := http.NewRequest("POST", "/party/join", strings.NewReader(`{"name":"Alice"}`))
+	third := makeCallWithWatchers(thirdReq, &mockSession{}, &mockController{}, watchers)
+	g.Expect(third.Code).To(Equal(200))
+}
+
+func Test_Watch_Returns400IfBodyInvalid(t *testing.T) {
+	req, _ := http.NewRequest("POST", "/party/join", strings.NewReader(`{`))
+	w := makeCall(req, &mockSession{}, &mockController{})
+
+	g := NewWithT(t)
+	g.Expect(w.Code).To(Equal(400))
+}
+
+func Test_Status(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/replay", nil)
+	req.AddCookie(&http.Cookie{Name: "session", Value: "testSession"})
+	session := &mockSession{}
+	w := makeCall(req, session, &mockController{})
+
+	g := NewWithT(t)
+	g.Expect(w.Code).To(Equal(200))
+	g.Expect(w.Body.String()).To(Equal(`{"position":1,"nbEvents":3,"playing":false}`))
+	g.Expect(session.receivedSession).To(Equal("testSession"))
+}
+
+func Test_Controls(t *testing.T) {
+	for path, expected := range map[string]string{
+		"/replay/forward": `{"position":2,"nbEvents":3,"playing":false}`,
+		"/replay/back":    `{"position":0,"nbEvents":3,"playing":false}`,
+		"/replay/play":    `{"position":1,"nbEvents":3,"playing":true}`,
+		"/replay/pause":   `{"position":1,"nbEvents":3,"playing":false}`,
+	} {
+		req, _ := http.NewRequest("POST", path, nil)
+		req.Header.Set("Authorization", "Bearer testSession")
+		controller := &mockController{}
+		w := makeCall(req, &mockSession{}, controller)
+
+		g := NewWithT(t)
+		g.Expect(w.Code).To(Equal(200))
+		g.Expect(w.Body.String()).To(Equal(expected))
+		g.Expect("/replay/" + controller.calledWith).To(Equal(path))
+	}
+}
+
+func Test_Controls_Returns409IfMoveImpossible(t *testing.T) {
+	req, _ := http.NewRequest("POST", "/replay/back", nil)
+	req.AddCookie(&http.Cookie{Name: "session", Value: "testSession"})
+	w := makeCall(req, &mockSession{}, &mockController{moveError: errAtStart})
+
+	g := NewWithT(t)
+	g.Expect(w.Code).To(Equal(409))
+	g.Expect(w.Body.String()).To(Equal(`{"error":"replay is at the first event"}`))
+}
+
+func Test_Controls_Returns401IfNoSession(t *testing.T) {
+	req, _ := http.NewRequest("POST", "/replay/forward", nil)
+	controller := &mockController{}
+	w := makeCall(req, &mockSession{}, controller)
+
+	g := NewWithT(t)
+	g.Expect(w.Code).To(Equal(401))
+	g.Expect(controller.calledWith).To(BeEmpty())
+}
+
+func Test_Controls_Returns403IfSessionInvalid(t *testing.T) {
+	req, _ := http.NewRequest("POST", "/replay/forward", nil)
+	req.AddCookie(&http.Cookie{Name: "session", Value: "testSession"})
+	controller := &mockController{}
+	w := makeCall(req, &mockSession{getError: errors.New("invalid")}, controller)
+
+	g := NewWithT(t)
+	g.Expect(w.Code).To(Equal(403))
+	g.Expect(controller.calledWith).To(BeEmpty())
+}
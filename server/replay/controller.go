@@ -0,0 +1,174 @@
+package replay
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/damien-springuel/bomb-canary/server/messagebus"
+)
+
+var (
+	errAtStart = errors.New("replay is at the first event")
+	errAtEnd   = errors.New("replay is at the last event")
+)
+
+type messageDispatcher interface {
+	Dispatch(m messagebus.Message)
+}
+
+type Status struct {
+	Position int  `json:"position"`
+	NbEvents int  `json:"nbEvents"`
+	Playing  bool `json:"playing"`
+}
+
+type controller struct {
+	mut               *sync.Mutex
+	messageDispatcher messageDispatcher
+	events            []messagebus.Message
+	players           map[string]bool
+	interval          time.Duration
+	after             func(d time.Duration, f func())
+	position          int
+	playing           bool
+	playback          int
+}
+
+func NewController(messageDispatcher messageDispatcher, events []messagebus.Message, interval time.Duration) *controller {
+	players := make(map[string]bool)
+	for _, event := range events {
+		switch event := event.(type) {
+		case messagebus.PlayerJoined:
+			players[event.Player] = true
+		case messagebus.RematchStarted:
+			for _, name := range event.Players {
+				players[name] = true
+			}
+		}
+	}
+
+	return &controller{
+		mut:               &sync.Mutex{},
+		messageDispatcher: messageDispatcher,
+		events:            events,
+		players:           players,
+		interval:          interval,
+		after: func(d time.Duration, f func()) {
+			time.AfterFunc(d, f)
+		},
+	}
+}
+
+func (c *controller) Start() {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	c.dispatchProgress()
+}
+
+func (c *controller) IsPlayer(name string) bool {
+	return c.players[name]
+}
+
+func (c *controller) Status() Status {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	return c.status()
+}
+
+func (c *controller) Forward() (Status, error) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	if c.position >= len(c.events) {
+		return c.status(), errAtEnd
+	}
+	c.forward()
+	c.dispatchProgress()
+	return c.status(), nil
+}
+
+func (c *controller) Back() (Status, error) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	if c.position == 0 {
+		return c.status(), errAtStart
+	}
+	c.position -= 1
+	c.messageDispatcher.Dispatch(messagebus.ReplayRewound{})
+	for _, event := range c.events[:c.position] {
+		c.messageDispatcher.Dispatch(event)
+	}
+	c.messageDispatcher.Dispatch(messagebus.ReplayRewindEnded{})
+	c.dispatchProgress()
+	return c.status(), nil
+}
+
+func (c *controller) Play() (Status, error) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	if c.position >= len(c.events) {
+		return c.status(), errAtEnd
+	}
+	if !c.playing {
+		c.playing = true
+		c.playback += 1
+		c.scheduleTick(c.playback)
+		c.dispatchProgress()
+	}
+	return c.status(), nil
+}
+
+func (c *controller) Pause() Status {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	if c.playing {
+		c.playing = false
+		c.playback += 1
+		c.dispatchProgress()
+	}
+	return c.status()
+}
+
+func (c *controller) scheduleTick(playback int) {
+	c.after(c.interval, func() {
+		c.mut.Lock()
+		defer c.mut.Unlock()
+
+		if !c.playing || c.playback != playback {
+			return
+		}
+		c.forward()
+		if c.playing {
+			c.scheduleTick(playback)
+		}
+		c.dispatchProgress()
+	})
+}
+
+func (c *controller) forward() {
+	c.messageDispatcher.Dispatch(c.events[c.position])
+	c.position += 1
+	if c.position >= len(c.events) {
+		c.playing = false
+	}
+}
+
+func (c *controller) status() Status {
+	return Status{
+		Position: c.position,
+		NbEvents: len(c.events),
+		Playing:  c.playing,
+	}
+}
+
+func (c *controller) dispatchProgress() {
+	c.messageDispatcher.Dispatch(messagebus.ReplayProgressed{
+		Position: c.position,
+		NbEvents: len(c.events),
+		Playing:  c.playing,
+	})
+}
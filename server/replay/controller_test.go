@@ -0,0 +1,199 @@
+package replay
+
+import (
+	"testing"
+	"time"
+
+	"github.com/damien-springuel/bomb-canary/server/messagebus"
+	. "github.com/onsi/gomega"
+)
+
+type mockDispatcher struct {
+	receivedMessages []messagebus.Message
+}
+
+func (m *mockDispatcher) Dispatch(message messagebus.Message) {
+	m.receivedMessages = append(m.receivedMessages, message)
+}
+
+type scheduledCall struct {
+	delay time.Duration
+	call  func()
+}
+
+func recordedEvents() []messagebus.Message {
+	return []messagebus.Message{
+		messagebus.PlayerJoined{Player: "Alice"},
+		messagebus.PlayerJoined{Player: "Bob"},
+		messagebus.GameStarted{},
+	}
+}
+
+func setupController() (*mockDispatcher, *[]scheduledCall, *controller) {
+	dispatcher := &mockDispatcher{}
+	scheduled := &[]scheduledCall{}
+	c := NewController(dispatcher, recordedEvents(), 2*time.Second)
+	c.after = func(d time.Duration, f func()) {
+		*scheduled = append(*scheduled, scheduledCall{delay: d, call: f})
+	}
+	return dispatcher, scheduled, c
+}
+
+func Test_Controller_StartDispatchesProgress(t *testing.T) {
+	dispatcher, _, c := setupController()
+	c.Start()
+
+	g := NewWithT(t)
+	g.Expect(dispatcher.receivedMessages).To(Equal([]messagebus.Message{
+		messagebus.ReplayProgressed{Position: 0, NbEvents: 3},
+	}))
+	g.Expect(c.Status()).To(Equal(Status{Position: 0, NbEvents: 3}))
+}
+
+func Test_Controller_IsPlayer(t *testing.T) {
+	c := NewController(&mockDispatcher{}, []messagebus.Message{
+		messagebus.PlayerJoined{Player: "Alice"},
+		messagebus.RematchStarted{Players: []string{"Bob", "Alice"}},
+	}, time.Second)
+
+	g := NewWithT(t)
+	g.Expect(c.IsPlayer("Alice")).To(BeTrue())
+	g.Expect(c.IsPlayer("Bob")).To(BeTrue())
+	g.Expect(c.IsPlayer("Charlie")).To(BeFalse())
+}
+
+func Test_Controller_Forward(t *testing.T) {
+	dispatcher, _, c := setupController()
+	c.Forward()
+	status, err := c.Forward()
+
+	g := NewWithT(t)
+	g.Expect(err).To(BeNil())
+	g.Expect(status).To(Equal(Status{Position: 2, NbEvents: 3}))
+	g.Expect(dispatcher.receivedMessages).To(Equal([]messagebus.Message{
+		messagebus.PlayerJoined{Player: "Alice"},
+		messagebus.ReplayProgressed{Position: 1, NbEvents: 3},
+		messagebus.PlayerJoined{Player: "Bob"},
+		messagebus.ReplayProgressed{Position: 2, NbEvents: 3},
+	}))
+}
+
+func Test_Controller_ForwardShouldErrorAtTheEnd(t *testing.T) {
+	dispatcher, _, c := setupController()
+	c.Forward()
+	c.Forward()
+	c.Forward()
+	dispatcher.receivedMessages = nil
+
+	status, err := c.Forward()
+
+	g := NewWithT(t)
+	g.Expect(err).To(Equal(errAtEnd))
+	g.Expect(status).To(Equal(Status{Position: 3, NbEvents: 3}))
+	g.Expect(dispatcher.receivedMessages).To(BeNil())
+}
+
+func Test_Controller_BackRewindsAndReplaysPreviousEvents(t *testing.T) {
+	dispatcher, _, c := setupController()
+	c.Forward()
+	c.Forward()
+	dispatcher.receivedMessages = nil
+
+	status, err := c.Back()
+
+	g := NewWithT(t)
+	g.Expect(err).To(BeNil())
+	g.Expect(status).To(Equal(Status{Position: 1, NbEvents: 3}))
+	g.Expect(dispatcher.receivedMessages).To(Equal([]messagebus.Message{
+		messagebus.ReplayRewound{},
+		messagebus.PlayerJoined{Player: "Alice"},
+		messagebus.ReplayRewindEnded{},
+		messagebus.ReplayProgressed{Position: 1, NbEvents: 3},
+	}))
+}
+
+func Test_Controller_BackShouldErrorAtTheStart(t *testing.T) {
+	dispatcher, _, c := setupController()
+
+	status, err := c.Back()
+
+	g := NewWithT(t)
+	g.Expect(err).To(Equal(errAtStart))
+	g.Expect(status).To(Equal(Status{Position: 0, NbEvents: 3}))
+	g.Expect(dispatcher.receivedMessages).To(BeNil())
+}
+
+func Test_Controller_PlayStepsForwardUntilTheEnd(t *testing.T) {
+	dispatcher, scheduled, c := setupController()
+
+	status, err := c.Play()
+
+	g := NewWithT(t)
+	g.Expect(err).To(BeNil())
+	g.Expect(status).To(Equal(Status{Position: 0, NbEvents: 3, Playing: true}))
+	g.Expect(*scheduled).To(HaveLen(1))
+	g.Expect((*scheduled)[0].delay).To(Equal(2 * time.Second))
+
+	(*scheduled)[0].call()
+	(*scheduled)[1].call()
+	(*scheduled)[2].call()
+
+	g.Expect(*scheduled).To(HaveLen(3))
+	g.Expect(c.Status()).To(Equal(Status{Position: 3, NbEvents: 3, Playing: false}))
+	g.Expect(dispatcher.receivedMessages).To(Equal([]messagebus.Message{
+		messagebus.ReplayProgressed{Position: 0, NbEvents: 3, Playing: true},
+		messagebus.PlayerJoined{Player: "Alice"},
+		messagebus.ReplayProgressed{Position: 1, NbEvents: 3, Playing: true},
+		messagebus.PlayerJoined{Player: "Bob"},
+		messagebus.ReplayProgressed{Position: 2, NbEvents: 3, Playing: true},
+		messagebus.GameStarted{},
+		messagebus.ReplayProgressed{Position: 3, NbEvents: 3, Playing: false},
+	}))
+}
+
+func Test_Controller_PauseIgnoresPendingTick(t *testing.T) {
+	dispatcher, scheduled, c := setupController()
+	c.Play()
+	dispatcher.receivedMessages = nil
+
+	status := c.Pause()
+	(*scheduled)[0].call()
+
+	g := NewWithT(t)
+	g.Expect(status).To(Equal(Status{Position: 0, NbEvents: 3, Playing: false}))
+	g.Expect(*scheduled).To(HaveLen(1))
+	g.Expect(dispatcher.receivedMessages).To(Equal([]messagebus.Message{
+		messagebus.ReplayProgressed{Position: 0, NbEvents: 3, Playing: false},
+	}))
+}
+
+func Test_Controller_PlayAgainAfterPauseIgnoresOldTick(t *testing.T) {
+	dispatcher, scheduled, c := setupController()
+	c.Play()
+	c.Pause()
+	c.Play()
+	dispatcher.receivedMessages = nil
+
+	(*scheduled)[0].call()
+	(*scheduled)[1].call()
+
+	g := NewWithT(t)
+	g.Expect(c.Status()).To(Equal(Status{Position: 1, NbEvents: 3, Playing: true}))
+	g.Expect(dispatcher.receivedMessages).To(Equal([]messagebus.Message{
+		messagebus.PlayerJoined{Player: "Alice"},
+		messagebus.ReplayProgressed{Position: 1, NbEvents: 3, Playing: true},
+	}))
+}
+
+func Test_Controller_PlayShouldErrorAtTheEnd(t *testing.T) {
+	_, scheduled, c := setupController()
+	c.Forward()
+	c.Forward()
+	c.Forward()
+
+	_, err := c.Play()
+
+	g := NewWithT(t)
+	g.Expect(err).To(Equal(errAtEnd))
+	g.Expect(*scheduled).To(BeEmpty())
+}
@@ -0,0 +1,37 @@
+package replay
+
+import (
+	"sync"
+
+	"github.com/damien-springuel/bomb-canary/server/messagebus"
+)
+
+type watchers struct {
+	mut       *sync.RWMutex
+	connected map[string]bool
+}
+
+func NewWatchers() *watchers {
+	return &watchers{
+		mut:       &sync.RWMutex{},
+		connected: make(map[string]bool),
+	}
+}
+
+func (w *watchers) Consume(m messagebus.Message) {
+	w.mut.Lock()
+	defer w.mut.Unlock()
+
+	switch m := m.(type) {
+	case messagebus.PlayerConnected:
+		w.connected[m.Player] = true
+	case messagebus.PlayerDisconnected:
+		delete(w.connected, m.Player)
+	}
+}
+
+func (w *watchers) IsWatched(name string) bool {
+	w.mut.RLock()
+	defer w.mut.RUnlock()
+	return w.connected[name]
+}
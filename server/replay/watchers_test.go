@@ -0,0 +1,22 @@
+package replay
+
+import (
+	"testing"
+
+	"github.com/damien-springuel/bomb-canary/server/messagebus"
+	. "github.com/onsi/gomega"
+)
+
+func Test_Watchers_SeatIsWatchedWhileItsStreamIsConnected(t *testing.T) {
+	w := NewWatchers()
+
+	g := NewWithT(t)
+	g.Expect(w.IsWatched("Alice")).To(BeFalse())
+
+	w.Consume(messagebus.PlayerConnected{Player: "Alice"})
+	g.Expect(w.IsWatched("Alice")).To(BeTrue())
+	g.Expect(w.IsWatched("Bob")).To(BeFalse())
+
+	w.Consume(messagebus.PlayerDisconnected{Player: "Alice"})
+	g.Expect(w.IsWatched("Alice")).To(BeFalse())
+}
@@ -0,0 +1,58 @@
+package sessions
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+)
+
+const WebsocketProtocol = "bearer"
+
+var ErrNoSession = errors.New("no session in request")
+
+func FromRequest(r *http.Request) (string, error) {
+	cookie, err := r.Cookie("session")
+	if err == nil && cookie.Value != "" {
+		return cookie.Value, nil
+	}
+
+	authorization := r.Header.Get("Authorization")
+	if strings.HasPrefix(authorization, "Bearer ") {
+		return strings.TrimSpace(strings.TrimPrefix(authorization, "Bearer ")), nil
+	}
+
+	protocols := websocketProtocols(r)
+	for i, protocol := range protocols {
+		if protocol == WebsocketProtocol && i+1 < len(protocols) {
+			return protocols[i+1], nil
+		}
+	}
+
+	return "", ErrNoSession
+}
+
+// FromWebsocketRequest also accepts a token in the query since browsers can't set
+// headers on a websocket upgrade. Every other endpoint should use FromRequest.
+func FromWebsocketRequest(r *http.Request) (string, error) {
+	session, err := FromRequest(r)
+	if err == nil {
+		return session, nil
+	}
+
+	token := r.URL.Query().Get("token")
+	if token != "" {
+		return token, nil
+	}
+
+	return "", ErrNoSession
+}
+
+func websocketProtocols(r *http.Request) []string {
+	protocols := []string{}
+	for _, header := range r.Header.Values("Sec-WebSocket-Protocol") {
+		for _, protocol := range strings.Split(header, ",") {
+			protocols = append(protocols, strings.TrimSpace(protocol))
+		}
+	}
+	return protocols
+}
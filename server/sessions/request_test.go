@@ -0,0 +1,83 @@
+package sessions
+
+import (
+	"net/http"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func Test_FromRequest_Cookie(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/", nil)
+	req.AddCookie(&http.Cookie{Name: "session", Value: "cookieSession"})
+	req.Header.Set("Authorization", "Bearer headerSession")
+
+	session, err := FromRequest(req)
+
+	g := NewWithT(t)
+	g.Expect(err).To(BeNil())
+	g.Expect(session).To(Equal("cookieSession"))
+}
+
+func Test_FromRequest_BearerHeader(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/?token=querySession", nil)
+	req.Header.Set("Authorization", "Bearer headerSession")
+
+	session, err := FromRequest(req)
+
+	g := NewWithT(t)
+	g.Expect(err).To(BeNil())
+	g.Expect(session).To(Equal("headerSession"))
+}
+
+func Test_FromRequest_WebsocketProtocol(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/?token=querySession", nil)
+	req.Header.Set("Sec-WebSocket-Protocol", "bearer, protocolSession")
+
+	session, err := FromRequest(req)
+
+	g := NewWithT(t)
+	g.Expect(err).To(BeNil())
+	g.Expect(session).To(Equal("protocolSession"))
+}
+
+func Test_FromRequest_IgnoresQueryToken(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/?token=querySession", nil)
+
+	_, err := FromRequest(req)
+
+	g := NewWithT(t)
+	g.Expect(err).To(Equal(ErrNoSession))
+}
+
+func Test_FromWebsocketRequest_QueryToken(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/?token=querySession", nil)
+
+	session, err := FromWebsocketRequest(req)
+
+	g := NewWithT(t)
+	g.Expect(err).To(BeNil())
+	g.Expect(session).To(Equal("querySession"))
+}
+
+func Test_FromWebsocketRequest_PrefersHeaders(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/?token=querySession", nil)
+	req.Header.Set("Authorization", "Bearer headerSession")
+
+	session, err := FromWebsocketRequest(req)
+
+	g := NewWithT(t)
+	g.Expect(err).To(BeNil())
+	g.Expect(session).To(Equal("headerSession"))
+}
+
+func Test_FromRequest_NoSession(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/", nil)
+	req.Header.Set("Authorization", "Basic abc")
+	req.Header.Set("Sec-WebSocket-Protocol", "bearer")
+
+	_, err := FromRequest(req)
+
+	g := NewWithT(t)
+	g.Expect(err).To(Equal(ErrNoSession))
+}
@@ -0,0 +1,197 @@
+package sessions
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	errMalformedToken = errors.New("malformed session token")
+	errUnknownKey     = errors.New("session token signed with unknown key")
+	errBadSignature   = errors.New("invalid session token signature")
+	errWrongParty     = errors.New("session token is for another party")
+	errExpiredToken   = errors.New("session token expired")
+	errRevokedToken   = errors.New("session token revoked")
+)
+
+type claims struct {
+	Player    string `json:"player"`
+	Party     string `json:"party"`
+	IssuedAt  int64  `json:"iat"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+type signingKey struct {
+	id     string
+	secret []byte
+}
+
+func newSigningKey(secret []byte) signingKey {
+	hash := sha256.Sum256(secret)
+	return signingKey{id: hex.EncodeToString(hash[:4]), secret: secret}
+}
+
+func (k signingKey) sign(payload string) string {
+	mac := hmac.New(sha256.New, k.secret)
+	mac.Write([]byte(k.id + "." + payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+type signedSessions struct {
+	party         string
+	ttl           time.Duration
+	keys          []signingKey
+	now           func() time.Time
+	mut           *sync.RWMutex
+	revokedBefore map[string]time.Time
+}
+
+func NewSigned(party string, ttl time.Duration, secrets ...[]byte) (signedSessions, error) {
+	if len(secrets) == 0 {
+		return signedSessions{}, errors.New("at least one session key is required")
+	}
+
+	keys := make([]signingKey, len(secrets))
+	for i, secret := range secrets {
+		if len(secret) == 0 {
+			return signedSessions{}, errors.New("session keys can't be empty")
+		}
+		keys[i] = newSigningKey(secret)
+	}
+
+	return signedSessions{
+		party:         party,
+		ttl:           ttl,
+		keys:          keys,
+		now:           time.Now,
+		mut:           &sync.RWMutex{},
+		revokedBefore: make(map[string]time.Time),
+	}, nil
+}
+
+func (s signedSessions) Create(name string) string {
+	now := s.now()
+	payloadBytes, _ := json.Marshal(claims{
+		Player:    name,
+		Party:     s.party,
+		IssuedAt:  now.UnixNano(),
+		ExpiresAt: now.Add(s.ttl).Unix(),
+	})
+	payload := base64.RawURLEncoding.EncodeToString(payloadBytes)
+
+	key := s.keys[0]
+	return key.id + "." + payload + "." + key.sign(payload)
+}
+
+func (s signedSessions) TTL() time.Duration {
+	return s.ttl
+}
+
+func (s signedSessions) Get(session string) (name string, err error) {
+	c, err := s.verify(session)
+	if err != nil {
+		return "", err
+	}
+	return c.Player, nil
+}
+
+func (s signedSessions) Renew(session string) (renewed string, ok bool) {
+	c, err := s.verify(session)
+	if err != nil {
+		return "", false
+	}
+	if time.Unix(c.ExpiresAt, 0).Sub(s.now()) > s.ttl/2 {
+		return "", false
+	}
+	return s.Create(c.Player), true
+}
+
+func (s signedSessions) RevokePlayer(name string) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	s.revokedBefore[name] = s.now()
+}
+
+func (s signedSessions) CleanUp() {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+	for name, revokedAt := range s.revokedBefore {
+		if !s.now().Before(revokedAt.Add(s.ttl)) {
+			delete(s.revokedBefore, name)
+		}
+	}
+}
+
+func (s signedSessions) StartCleanUp(interval time.Duration) (stop func()) {
+	ticker := time.NewTicker(interval)
+	done := make(chan bool)
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				s.CleanUp()
+			case <-done:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+	return func() { close(done) }
+}
+
+func (s signedSessions) verify(session string) (claims, error) {
+	parts := strings.Split(session, ".")
+	if len(parts) != 3 {
+		return claims{}, errMalformedToken
+	}
+	keyId, payload, signature := parts[0], parts[1], parts[2]
+
+	key, found := s.keyById(keyId)
+	if !found {
+		return claims{}, errUnknownKey
+	}
+	if !hmac.Equal([]byte(signature), []byte(key.sign(payload))) {
+		return claims{}, errBadSignature
+	}
+
+	payloadBytes, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return claims{}, errMalformedToken
+	}
+	var c claims
+	err = json.Unmarshal(payloadBytes, &c)
+	if err != nil || c.Player == "" {
+		return claims{}, errMalformedToken
+	}
+
+	if c.Party != s.party {
+		return claims{}, errWrongParty
+	}
+	if !s.now().Before(time.Unix(c.ExpiresAt, 0)) {
+		return claims{}, errExpiredToken
+	}
+
+	s.mut.RLock()
+	defer s.mut.RUnlock()
+	if revokedAt, revoked := s.revokedBefore[c.Player]; revoked && c.IssuedAt < revokedAt.UnixNano() {
+		return claims{}, errRevokedToken
+	}
+
+	return c, nil
+}
+
+func (s signedSessions) keyById(id string) (signingKey, bool) {
+	for _, key := range s.keys {
+		if key.id == id {
+			return key, true
+		}
+	}
+	return signingKey{}, false
+}
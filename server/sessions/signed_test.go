@@ -0,0 +1,175 @@
+package sessions
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func fixedNow(now time.Time) func() time.Time {
+	return func() time.Time { return now }
+}
+
+func Test_Signed_CreateAndGet(t *testing.T) {
+	s, err := NewSigned("party1", time.Hour, []byte("key1"))
+	g := NewWithT(t)
+	g.Expect(err).To(BeNil())
+
+	token := s.Create("name")
+	g.Expect(strings.Split(token, ".")).To(HaveLen(3))
+
+	name, err := s.Get(token)
+	g.Expect(err).To(BeNil())
+	g.Expect(name).To(Equal("name"))
+}
+
+func Test_Signed_SurvivesRestartWithSameKey(t *testing.T) {
+	before, _ := NewSigned("party1", time.Hour, []byte("key1"))
+	after, _ := NewSigned("party1", time.Hour, []byte("key1"))
+
+	name, err := after.Get(before.Create("name"))
+
+	g := NewWithT(t)
+	g.Expect(err).To(BeNil())
+	g.Expect(name).To(Equal("name"))
+}
+
+func Test_Signed_KeyRotation(t *testing.T) {
+	old, _ := NewSigned("party1", time.Hour, []byte("old"))
+	rotated, _ := NewSigned("party1", time.Hour, []byte("new"), []byte("old"))
+	retired, _ := NewSigned("party1", time.Hour, []byte("new"))
+
+	oldToken := old.Create("name")
+	newToken := rotated.Create("name")
+
+	g := NewWithT(t)
+	name, err := rotated.Get(oldToken)
+	g.Expect(err).To(BeNil())
+	g.Expect(name).To(Equal("name"))
+
+	name, err = retired.Get(newToken)
+	g.Expect(err).To(BeNil())
+	g.Expect(name).To(Equal("name"))
+
+	_, err = retired.Get(oldToken)
+	g.Expect(err).To(Equal(errUnknownKey))
+}
+
+func Test_Signed_RejectsTamperedToken(t *testing.T) {
+	s, _ := NewSigned("party1", time.Hour, []byte("key1"))
+	other, _ := NewSigned("party1", time.Hour, []byte("key1"))
+	other.now = fixedNow(time.Now().Add(time.Minute))
+
+	parts := strings.Split(s.Create("name"), ".")
+	otherParts := strings.Split(other.Create("someoneElse"), ".")
+
+	_, err := s.Get(parts[0] + "." + otherParts[1] + "." + parts[2])
+
+	g := NewWithT(t)
+	g.Expect(err).To(Equal(errBadSignature))
+}
+
+func Test_Signed_RejectsOtherParty(t *testing.T) {
+	s, _ := NewSigned("party1", time.Hour, []byte("key1"))
+	other, _ := NewSigned("party2", time.Hour, []byte("key1"))
+
+	_, err := s.Get(other.Create("name"))
+
+	g := NewWithT(t)
+	g.Expect(err).To(Equal(errWrongParty))
+}
+
+func Test_Signed_RejectsExpiredToken(t *testing.T) {
+	s, _ := NewSigned("party1", time.Hour, []byte("key1"))
+	now := time.Now()
+	s.now = fixedNow(now)
+	token := s.Create("name")
+
+	s.now = fixedNow(now.Add(time.Hour))
+	_, err := s.Get(token)
+
+	g := NewWithT(t)
+	g.Expect(err).To(Equal(errExpiredToken))
+}
+
+func Test_Signed_RejectsMalformedToken(t *testing.T) {
+	s, _ := NewSigned("party1", time.Hour, []byte("key1"))
+
+	g := NewWithT(t)
+	_, err := s.Get("1")
+	g.Expect(err).To(Equal(errMalformedToken))
+
+	_, err = s.Get("a.b.c")
+	g.Expect(err).To(Equal(errUnknownKey))
+}
+
+func Test_NewSigned_RequiresKeys(t *testing.T) {
+	g := NewWithT(t)
+	_, err := NewSigned("party1", time.Hour)
+	g.Expect(err).ToNot(BeNil())
+
+	_, err = NewSigned("party1", time.Hour, []byte{})
+	g.Expect(err).ToNot(BeNil())
+}
+
+func Test_Signed_RenewsOnlyPastHalfTheTtl(t *testing.T) {
+	s, _ := NewSigned("party1", time.Hour, []byte("key1"))
+	now := time.Now()
+	s.now = fixedNow(now)
+	token := s.Create("name")
+
+	g := NewWithT(t)
+	_, ok := s.Renew(token)
+	g.Expect(ok).To(BeFalse())
+
+	s.now = fixedNow(now.Add(40 * time.Minute))
+	renewed, ok := s.Renew(token)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(renewed).ToNot(Equal(token))
+
+	s.now = fixedNow(now.Add(90 * time.Minute))
+	_, err := s.Get(token)
+	g.Expect(err).To(Equal(errExpiredToken))
+	name, err := s.Get(renewed)
+	g.Expect(err).To(BeNil())
+	g.Expect(name).To(Equal("name"))
+}
+
+func Test_Signed_DoesntRenewInvalidToken(t *testing.T) {
+	s, _ := NewSigned("party1", time.Hour, []byte("key1"))
+
+	_, ok := s.Renew("a.b.c")
+
+	g := NewWithT(t)
+	g.Expect(ok).To(BeFalse())
+}
+
+func Test_Signed_RevokePlayerRevokesOnlyEarlierSessions(t *testing.T) {
+	s, _ := NewSigned("party1", time.Hour, []byte("key1"))
+	now := time.Now()
+	s.now = fixedNow(now)
+	oldToken := s.Create("name")
+	otherToken := s.Create("other")
+
+	s.now = fixedNow(now.Add(time.Second))
+	s.RevokePlayer("name")
+	newToken := s.Create("name")
+
+	g := NewWithT(t)
+	_, err := s.Get(oldToken)
+	g.Expect(err).To(Equal(errRevokedToken))
+
+	name, err := s.Get(newToken)
+	g.Expect(err).To(BeNil())
+	g.Expect(name).To(Equal("name"))
+
+	name, err = s.Get(otherToken)
+	g.Expect(err).To(BeNil())
+	g.Expect(name).To(Equal("other"))
+
+	s.now = fixedNow(now.Add(time.Hour + time.Second))
+	s.CleanUp()
+	g.Expect(s.revokedBefore).To(BeEmpty())
+}
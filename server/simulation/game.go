@@ -0,0 +1,225 @@
+package simulation
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+
+	"github.com/damien-springuel/bomb-canary/server/gamehub"
+	"github.com/damien-springuel/bomb-canary/server/gamerules"
+	"github.com/damien-springuel/bomb-canary/server/messagebus"
+)
+
+var errGameStalled = errors.New("game stalled before ending")
+
+type seededAllegianceGenerator struct {
+	random *rand.Rand
+}
+
+func NewSeededAllegianceGenerator(seed int64) seededAllegianceGenerator {
+	return seededAllegianceGenerator{random: rand.New(rand.NewSource(seed))}
+}
+
+func (s seededAllegianceGenerator) Generate(nbPlayers, nbSpies int) []gamerules.Allegiance {
+	allegiances := make([]gamerules.Allegiance, nbPlayers)
+	for i := range allegiances {
+		if i < nbSpies {
+			allegiances[i] = gamerules.Spy
+		} else {
+			allegiances[i] = gamerules.Resistance
+		}
+	}
+	s.random.Shuffle(len(allegiances), func(i, j int) { allegiances[i], allegiances[j] = allegiances[j], allegiances[i] })
+	return allegiances
+}
+
+type eventQueue struct {
+	messages []messagebus.Message
+}
+
+func (q *eventQueue) Dispatch(m messagebus.Message) {
+	q.messages = append(q.messages, m)
+}
+
+func (q *eventQueue) next() (messagebus.Message, bool) {
+	if len(q.messages) == 0 {
+		return nil, false
+	}
+	m := q.messages[0]
+	q.messages = q.messages[1:]
+	return m, true
+}
+
+type seat struct {
+	name     string
+	strategy Strategy
+}
+
+type gameResult struct {
+	nbPlayers   int
+	winner      gamerules.Allegiance
+	allegiances map[string]gamerules.Allegiance
+	strategies  map[string]string
+}
+
+type table struct {
+	seats        []seat
+	random       *rand.Rand
+	queue        *eventQueue
+	hub          interface{ Consume(m messagebus.Message) }
+	players      []string
+	allegiances  map[string]gamerules.Allegiance
+	spies        []string
+	requirements []messagebus.MissionRequirement
+	leader       string
+	mission      gamerules.Mission
+	team         []string
+	voteFailures int
+	missions     []MissionSummary
+}
+
+func playGame(seats []seat, seed int64) (gameResult, error) {
+	queue := &eventQueue{}
+	t := &table{
+		seats:       seats,
+		random:      rand.New(rand.NewSource(seed)),
+		queue:       queue,
+		hub:         gamehub.New(queue, NewSeededAllegianceGenerator(seed)),
+		allegiances: make(map[string]gamerules.Allegiance),
+		mission:     gamerules.First,
+	}
+
+	for _, s := range seats {
+		t.hub.Consume(messagebus.JoinParty{Player: s.name})
+	}
+	for _, s := range seats {
+		t.hub.Consume(messagebus.MarkPlayerReady{Player: s.name})
+	}
+	t.hub.Consume(messagebus.StartGame{Player: seats[0].name})
+
+	for {
+		m, ok := queue.next()
+		if !ok {
+			return gameResult{}, errGameStalled
+		}
+
+		if gameEnded, ok := m.(messagebus.GameEnded); ok {
+			result := gameResult{
+				nbPlayers:   len(seats),
+				winner:      gamerules.Allegiance(gameEnded.Winner),
+				allegiances: t.allegiances,
+				strategies:  make(map[string]string),
+			}
+			for _, s := range seats {
+				result.strategies[s.name] = s.strategy.Name()
+			}
+			return result, nil
+		}
+
+		err := t.handle(m)
+		if err != nil {
+			return gameResult{}, err
+		}
+	}
+}
+
+func (t *table) handle(m messagebus.Message) error {
+	switch m := m.(type) {
+	case messagebus.PlayerJoined:
+		t.players = append(t.players, m.Player)
+
+	case messagebus.GameStarted:
+		t.requirements = m.MissionRequirements
+
+	case messagebus.AllegianceRevealed:
+		for name, allegiance := range m.AllegianceByPlayer {
+			t.allegiances[name] = gamerules.Allegiance(allegiance)
+			if allegiance == messagebus.Spy {
+				t.spies = append(t.spies, name)
+			}
+		}
+		sort.Strings(t.spies)
+
+	case messagebus.LeaderStartedToSelectMembers:
+		t.leader = m.Leader
+		t.team = nil
+		leader, err := t.seat(m.Leader)
+		if err != nil {
+			return err
+		}
+		for _, member := range leader.strategy.SelectTeam(t.viewFor(m.Leader), t.random) {
+			t.hub.Consume(messagebus.LeaderSelectsMember{Leader: m.Leader, MemberToSelect: member})
+		}
+		t.hub.Consume(messagebus.LeaderConfirmsTeamSelection{Leader: m.Leader})
+
+	case messagebus.LeaderSelectedMember:
+		t.team = append(t.team, m.SelectedMember)
+
+	case messagebus.LeaderConfirmedSelection:
+		for _, s := range t.seats {
+			if s.strategy.VoteOnTeam(t.viewFor(s.name), t.random) {
+				t.hub.Consume(messagebus.ApproveTeam{Player: s.name})
+			} else {
+				t.hub.Consume(messagebus.RejectTeam{Player: s.name})
+			}
+		}
+
+	case messagebus.AllPlayerVotedOnTeam:
+		t.voteFailures = m.VoteFailures
+
+	case messagebus.MissionStarted:
+		for _, member := range t.team {
+			s, err := t.seat(member)
+			if err != nil {
+				return err
+			}
+			if s.strategy.WorkOnMission(t.viewFor(member), t.random) {
+				t.hub.Consume(messagebus.SucceedMission{Player: member})
+			} else {
+				t.hub.Consume(messagebus.FailMission{Player: member})
+			}
+		}
+
+	case messagebus.MissionCompleted:
+		t.missions = append(t.missions, MissionSummary{
+			Team:    append([]string(nil), t.team...),
+			NbFails: m.Outcomes[false],
+			Success: m.Success,
+		})
+		t.mission += 1
+	}
+	return nil
+}
+
+func (t *table) seat(name string) (seat, error) {
+	for _, s := range t.seats {
+		if s.name == name {
+			return s, nil
+		}
+	}
+	return seat{}, fmt.Errorf("no seat for player %s", name)
+}
+
+func (t *table) viewFor(name string) View {
+	view := View{
+		Me:           name,
+		Allegiance:   t.allegiances[name],
+		Players:      append([]string(nil), t.players...),
+		NbSpies:      len(t.spies),
+		Leader:       t.leader,
+		Mission:      t.mission,
+		Team:         append([]string(nil), t.team...),
+		VoteFailures: t.voteFailures,
+		Missions:     t.missions,
+	}
+	if view.Allegiance == gamerules.Spy {
+		view.Spies = append([]string(nil), t.spies...)
+	}
+	if int(t.mission) <= len(t.requirements) {
+		requirement := t.requirements[t.mission-1]
+		view.TeamSize = requirement.NbPeopleOnMission
+		view.FailsToFail = requirement.NbFailuresRequiredToFail
+	}
+	return view
+}
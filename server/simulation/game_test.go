@@ -0,0 +1,75 @@
+package simulation
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/damien-springuel/bomb-canary/server/gamerules"
+	. "github.com/onsi/gomega"
+)
+
+func fivePlayerSeats(strategy Strategy) []seat {
+	return []seat{
+		{name: "Alice", strategy: strategy},
+		{name: "Bob", strategy: strategy},
+		{name: "Charlie", strategy: strategy},
+		{name: "Dan", strategy: strategy},
+		{name: "Edith", strategy: strategy},
+	}
+}
+
+func Test_SeededAllegianceGenerator_IsDeterministic(t *testing.T) {
+	first := NewSeededAllegianceGenerator(42).Generate(7, 3)
+	second := NewSeededAllegianceGenerator(42).Generate(7, 3)
+
+	g := NewWithT(t)
+	g.Expect(first).To(Equal(second))
+
+	nbSpies := 0
+	for _, allegiance := range first {
+		if allegiance == gamerules.Spy {
+			nbSpies += 1
+		}
+	}
+	g.Expect(nbSpies).To(Equal(3))
+}
+
+func Test_PlayGame_TrustingSpiesAlwaysWin(t *testing.T) {
+	result, err := playGame(fivePlayerSeats(trustingStrategy{}), 1)
+
+	g := NewWithT(t)
+	g.Expect(err).To(BeNil())
+	g.Expect(result.nbPlayers).To(Equal(5))
+	g.Expect(result.winner).To(Equal(gamerules.Spy))
+	g.Expect(result.allegiances).To(HaveLen(5))
+	g.Expect(result.strategies).To(Equal(map[string]string{
+		"Alice":   "trusting",
+		"Bob":     "trusting",
+		"Charlie": "trusting",
+		"Dan":     "trusting",
+		"Edith":   "trusting",
+	}))
+}
+
+func Test_PlayGame_SameSeedSameResult(t *testing.T) {
+	first, _ := playGame(fivePlayerSeats(randomStrategy{}), 99)
+	second, _ := playGame(fivePlayerSeats(randomStrategy{}), 99)
+
+	g := NewWithT(t)
+	g.Expect(first).To(Equal(second))
+}
+
+type stallingStrategy struct {
+	trustingStrategy
+}
+
+func (s stallingStrategy) SelectTeam(view View, random *rand.Rand) []string {
+	return nil
+}
+
+func Test_PlayGame_ErrorsIfGameStalls(t *testing.T) {
+	_, err := playGame(fivePlayerSeats(stallingStrategy{}), 1)
+
+	g := NewWithT(t)
+	g.Expect(err).To(Equal(errGameStalled))
+}
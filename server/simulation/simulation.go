@@ -0,0 +1,217 @@
+package simulation
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"sort"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/damien-springuel/bomb-canary/server/gamerules"
+)
+
+var (
+	errNoStrategies   = errors.New("at least one strategy is required")
+	errNoPlayerCounts = errors.New("at least one player count is required")
+)
+
+type Config struct {
+	NbGames      int
+	PlayerCounts []int
+	Strategies   []Strategy
+	Seed         int64
+	Workers      int
+}
+
+type Record struct {
+	Games           int
+	Wins            int
+	ResistanceGames int
+	ResistanceWins  int
+	SpyGames        int
+	SpyWins         int
+}
+
+func winRate(wins, games int) float64 {
+	if games == 0 {
+		return 0
+	}
+	return float64(wins) / float64(games)
+}
+
+func (r *Record) add(allegiance gamerules.Allegiance, won bool) {
+	r.Games += 1
+	if allegiance == gamerules.Spy {
+		r.SpyGames += 1
+	} else {
+		r.ResistanceGames += 1
+	}
+	if won {
+		r.Wins += 1
+		if allegiance == gamerules.Spy {
+			r.SpyWins += 1
+		} else {
+			r.ResistanceWins += 1
+		}
+	}
+}
+
+type CountRecord struct {
+	Games          int
+	ResistanceWins int
+	SpyWins        int
+}
+
+type Results struct {
+	Games         int
+	StalledGames  int
+	ByStrategy    map[string]*Record
+	ByPlayerCount map[int]*CountRecord
+}
+
+func newResults() Results {
+	return Results{
+		ByStrategy:    make(map[string]*Record),
+		ByPlayerCount: make(map[int]*CountRecord),
+	}
+}
+
+func (r *Results) add(result gameResult) {
+	r.Games += 1
+
+	count, exists := r.ByPlayerCount[result.nbPlayers]
+	if !exists {
+		count = &CountRecord{}
+		r.ByPlayerCount[result.nbPlayers] = count
+	}
+	count.Games += 1
+	if result.winner == gamerules.Spy {
+		count.SpyWins += 1
+	} else {
+		count.ResistanceWins += 1
+	}
+
+	for name, strategyName := range result.strategies {
+		record, exists := r.ByStrategy[strategyName]
+		if !exists {
+			record = &Record{}
+			r.ByStrategy[strategyName] = record
+		}
+		allegiance := result.allegiances[name]
+		record.add(allegiance, allegiance == result.winner)
+	}
+}
+
+func Run(config Config) (Results, error) {
+	if len(config.Strategies) == 0 {
+		return Results{}, errNoStrategies
+	}
+	if len(config.PlayerCounts) == 0 {
+		return Results{}, errNoPlayerCounts
+	}
+	for _, count := range config.PlayerCounts {
+		if count < 5 || count > 10 {
+			return Results{}, fmt.Errorf("invalid player count %d, must be between 5 and 10", count)
+		}
+	}
+
+	workers := config.Workers
+	if workers < 1 {
+		workers = 1
+	}
+
+	gameIndexes := make(chan int)
+	gameResults := make(chan *gameResult)
+	wg := &sync.WaitGroup{}
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range gameIndexes {
+				result, err := playGame(config.seatsFor(i), config.Seed+int64(i))
+				if err != nil {
+					gameResults <- nil
+				} else {
+					gameResults <- &result
+				}
+			}
+		}()
+	}
+
+	go func() {
+		for i := 0; i < config.NbGames; i++ {
+			gameIndexes <- i
+		}
+		close(gameIndexes)
+		wg.Wait()
+		close(gameResults)
+	}()
+
+	results := newResults()
+	for result := range gameResults {
+		if result == nil {
+			results.StalledGames += 1
+			continue
+		}
+		results.add(*result)
+	}
+	return results, nil
+}
+
+func (c Config) seatsFor(gameIndex int) []seat {
+	random := rand.New(rand.NewSource(c.Seed + int64(gameIndex)))
+	nbPlayers := c.PlayerCounts[gameIndex%len(c.PlayerCounts)]
+
+	seats := make([]seat, nbPlayers)
+	for i := range seats {
+		seats[i] = seat{
+			name:     fmt.Sprintf("Bot%d", i+1),
+			strategy: c.Strategies[random.Intn(len(c.Strategies))],
+		}
+	}
+	return seats
+}
+
+func (r Results) WriteTables(w io.Writer) {
+	fmt.Fprintf(w, "games played: %d, stalled: %d\n\n", r.Games, r.StalledGames)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "strategy\tseats\twin rate\tresistance win rate\tspy win rate\t")
+	strategyNames := make([]string, 0, len(r.ByStrategy))
+	for name := range r.ByStrategy {
+		strategyNames = append(strategyNames, name)
+	}
+	sort.Strings(strategyNames)
+	for _, name := range strategyNames {
+		record := r.ByStrategy[name]
+		fmt.Fprintf(tw, "%s\t%d\t%.1f%%\t%.1f%%\t%.1f%%\t\n",
+			name,
+			record.Games,
+			100*winRate(record.Wins, record.Games),
+			100*winRate(record.ResistanceWins, record.ResistanceGames),
+			100*winRate(record.SpyWins, record.SpyGames),
+		)
+	}
+	tw.Flush()
+	fmt.Fprintln(w)
+
+	tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "players\tgames\tresistance win rate\tspy win rate\t")
+	counts := make([]int, 0, len(r.ByPlayerCount))
+	for count := range r.ByPlayerCount {
+		counts = append(counts, count)
+	}
+	sort.Ints(counts)
+	for _, count := range counts {
+		record := r.ByPlayerCount[count]
+		fmt.Fprintf(tw, "%d\t%d\t%.1f%%\t%.1f%%\t\n",
+			count,
+			record.Games,
+			100*winRate(record.ResistanceWins, record.Games),
+			100*winRate(record.SpyWins, record.Games),
+		)
+	}
+	tw.Flush()
+}
@@ -0,0 +1,86 @@
+package simulation
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/damien-springuel/bomb-canary/server/gamerules"
+	. "github.com/onsi/gomega"
+)
+
+func Test_Run(t *testing.T) {
+	results, err := Run(Config{
+		NbGames:      60,
+		PlayerCounts: []int{5, 10},
+		Strategies:   []Strategy{randomStrategy{}, cautiousStrategy{}},
+		Seed:         3,
+		Workers:      4,
+	})
+
+	g := NewWithT(t)
+	g.Expect(err).To(BeNil())
+	g.Expect(results.Games + results.StalledGames).To(Equal(60))
+	g.Expect(results.ByPlayerCount).To(HaveLen(2))
+	g.Expect(results.ByPlayerCount[5].Games + results.ByPlayerCount[10].Games).To(Equal(results.Games))
+	g.Expect(results.ByStrategy).To(HaveKey("random"))
+	g.Expect(results.ByStrategy).To(HaveKey("cautious"))
+
+	seats := 0
+	for _, record := range results.ByStrategy {
+		seats += record.Games
+		g.Expect(record.ResistanceGames + record.SpyGames).To(Equal(record.Games))
+		g.Expect(record.ResistanceWins + record.SpyWins).To(Equal(record.Wins))
+	}
+	g.Expect(seats).To(Equal(results.ByPlayerCount[5].Games*5 + results.ByPlayerCount[10].Games*10))
+}
+
+func Test_Run_IsDeterministicRegardlessOfWorkers(t *testing.T) {
+	config := Config{
+		NbGames:      40,
+		PlayerCounts: []int{6, 7},
+		Strategies:   []Strategy{randomStrategy{}, trustingStrategy{}},
+		Seed:         11,
+		Workers:      1,
+	}
+	sequential, _ := Run(config)
+	config.Workers = 8
+	parallel, _ := Run(config)
+
+	g := NewWithT(t)
+	g.Expect(parallel).To(Equal(sequential))
+}
+
+func Test_Run_ErrorsOnInvalidConfig(t *testing.T) {
+	g := NewWithT(t)
+
+	_, err := Run(Config{NbGames: 1, PlayerCounts: []int{5}})
+	g.Expect(err).To(Equal(errNoStrategies))
+
+	_, err = Run(Config{NbGames: 1, Strategies: []Strategy{randomStrategy{}}})
+	g.Expect(err).To(Equal(errNoPlayerCounts))
+
+	_, err = Run(Config{NbGames: 1, PlayerCounts: []int{4}, Strategies: []Strategy{randomStrategy{}}})
+	g.Expect(err).To(MatchError("invalid player count 4, must be between 5 and 10"))
+}
+
+func Test_WriteTables(t *testing.T) {
+	results := newResults()
+	results.add(gameResult{
+		nbPlayers:   5,
+		winner:      "spy",
+		allegiances: map[string]gamerules.Allegiance{"a": "spy", "b": "spy", "c": "resistance", "d": "resistance", "e": "resistance"},
+		strategies:  map[string]string{"a": "random", "b": "random", "c": "random", "d": "random", "e": "random"},
+	})
+
+	out := &bytes.Buffer{}
+	results.WriteTables(out)
+
+	g := NewWithT(t)
+	g.Expect(out.String()).To(Equal(
+		"games played: 1, stalled: 0\n\n" +
+			"strategy  seats  win rate  resistance win rate  spy win rate  \n" +
+			"random    5      40.0%     0.0%                 100.0%        \n\n" +
+			"players  games  resistance win rate  spy win rate  \n" +
+			"5        1      0.0%                 100.0%        \n",
+	))
+}
@@ -0,0 +1,274 @@
+package simulation
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+
+	"github.com/damien-springuel/bomb-canary/server/analysis"
+	"github.com/damien-springuel/bomb-canary/server/gamerules"
+)
+
+type MissionSummary struct {
+	Team    []string
+	NbFails int
+	Success bool
+}
+
+type View struct {
+	Me           string
+	Allegiance   gamerules.Allegiance
+	Spies        []string
+	Players      []string
+	NbSpies      int
+	Leader       string
+	Mission      gamerules.Mission
+	TeamSize     int
+	FailsToFail  int
+	Team         []string
+	VoteFailures int
+	Missions     []MissionSummary
+}
+
+func (v View) isSpy(name string) bool {
+	return contains(v.Spies, name)
+}
+
+func (v View) teamHasSpy() bool {
+	for _, member := range v.Team {
+		if v.isSpy(member) {
+			return true
+		}
+	}
+	return false
+}
+
+func (v View) suspects() map[string]int {
+	suspects := make(map[string]int)
+	for _, mission := range v.Missions {
+		if mission.NbFails == 0 {
+			continue
+		}
+		for _, member := range mission.Team {
+			if member != v.Me {
+				suspects[member] += mission.NbFails
+			}
+		}
+	}
+	return suspects
+}
+
+type Strategy interface {
+	Name() string
+	SelectTeam(view View, random *rand.Rand) []string
+	VoteOnTeam(view View, random *rand.Rand) bool
+	WorkOnMission(view View, random *rand.Rand) bool
+}
+
+var strategies = map[string]Strategy{
+	randomStrategy{}.Name():    randomStrategy{},
+	trustingStrategy{}.Name():  trustingStrategy{},
+	cautiousStrategy{}.Name():  cautiousStrategy{},
+	deductiveStrategy{}.Name(): deductiveStrategy{},
+}
+
+func StrategyNames() []string {
+	names := make([]string, 0, len(strategies))
+	for name := range strategies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func StrategyByName(name string) (Strategy, error) {
+	strategy, exists := strategies[name]
+	if !exists {
+		return nil, fmt.Errorf("unknown strategy %q, available strategies are %v", name, StrategyNames())
+	}
+	return strategy, nil
+}
+
+type randomStrategy struct{}
+
+func (r randomStrategy) Name() string {
+	return "random"
+}
+
+func (r randomStrategy) SelectTeam(view View, random *rand.Rand) []string {
+	return pickRandomly(view.Players, view.TeamSize, random)
+}
+
+func (r randomStrategy) VoteOnTeam(view View, random *rand.Rand) bool {
+	return random.Intn(2) == 0
+}
+
+func (r randomStrategy) WorkOnMission(view View, random *rand.Rand) bool {
+	if view.Allegiance == gamerules.Resistance {
+		return true
+	}
+	return random.Intn(2) == 0
+}
+
+type trustingStrategy struct{}
+
+func (t trustingStrategy) Name() string {
+	return "trusting"
+}
+
+func (t trustingStrategy) SelectTeam(view View, random *rand.Rand) []string {
+	others := without(view.Players, view.Me)
+	return append([]string{view.Me}, pickRandomly(others, view.TeamSize-1, random)...)
+}
+
+func (t trustingStrategy) VoteOnTeam(view View, random *rand.Rand) bool {
+	return true
+}
+
+func (t trustingStrategy) WorkOnMission(view View, random *rand.Rand) bool {
+	return view.Allegiance == gamerules.Resistance
+}
+
+type cautiousStrategy struct{}
+
+func (c cautiousStrategy) Name() string {
+	return "cautious"
+}
+
+func (c cautiousStrategy) SelectTeam(view View, random *rand.Rand) []string {
+	candidates := without(view.Players, view.Me)
+	if view.Allegiance == gamerules.Spy {
+		team := append([]string{view.Me}, pickRandomly(withoutAll(candidates, view.Spies), view.TeamSize-1, random)...)
+		return append(team, pickRandomly(withoutAll(candidates, team), view.TeamSize-len(team), random)...)
+	}
+
+	suspects := view.suspects()
+	random.Shuffle(len(candidates), func(i, j int) { candidates[i], candidates[j] = candidates[j], candidates[i] })
+	sort.SliceStable(candidates, func(i, j int) bool { return suspects[candidates[i]] < suspects[candidates[j]] })
+	return append([]string{view.Me}, candidates[:view.TeamSize-1]...)
+}
+
+func (c cautiousStrategy) VoteOnTeam(view View, random *rand.Rand) bool {
+	if view.VoteFailures == 4 {
+		return view.Allegiance == gamerules.Resistance
+	}
+
+	if view.Allegiance == gamerules.Spy {
+		return view.teamHasSpy()
+	}
+
+	suspects := view.suspects()
+	for _, member := range view.Team {
+		if suspects[member] > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (c cautiousStrategy) WorkOnMission(view View, random *rand.Rand) bool {
+	if view.Allegiance == gamerules.Resistance {
+		return true
+	}
+
+	spiesOnTeam := []string{}
+	for _, member := range view.Team {
+		if view.isSpy(member) {
+			spiesOnTeam = append(spiesOnTeam, member)
+		}
+	}
+	sort.Strings(spiesOnTeam)
+	for i, spy := range spiesOnTeam {
+		if spy == view.Me {
+			return i >= view.FailsToFail
+		}
+	}
+	return false
+}
+
+type deductiveStrategy struct{}
+
+func (d deductiveStrategy) Name() string {
+	return "deductive"
+}
+
+func (d deductiveStrategy) spyProbabilities(view View) (map[string]float64, bool) {
+	missions := make([]analysis.Mission, len(view.Missions))
+	for i, mission := range view.Missions {
+		missions[i] = analysis.Mission{Team: mission.Team, NbFails: mission.NbFails}
+	}
+	result, err := analysis.Compute(view.Players, view.NbSpies, missions, []string{view.Me})
+	if err != nil {
+		return nil, false
+	}
+	return result.SpyProbabilities, true
+}
+
+func (d deductiveStrategy) SelectTeam(view View, random *rand.Rand) []string {
+	probabilities, ok := d.spyProbabilities(view)
+	if view.Allegiance == gamerules.Spy || !ok {
+		return cautiousStrategy{}.SelectTeam(view, random)
+	}
+
+	candidates := without(view.Players, view.Me)
+	random.Shuffle(len(candidates), func(i, j int) { candidates[i], candidates[j] = candidates[j], candidates[i] })
+	sort.SliceStable(candidates, func(i, j int) bool { return probabilities[candidates[i]] < probabilities[candidates[j]] })
+	return append([]string{view.Me}, candidates[:view.TeamSize-1]...)
+}
+
+func (d deductiveStrategy) VoteOnTeam(view View, random *rand.Rand) bool {
+	probabilities, ok := d.spyProbabilities(view)
+	if view.Allegiance == gamerules.Spy || view.VoteFailures == 4 || !ok {
+		return cautiousStrategy{}.VoteOnTeam(view, random)
+	}
+
+	sorted := make([]float64, 0, len(probabilities))
+	for _, probability := range probabilities {
+		sorted = append(sorted, probability)
+	}
+	sort.Float64s(sorted)
+	threshold := sorted[view.TeamSize-1] + 0.01
+	for _, member := range view.Team {
+		if probabilities[member] > threshold {
+			return false
+		}
+	}
+	return true
+}
+
+func (d deductiveStrategy) WorkOnMission(view View, random *rand.Rand) bool {
+	return cautiousStrategy{}.WorkOnMission(view, random)
+}
+
+func pickRandomly(names []string, count int, random *rand.Rand) []string {
+	if count > len(names) {
+		count = len(names)
+	}
+	picked := make([]string, len(names))
+	copy(picked, names)
+	random.Shuffle(len(picked), func(i, j int) { picked[i], picked[j] = picked[j], picked[i] })
+	return picked[:count]
+}
+
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func without(names []string, name string) []string {
+	return withoutAll(names, []string{name})
+}
+
+func withoutAll(names []string, excluded []string) []string {
+	remaining := make([]string, 0, len(names))
+	for _, n := range names {
+		if !contains(excluded, n) {
+			remaining = append(remaining, n)
+		}
+	}
+	return remaining
+}
@@ -0,0 +1,94 @@
+package simulation
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/damien-springuel/bomb-canary/server/gamerules"
+	. "github.com/onsi/gomega"
+)
+
+func Test_StrategyByName(t *testing.T) {
+	g := NewWithT(t)
+
+	strategy, err := StrategyByName("cautious")
+	g.Expect(err).To(BeNil())
+	g.Expect(strategy).To(Equal(cautiousStrategy{}))
+
+	_, err = StrategyByName("unknown")
+	g.Expect(err).To(MatchError(`unknown strategy "unknown", available strategies are [cautious deductive random trusting]`))
+}
+
+func Test_TrustingStrategy_SelectsItselfFirst(t *testing.T) {
+	view := View{Me: "Bob", Players: []string{"Alice", "Bob", "Charlie", "Dan", "Edith"}, TeamSize: 3}
+	team := trustingStrategy{}.SelectTeam(view, rand.New(rand.NewSource(1)))
+
+	g := NewWithT(t)
+	g.Expect(team).To(HaveLen(3))
+	g.Expect(team[0]).To(Equal("Bob"))
+	g.Expect(team[1:]).ToNot(ContainElement("Bob"))
+}
+
+func Test_CautiousStrategy_ResistanceAvoidsSuspects(t *testing.T) {
+	view := View{
+		Me:         "Alice",
+		Allegiance: gamerules.Resistance,
+		Players:    []string{"Alice", "Bob", "Charlie", "Dan", "Edith"},
+		TeamSize:   3,
+		Missions:   []MissionSummary{{Team: []string{"Alice", "Bob"}, NbFails: 1}},
+	}
+	random := rand.New(rand.NewSource(1))
+
+	g := NewWithT(t)
+	g.Expect(cautiousStrategy{}.SelectTeam(view, random)).ToNot(ContainElement("Bob"))
+
+	view.Team = []string{"Alice", "Bob", "Charlie"}
+	g.Expect(cautiousStrategy{}.VoteOnTeam(view, random)).To(BeFalse())
+
+	view.Team = []string{"Alice", "Dan", "Charlie"}
+	g.Expect(cautiousStrategy{}.VoteOnTeam(view, random)).To(BeTrue())
+
+	view.Team = []string{"Alice", "Bob", "Charlie"}
+	view.VoteFailures = 4
+	g.Expect(cautiousStrategy{}.VoteOnTeam(view, random)).To(BeTrue())
+}
+
+func Test_CautiousStrategy_SpiesOnlyPlayTheFailsNeeded(t *testing.T) {
+	view := View{
+		Allegiance:  gamerules.Spy,
+		Spies:       []string{"Alice", "Bob"},
+		Team:        []string{"Bob", "Alice", "Charlie"},
+		FailsToFail: 1,
+	}
+	random := rand.New(rand.NewSource(1))
+
+	g := NewWithT(t)
+	view.Me = "Alice"
+	g.Expect(cautiousStrategy{}.WorkOnMission(view, random)).To(BeFalse())
+	view.Me = "Bob"
+	g.Expect(cautiousStrategy{}.WorkOnMission(view, random)).To(BeTrue())
+
+	view.FailsToFail = 2
+	g.Expect(cautiousStrategy{}.WorkOnMission(view, random)).To(BeFalse())
+}
+
+func Test_DeductiveStrategy_AvoidsDeducedSpies(t *testing.T) {
+	view := View{
+		Me:         "Charlie",
+		Allegiance: gamerules.Resistance,
+		Players:    []string{"Alice", "Bob", "Charlie", "Dan", "Edith"},
+		NbSpies:    2,
+		TeamSize:   3,
+		Missions:   []MissionSummary{{Team: []string{"Alice", "Bob"}, NbFails: 2}},
+	}
+	random := rand.New(rand.NewSource(1))
+
+	g := NewWithT(t)
+	g.Expect(deductiveStrategy{}.SelectTeam(view, random)).To(ConsistOf("Charlie", "Dan", "Edith"))
+
+	view.Team = []string{"Alice", "Charlie", "Dan"}
+	g.Expect(deductiveStrategy{}.VoteOnTeam(view, random)).To(BeFalse())
+
+	view.Team = []string{"Edith", "Charlie", "Dan"}
+	g.Expect(deductiveStrategy{}.VoteOnTeam(view, random)).To(BeTrue())
+}
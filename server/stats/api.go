@@ -0,0 +1,37 @@
+package stats
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+type statsGetter interface {
+	Player(name string) (PlayerStats, error)
+	Leaderboard() []PlayerStats
+}
+
+type statsServer struct {
+	statsGetter statsGetter
+}
+
+func Register(engine *gin.Engine, statsGetter statsGetter) {
+	statsServer := statsServer{
+		statsGetter: statsGetter,
+	}
+
+	engine.GET("/stats/players/:name", statsServer.player)
+	engine.GET("/stats/leaderboard", statsServer.leaderboard)
+}
+
+func (s statsServer) player(c *gin.Context) {
+	stats, err := s.statsGetter.Player(c.Param("name"))
+	if err != nil {
+		c.AbortWithStatusJSON(404, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(200, stats)
+}
+
+func (s statsServer) leaderboard(c *gin.Context) {
+	c.JSON(200, gin.H{"players": s.statsGetter.Leaderboard()})
+}
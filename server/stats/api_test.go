@@ -0,0 +1,66 @@
+package stats
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/gomega"
+)
+
+type mockStatsGetter struct {
+	receivedName string
+	playerError  error
+}
+
+func (m *mockStatsGetter) Player(name string) (PlayerStats, error) {
+	m.receivedName = name
+	return PlayerStats{Name: name, GamesPlayed: 2, Wins: 1, WinRate: 0.5}, m.playerError
+}
+
+func (m *mockStatsGetter) Leaderboard() []PlayerStats {
+	return []PlayerStats{{Name: "Alice", GamesPlayed: 1, Wins: 1, WinRate: 1}}
+}
+
+func makeCall(req *http.Request, statsGetter *mockStatsGetter) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	ginEngine := gin.New()
+	Register(ginEngine, statsGetter)
+
+	w := httptest.NewRecorder()
+	ginEngine.ServeHTTP(w, req)
+	return w
+}
+
+func Test_Player(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/stats/players/Alice", nil)
+	statsGetter := &mockStatsGetter{}
+	w := makeCall(req, statsGetter)
+
+	g := NewWithT(t)
+	g.Expect(w.Code).To(Equal(200))
+	g.Expect(w.Body.String()).To(Equal(`{"name":"Alice","gamesPlayed":2,"wins":1,"winRate":0.5,` +
+		`"asResistance":{"games":0,"wins":0,"winRate":0},"asSpy":{"games":0,"wins":0,"winRate":0},` +
+		`"missionsOnTeam":0,"missionsSucceeded":0,"missionSuccessRate":0,` +
+		`"votesOnSpyTeams":0,"approvalsOfSpyTeams":0,"spyTeamApprovalRate":0,"timesLeader":0}`))
+	g.Expect(statsGetter.receivedName).To(Equal("Alice"))
+}
+
+func Test_Player_Returns404IfUnknown(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/stats/players/Alice", nil)
+	w := makeCall(req, &mockStatsGetter{playerError: errUnknownPlayer})
+
+	g := NewWithT(t)
+	g.Expect(w.Code).To(Equal(404))
+	g.Expect(w.Body.String()).To(Equal(`{"error":"player hasn't played any game"}`))
+}
+
+func Test_Leaderboard_Endpoint(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/stats/leaderboard", nil)
+	w := makeCall(req, &mockStatsGetter{})
+
+	g := NewWithT(t)
+	g.Expect(w.Code).To(Equal(200))
+	g.Expect(w.Body.String()).To(HavePrefix(`{"players":[{"name":"Alice","gamesPlayed":1,"wins":1,"winRate":1,`))
+}
@@ -0,0 +1,171 @@
+package stats
+
+import (
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/damien-springuel/bomb-canary/server/messagebus"
+)
+
+var errUnknownPlayer = errors.New("player hasn't played any game")
+
+type AllegianceStats struct {
+	Games   int     `json:"games"`
+	Wins    int     `json:"wins"`
+	WinRate float64 `json:"winRate"`
+}
+
+type PlayerStats struct {
+	Name                string          `json:"name"`
+	GamesPlayed         int             `json:"gamesPlayed"`
+	Wins                int             `json:"wins"`
+	WinRate             float64         `json:"winRate"`
+	AsResistance        AllegianceStats `json:"asResistance"`
+	AsSpy               AllegianceStats `json:"asSpy"`
+	MissionsOnTeam      int             `json:"missionsOnTeam"`
+	MissionsSucceeded   int             `json:"missionsSucceeded"`
+	MissionSuccessRate  float64         `json:"missionSuccessRate"`
+	VotesOnSpyTeams     int             `json:"votesOnSpyTeams"`
+	ApprovalsOfSpyTeams int             `json:"approvalsOfSpyTeams"`
+	SpyTeamApprovalRate float64         `json:"spyTeamApprovalRate"`
+	TimesLeader         int             `json:"timesLeader"`
+}
+
+type tracker struct {
+	mut     *sync.RWMutex
+	players map[string]*PlayerStats
+}
+
+func NewTracker() *tracker {
+	return &tracker{
+		mut:     &sync.RWMutex{},
+		players: make(map[string]*PlayerStats),
+	}
+}
+
+func (t *tracker) Load(summaries []messagebus.GameSummarized) {
+	for _, summary := range summaries {
+		t.Consume(summary)
+	}
+}
+
+func (t *tracker) Consume(m messagebus.Message) {
+	summary, ok := m.(messagebus.GameSummarized)
+	if !ok {
+		return
+	}
+
+	t.mut.Lock()
+	defer t.mut.Unlock()
+
+	for name, allegiance := range summary.AllegianceByPlayer {
+		stats := t.statsOf(name)
+		stats.GamesPlayed += 1
+		won := allegiance == summary.Winner
+		if won {
+			stats.Wins += 1
+		}
+
+		allegianceStats := &stats.AsResistance
+		if allegiance == messagebus.Spy {
+			allegianceStats = &stats.AsSpy
+		}
+		allegianceStats.Games += 1
+		if won {
+			allegianceStats.Wins += 1
+		}
+	}
+
+	for _, proposal := range summary.TeamProposals {
+		t.statsOf(proposal.Leader).TimesLeader += 1
+
+		if !hasSpy(proposal.Team, summary.AllegianceByPlayer) {
+			continue
+		}
+		for name, approved := range proposal.Votes {
+			stats := t.statsOf(name)
+			stats.VotesOnSpyTeams += 1
+			if approved {
+				stats.ApprovalsOfSpyTeams += 1
+			}
+		}
+	}
+
+	for _, mission := range summary.Missions {
+		for _, name := range mission.Team {
+			stats := t.statsOf(name)
+			stats.MissionsOnTeam += 1
+			if mission.Success {
+				stats.MissionsSucceeded += 1
+			}
+		}
+	}
+}
+
+func (t *tracker) statsOf(name string) *PlayerStats {
+	stats, exists := t.players[name]
+	if !exists {
+		stats = &PlayerStats{Name: name}
+		t.players[name] = stats
+	}
+	return stats
+}
+
+func hasSpy(team []string, allegianceByPlayer map[string]messagebus.Allegiance) bool {
+	for _, name := range team {
+		if allegianceByPlayer[name] == messagebus.Spy {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *tracker) Player(name string) (PlayerStats, error) {
+	t.mut.RLock()
+	defer t.mut.RUnlock()
+
+	stats, exists := t.players[name]
+	if !exists {
+		return PlayerStats{}, errUnknownPlayer
+	}
+	return withRates(*stats), nil
+}
+
+func (t *tracker) Leaderboard() []PlayerStats {
+	t.mut.RLock()
+	defer t.mut.RUnlock()
+
+	leaderboard := make([]PlayerStats, 0, len(t.players))
+	for _, stats := range t.players {
+		leaderboard = append(leaderboard, withRates(*stats))
+	}
+
+	sort.Slice(leaderboard, func(i, j int) bool {
+		a, b := leaderboard[i], leaderboard[j]
+		if a.WinRate != b.WinRate {
+			return a.WinRate > b.WinRate
+		}
+		if a.GamesPlayed != b.GamesPlayed {
+			return a.GamesPlayed > b.GamesPlayed
+		}
+		return a.Name < b.Name
+	})
+	return leaderboard
+}
+
+func withRates(stats PlayerStats) PlayerStats {
+	stats.WinRate = rate(stats.Wins, stats.GamesPlayed)
+	stats.AsResistance.WinRate = rate(stats.AsResistance.Wins, stats.AsResistance.Games)
+	stats.AsSpy.WinRate = rate(stats.AsSpy.Wins, stats.AsSpy.Games)
+	stats.MissionSuccessRate = rate(stats.MissionsSucceeded, stats.MissionsOnTeam)
+	stats.SpyTeamApprovalRate = rate(stats.ApprovalsOfSpyTeams, stats.VotesOnSpyTeams)
+	return stats
+}
+
+func rate(count int, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return float64(count) / float64(total)
+}
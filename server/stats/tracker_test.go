@@ -0,0 +1,104 @@
+package stats
+
+import (
+	"testing"
+
+	"github.com/damien-springuel/bomb-canary/server/messagebus"
+	. "github.com/onsi/gomega"
+)
+
+func firstGame() messagebus.GameSummarized {
+	return messagebus.GameSummarized{
+		Winner: messagebus.Spy,
+		AllegianceByPlayer: map[string]messagebus.Allegiance{
+			"Alice":   messagebus.Spy,
+			"Bob":     messagebus.Resistance,
+			"Charlie": messagebus.Resistance,
+		},
+		TeamProposals: []messagebus.TeamProposal{
+			{Mission: 1, Leader: "Alice", Team: []string{"Alice", "Bob"}, Votes: map[string]bool{"Alice": true, "Bob": true, "Charlie": false}, Approved: true},
+			{Mission: 2, Leader: "Bob", Team: []string{"Bob", "Charlie"}, Votes: map[string]bool{"Alice": false, "Bob": true, "Charlie": true}, Approved: true},
+		},
+		Missions: []messagebus.MissionSummary{
+			{Mission: 1, Team: []string{"Alice", "Bob"}, Success: false, NbFails: 1},
+			{Mission: 2, Team: []string{"Bob", "Charlie"}, Success: true},
+		},
+	}
+}
+
+func secondGame() messagebus.GameSummarized {
+	return messagebus.GameSummarized{
+		Winner: messagebus.Resistance,
+		AllegianceByPlayer: map[string]messagebus.Allegiance{
+			"Alice": messagebus.Resistance,
+			"Bob":   messagebus.Spy,
+		},
+		TeamProposals: []messagebus.TeamProposal{
+			{Mission: 1, Leader: "Alice", Team: []string{"Alice", "Bob"}, Votes: map[string]bool{"Alice": false, "Bob": true}, Approved: false},
+		},
+	}
+}
+
+func Test_PlayerStats(t *testing.T) {
+	tracker := NewTracker()
+	tracker.Consume(firstGame())
+	tracker.Consume(secondGame())
+
+	g := NewWithT(t)
+	alice, err := tracker.Player("Alice")
+	g.Expect(err).To(BeNil())
+	g.Expect(alice).To(Equal(PlayerStats{
+		Name:                "Alice",
+		GamesPlayed:         2,
+		Wins:                2,
+		WinRate:             1,
+		AsResistance:        AllegianceStats{Games: 1, Wins: 1, WinRate: 1},
+		AsSpy:               AllegianceStats{Games: 1, Wins: 1, WinRate: 1},
+		MissionsOnTeam:      1,
+		MissionsSucceeded:   0,
+		MissionSuccessRate:  0,
+		VotesOnSpyTeams:     2,
+		ApprovalsOfSpyTeams: 1,
+		SpyTeamApprovalRate: 0.5,
+		TimesLeader:         2,
+	}))
+
+	bob, err := tracker.Player("Bob")
+	g.Expect(err).To(BeNil())
+	g.Expect(bob).To(Equal(PlayerStats{
+		Name:                "Bob",
+		GamesPlayed:         2,
+		Wins:                0,
+		WinRate:             0,
+		AsResistance:        AllegianceStats{Games: 1},
+		AsSpy:               AllegianceStats{Games: 1},
+		MissionsOnTeam:      2,
+		MissionsSucceeded:   1,
+		MissionSuccessRate:  0.5,
+		VotesOnSpyTeams:     2,
+		ApprovalsOfSpyTeams: 2,
+		SpyTeamApprovalRate: 1,
+		TimesLeader:         1,
+	}))
+}
+
+func Test_PlayerStats_UnknownPlayer(t *testing.T) {
+	tracker := NewTracker()
+	tracker.Consume(messagebus.GameEnded{Winner: messagebus.Spy})
+
+	g := NewWithT(t)
+	_, err := tracker.Player("Alice")
+	g.Expect(err).To(Equal(errUnknownPlayer))
+}
+
+func Test_Leaderboard(t *testing.T) {
+	tracker := NewTracker()
+	tracker.Load([]messagebus.GameSummarized{firstGame(), secondGame()})
+
+	leaderboard := tracker.Leaderboard()
+
+	g := NewWithT(t)
+	g.Expect(leaderboard).To(HaveLen(3))
+	g.Expect([]string{leaderboard[0].Name, leaderboard[1].Name, leaderboard[2].Name}).To(Equal([]string{"Alice", "Bob", "Charlie"}))
+	g.Expect(leaderboard[2].WinRate).To(Equal(0.0))
+}
@@ -0,0 +1,102 @@
+package summary
+
+import (
+	"sort"
+
+	"github.com/damien-springuel/bomb-canary/server/messagebus"
+)
+
+type messageDispatcher interface {
+	Dispatch(m messagebus.Message)
+}
+
+type recorder struct {
+	messageDispatcher  messageDispatcher
+	revealMissionCards bool
+	allegiances        map[string]messagebus.Allegiance
+	leader             string
+	team               []string
+	failedBy           []string
+	proposals          []messagebus.TeamProposal
+	missions           []messagebus.MissionSummary
+}
+
+func NewRecorder(messageDispatcher messageDispatcher, revealMissionCards bool) *recorder {
+	return &recorder{
+		messageDispatcher:  messageDispatcher,
+		revealMissionCards: revealMissionCards,
+	}
+}
+
+func (r *recorder) Consume(m messagebus.Message) {
+	switch m := m.(type) {
+	case messagebus.AllegianceRevealed:
+		r.allegiances = make(map[string]messagebus.Allegiance)
+		for name, allegiance := range m.AllegianceByPlayer {
+			r.allegiances[name] = allegiance
+		}
+		r.proposals = nil
+		r.missions = nil
+
+	case messagebus.LeaderStartedToSelectMembers:
+		r.leader = m.Leader
+		r.team = nil
+
+	case messagebus.LeaderSelectedMember:
+		r.team = append(r.team, m.SelectedMember)
+
+	case messagebus.LeaderDeselectedMember:
+		for i, member := range r.team {
+			if member == m.DeselectedMember {
+				r.team = append(r.team[:i:i], r.team[i+1:]...)
+				break
+			}
+		}
+
+	case messagebus.AllPlayerVotedOnTeam:
+		votes := make(map[string]bool)
+		for name, approved := range m.PlayerVotes {
+			votes[name] = approved
+		}
+		r.proposals = append(r.proposals, messagebus.TeamProposal{
+			Mission:  r.currentMission(),
+			Leader:   r.leader,
+			Team:     append([]string{}, r.team...),
+			Votes:    votes,
+			Approved: m.Approved,
+		})
+
+	case messagebus.MissionStarted:
+		r.failedBy = nil
+
+	case messagebus.PlayerWorkedOnMission:
+		if !m.Success {
+			r.failedBy = append(r.failedBy, m.Player)
+		}
+
+	case messagebus.MissionCompleted:
+		mission := messagebus.MissionSummary{
+			Mission: r.currentMission(),
+			Team:    append([]string{}, r.team...),
+			Success: m.Success,
+			NbFails: m.Outcomes[false],
+		}
+		if r.revealMissionCards {
+			mission.FailedBy = append([]string{}, r.failedBy...)
+			sort.Strings(mission.FailedBy)
+		}
+		r.missions = append(r.missions, mission)
+
+	case messagebus.GameEnded:
+		r.messageDispatcher.Dispatch(messagebus.GameSummarized{
+			Winner:             m.Winner,
+			AllegianceByPlayer: r.allegiances,
+			TeamProposals:      r.proposals,
+			Missions:           r.missions,
+		})
+	}
+}
+
+func (r *recorder) currentMission() int {
+	return len(r.missions) + 1
+}
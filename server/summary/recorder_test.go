@@ -0,0 +1,95 @@
+package summary
+
+import (
+	"testing"
+
+	"github.com/damien-springuel/bomb-canary/server/messagebus"
+	. "github.com/onsi/gomega"
+)
+
+type mockDispatcher struct {
+	receivedMessages []messagebus.Message
+}
+
+func (m *mockDispatcher) Dispatch(message messagebus.Message) {
+	m.receivedMessages = append(m.receivedMessages, message)
+}
+
+func allegiances() map[string]messagebus.Allegiance {
+	return map[string]messagebus.Allegiance{
+		"Alice":   messagebus.Spy,
+		"Bob":     messagebus.Resistance,
+		"Charlie": messagebus.Spy,
+		"Dan":     messagebus.Resistance,
+		"Edith":   messagebus.Resistance,
+	}
+}
+
+func playShortGame(r *recorder) {
+	r.Consume(messagebus.AllegianceRevealed{AllegianceByPlayer: allegiances()})
+
+	r.Consume(messagebus.LeaderStartedToSelectMembers{Leader: "Alice"})
+	r.Consume(messagebus.LeaderSelectedMember{SelectedMember: "Alice"})
+	r.Consume(messagebus.LeaderSelectedMember{SelectedMember: "Dan"})
+	r.Consume(messagebus.LeaderDeselectedMember{DeselectedMember: "Dan"})
+	r.Consume(messagebus.LeaderSelectedMember{SelectedMember: "Bob"})
+	r.Consume(messagebus.LeaderConfirmedSelection{})
+	r.Consume(messagebus.AllPlayerVotedOnTeam{Approved: false, VoteFailures: 1, PlayerVotes: map[string]bool{"Alice": true, "Bob": false}})
+
+	r.Consume(messagebus.LeaderStartedToSelectMembers{Leader: "Bob"})
+	r.Consume(messagebus.LeaderSelectedMember{SelectedMember: "Bob"})
+	r.Consume(messagebus.LeaderSelectedMember{SelectedMember: "Charlie"})
+	r.Consume(messagebus.LeaderConfirmedSelection{})
+	r.Consume(messagebus.AllPlayerVotedOnTeam{Approved: true, PlayerVotes: map[string]bool{"Alice": true, "Bob": true}})
+	r.Consume(messagebus.MissionStarted{})
+	r.Consume(messagebus.PlayerWorkedOnMission{Player: "Charlie", Success: false})
+	r.Consume(messagebus.PlayerWorkedOnMission{Player: "Bob", Success: true})
+	r.Consume(messagebus.MissionCompleted{Success: false, Outcomes: map[bool]int{true: 1, false: 1}})
+
+	r.Consume(messagebus.GameEnded{Winner: messagebus.Spy, Spies: []string{"Alice", "Charlie"}})
+}
+
+func Test_GameSummarizedWhenGameEnds(t *testing.T) {
+	dispatcher := &mockDispatcher{}
+	r := NewRecorder(dispatcher, false)
+	playShortGame(r)
+
+	g := NewWithT(t)
+	g.Expect(dispatcher.receivedMessages).To(Equal([]messagebus.Message{
+		messagebus.GameSummarized{
+			Winner:             messagebus.Spy,
+			AllegianceByPlayer: allegiances(),
+			TeamProposals: []messagebus.TeamProposal{
+				{Mission: 1, Leader: "Alice", Team: []string{"Alice", "Bob"}, Votes: map[string]bool{"Alice": true, "Bob": false}, Approved: false},
+				{Mission: 1, Leader: "Bob", Team: []string{"Bob", "Charlie"}, Votes: map[string]bool{"Alice": true, "Bob": true}, Approved: true},
+			},
+			Missions: []messagebus.MissionSummary{
+				{Mission: 1, Team: []string{"Bob", "Charlie"}, Success: false, NbFails: 1},
+			},
+		},
+	}))
+}
+
+func Test_GameSummarizedRevealsMissionCardsWhenEnabled(t *testing.T) {
+	dispatcher := &mockDispatcher{}
+	r := NewRecorder(dispatcher, true)
+	playShortGame(r)
+
+	g := NewWithT(t)
+	g.Expect(dispatcher.receivedMessages).To(HaveLen(1))
+	summary := dispatcher.receivedMessages[0].(messagebus.GameSummarized)
+	g.Expect(summary.Missions).To(Equal([]messagebus.MissionSummary{
+		{Mission: 1, Team: []string{"Bob", "Charlie"}, Success: false, NbFails: 1, FailedBy: []string{"Charlie"}},
+	}))
+}
+
+func Test_NothingDispatchedBeforeGameEnds(t *testing.T) {
+	dispatcher := &mockDispatcher{}
+	r := NewRecorder(dispatcher, false)
+	r.Consume(messagebus.AllegianceRevealed{AllegianceByPlayer: allegiances()})
+	r.Consume(messagebus.LeaderStartedToSelectMembers{Leader: "Alice"})
+	r.Consume(messagebus.MissionCompleted{Success: true, Outcomes: map[bool]int{true: 2}})
+
+	g := NewWithT(t)
+	g.Expect(dispatcher.receivedMessages).To(BeEmpty())
+}
@@ -0,0 +1,25 @@
+package tournament
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+type standingsGetter interface {
+	Standings() Standings
+}
+
+type tournamentServer struct {
+	standingsGetter standingsGetter
+}
+
+func Register(engine *gin.Engine, standingsGetter standingsGetter) {
+	tournamentServer := tournamentServer{
+		standingsGetter: standingsGetter,
+	}
+
+	engine.GET("/series/standings", tournamentServer.standings)
+}
+
+func (t tournamentServer) standings(c *gin.Context) {
+	c.JSON(200, t.standingsGetter.Standings())
+}
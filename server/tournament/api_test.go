@@ -0,0 +1,31 @@
+package tournament
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/gomega"
+)
+
+type mockStandingsGetter struct{}
+
+func (m mockStandingsGetter) Standings() Standings {
+	return Standings{NbGames: 3, GamesPlayed: 1, Players: []Standing{{Player: "Alice", Points: 3, Wins: 1, SpyWins: 1}}}
+}
+
+func Test_Standings(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	ginEngine := gin.New()
+	Register(ginEngine, mockStandingsGetter{})
+
+	req, _ := http.NewRequest("GET", "/series/standings", nil)
+	w := httptest.NewRecorder()
+	ginEngine.ServeHTTP(w, req)
+
+	g := NewWithT(t)
+	g.Expect(w.Code).To(Equal(200))
+	g.Expect(w.Body.String()).To(Equal(`{"nbGames":3,"gamesPlayed":1,"finished":false,` +
+		`"players":[{"player":"Alice","points":3,"wins":1,"resistanceWins":0,"spyWins":1}]}`))
+}
@@ -0,0 +1,137 @@
+package tournament
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/damien-springuel/bomb-canary/server/messagebus"
+)
+
+type messageDispatcher interface {
+	Dispatch(m messagebus.Message)
+}
+
+type Scoring struct {
+	PointsPerWin       int
+	ResistanceWinBonus int
+	SpyWinBonus        int
+}
+
+type Standing struct {
+	Player         string `json:"player"`
+	Points         int    `json:"points"`
+	Wins           int    `json:"wins"`
+	ResistanceWins int    `json:"resistanceWins"`
+	SpyWins        int    `json:"spyWins"`
+}
+
+type Standings struct {
+	NbGames     int        `json:"nbGames"`
+	GamesPlayed int        `json:"gamesPlayed"`
+	Finished    bool       `json:"finished"`
+	Players     []Standing `json:"players"`
+}
+
+type series struct {
+	mut               *sync.RWMutex
+	messageDispatcher messageDispatcher
+	nbGames           int
+	scoring           Scoring
+	rematchDelay      time.Duration
+	after             func(d time.Duration, f func())
+	gamesPlayed       int
+	allegiances       map[string]messagebus.Allegiance
+	standings         map[string]*Standing
+}
+
+func NewSeries(messageDispatcher messageDispatcher, nbGames int, scoring Scoring, rematchDelay time.Duration) *series {
+	return &series{
+		mut:               &sync.RWMutex{},
+		messageDispatcher: messageDispatcher,
+		nbGames:           nbGames,
+		scoring:           scoring,
+		rematchDelay:      rematchDelay,
+		after: func(d time.Duration, f func()) {
+			time.AfterFunc(d, f)
+		},
+		standings: make(map[string]*Standing),
+	}
+}
+
+func (s *series) Consume(m messagebus.Message) {
+	switch m := m.(type) {
+	case messagebus.AllegianceRevealed:
+		s.startGame(m.AllegianceByPlayer)
+	case messagebus.GameEnded:
+		s.endGame(m.Winner)
+	}
+}
+
+func (s *series) startGame(allegianceByPlayer map[string]messagebus.Allegiance) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	s.allegiances = allegianceByPlayer
+	for name := range allegianceByPlayer {
+		if _, exists := s.standings[name]; !exists {
+			s.standings[name] = &Standing{Player: name}
+		}
+	}
+}
+
+func (s *series) endGame(winner messagebus.Allegiance) {
+	s.mut.Lock()
+	defer s.mut.Unlock()
+
+	if s.allegiances == nil || s.gamesPlayed >= s.nbGames {
+		return
+	}
+
+	for name, allegiance := range s.allegiances {
+		if allegiance != winner {
+			continue
+		}
+		standing := s.standings[name]
+		standing.Wins += 1
+		standing.Points += s.scoring.PointsPerWin
+		if allegiance == messagebus.Spy {
+			standing.SpyWins += 1
+			standing.Points += s.scoring.SpyWinBonus
+		} else {
+			standing.ResistanceWins += 1
+			standing.Points += s.scoring.ResistanceWinBonus
+		}
+	}
+	s.allegiances = nil
+	s.gamesPlayed += 1
+
+	if s.gamesPlayed < s.nbGames {
+		s.after(s.rematchDelay, func() {
+			s.messageDispatcher.Dispatch(messagebus.StartRematch{})
+		})
+	}
+}
+
+func (s *series) Standings() Standings {
+	s.mut.RLock()
+	defer s.mut.RUnlock()
+
+	players := make([]Standing, 0, len(s.standings))
+	for _, standing := range s.standings {
+		players = append(players, *standing)
+	}
+	sort.Slice(players, func(i, j int) bool {
+		if players[i].Points != players[j].Points {
+			return players[i].Points > players[j].Points
+		}
+		return players[i].Player < players[j].Player
+	})
+
+	return Standings{
+		NbGames:     s.nbGames,
+		GamesPlayed: s.gamesPlayed,
+		Finished:    s.gamesPlayed >= s.nbGames,
+		Players:     players,
+	}
+}
@@ -0,0 +1,99 @@
+package tournament
+
+import (
+	"testing"
+	"time"
+
+	"github.com/damien-springuel/bomb-canary/server/messagebus"
+	. "github.com/onsi/gomega"
+)
+
+type mockDispatcher struct {
+	receivedMessages []messagebus.Message
+}
+
+func (m *mockDispatcher) Dispatch(message messagebus.Message) {
+	m.receivedMessages = append(m.receivedMessages, message)
+}
+
+type scheduledCall struct {
+	delay time.Duration
+	call  func()
+}
+
+func setupSeries(nbGames int) (*mockDispatcher, *[]scheduledCall, *series) {
+	dispatcher := &mockDispatcher{}
+	scheduled := &[]scheduledCall{}
+	s := NewSeries(dispatcher, nbGames, Scoring{PointsPerWin: 2, ResistanceWinBonus: 0, SpyWinBonus: 1}, 20*time.Second)
+	s.after = func(d time.Duration, f func()) {
+		*scheduled = append(*scheduled, scheduledCall{delay: d, call: f})
+	}
+	return dispatcher, scheduled, s
+}
+
+func playGame(s *series, spies []string, resistance []string, winner messagebus.Allegiance) {
+	allegiances := make(map[string]messagebus.Allegiance)
+	for _, name := range spies {
+		allegiances[name] = messagebus.Spy
+	}
+	for _, name := range resistance {
+		allegiances[name] = messagebus.Resistance
+	}
+	s.Consume(messagebus.AllegianceRevealed{AllegianceByPlayer: allegiances})
+	s.Consume(messagebus.GameEnded{Winner: winner, Spies: spies})
+}
+
+func Test_Series_ScoresWinsWithAllegianceBonus(t *testing.T) {
+	_, _, s := setupSeries(3)
+	playGame(s, []string{"Alice"}, []string{"Bob", "Charlie"}, messagebus.Spy)
+	playGame(s, []string{"Bob"}, []string{"Alice", "Charlie"}, messagebus.Resistance)
+
+	g := NewWithT(t)
+	g.Expect(s.Standings()).To(Equal(Standings{
+		NbGames:     3,
+		GamesPlayed: 2,
+		Finished:    false,
+		Players: []Standing{
+			{Player: "Alice", Points: 5, Wins: 2, ResistanceWins: 1, SpyWins: 1},
+			{Player: "Charlie", Points: 2, Wins: 1, ResistanceWins: 1},
+			{Player: "Bob", Points: 0},
+		},
+	}))
+}
+
+func Test_Series_SchedulesARematchUntilTheLastGame(t *testing.T) {
+	dispatcher, scheduled, s := setupSeries(2)
+	playGame(s, []string{"Alice"}, []string{"Bob"}, messagebus.Spy)
+
+	g := NewWithT(t)
+	g.Expect(*scheduled).To(HaveLen(1))
+	g.Expect((*scheduled)[0].delay).To(Equal(20 * time.Second))
+	g.Expect(dispatcher.receivedMessages).To(BeEmpty())
+	(*scheduled)[0].call()
+	g.Expect(dispatcher.receivedMessages).To(Equal([]messagebus.Message{messagebus.StartRematch{}}))
+
+	playGame(s, []string{"Bob"}, []string{"Alice"}, messagebus.Spy)
+	g.Expect(*scheduled).To(HaveLen(1))
+	g.Expect(s.Standings().Finished).To(BeTrue())
+}
+
+func Test_Series_IgnoresGamesAfterTheSeriesIsFinished(t *testing.T) {
+	_, scheduled, s := setupSeries(1)
+	playGame(s, []string{"Alice"}, []string{"Bob"}, messagebus.Spy)
+	playGame(s, []string{"Alice"}, []string{"Bob"}, messagebus.Spy)
+
+	g := NewWithT(t)
+	g.Expect(*scheduled).To(BeEmpty())
+	standings := s.Standings()
+	g.Expect(standings.GamesPlayed).To(Equal(1))
+	g.Expect(standings.Players[0]).To(Equal(Standing{Player: "Alice", Points: 3, Wins: 1, SpyWins: 1}))
+}
+
+func Test_Series_IgnoresGameEndedWithoutAllegiances(t *testing.T) {
+	_, scheduled, s := setupSeries(2)
+	s.Consume(messagebus.GameEnded{Winner: messagebus.Spy})
+
+	g := NewWithT(t)
+	g.Expect(*scheduled).To(BeEmpty())
+	g.Expect(s.Standings()).To(Equal(Standings{NbGames: 2, Players: []Standing{}}))
+}
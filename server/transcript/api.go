@@ -0,0 +1,64 @@
+package transcript
+
+import (
+	"github.com/damien-springuel/bomb-canary/server/sessions"
+	"github.com/gin-gonic/gin"
+)
+
+type sessionGetter interface {
+	Get(session string) (name string, err error)
+}
+
+type exporter interface {
+	Transcript() (Transcript, error)
+}
+
+type transcriptServer struct {
+	sessionGetter sessionGetter
+	exporter      exporter
+}
+
+func Register(engine *gin.Engine, sessionGetter sessionGetter, exporter exporter) {
+	transcriptServer := transcriptServer{
+		sessionGetter: sessionGetter,
+		exporter:      exporter,
+	}
+
+	engine.GET("/game/export", transcriptServer.checkSession, transcriptServer.export)
+}
+
+func (t transcriptServer) checkSession(c *gin.Context) {
+	session, err := sessions.FromRequest(c.Request)
+	if err != nil {
+		c.AbortWithStatus(401)
+		return
+	}
+
+	_, err = t.sessionGetter.Get(session)
+	if err != nil {
+		c.AbortWithStatus(403)
+		return
+	}
+
+	c.Next()
+}
+
+func (t transcriptServer) export(c *gin.Context) {
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "md" {
+		c.AbortWithStatusJSON(400, gin.H{"error": "format must be json or md"})
+		return
+	}
+
+	transcript, err := t.exporter.Transcript()
+	if err != nil {
+		c.AbortWithStatusJSON(409, gin.H{"error": err.Error()})
+		return
+	}
+
+	if format == "md" {
+		c.Data(200, "text/markdown; charset=utf-8", []byte(transcript.Markdown()))
+		return
+	}
+	c.JSON(200, transcript)
+}
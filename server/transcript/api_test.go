@@ -0,0 +1,127 @@
+package transcript
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	. "github.com/onsi/gomega"
+)
+
+type mockSessionGetter struct {
+	receivedSession string
+	getError        error
+}
+
+func (m *mockSessionGetter) Get(session string) (name string, err error) {
+	m.receivedSession = session
+	return "testName", m.getError
+}
+
+type mockExporter struct {
+	called      bool
+	exportError error
+}
+
+func (m *mockExporter) Transcript() (Transcript, error) {
+	m.called = true
+	return Transcript{
+		Players: []string{"Alice", "Bob"},
+		Rounds: []Round{
+			{Round: 1, Mission: 1, Leader: "Alice", Team: []string{"Alice", "Bob"}, Approvals: 1, Rejections: 1, Approved: false},
+		},
+	}, m.exportError
+}
+
+func makeCall(req *http.Request, sessionGetter *mockSessionGetter, exporter *mockExporter) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	ginEngine := gin.New()
+	Register(ginEngine, sessionGetter, exporter)
+
+	w := httptest.NewRecorder()
+	ginEngine.ServeHTTP(w, req)
+	return w
+}
+
+func Test_Export_Json(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/game/export?format=json", nil)
+	req.AddCookie(&http.Cookie{Name: "session", Value: "testSession"})
+	sessionGetter := &mockSessionGetter{}
+	w := makeCall(req, sessionGetter, &mockExporter{})
+
+	g := NewWithT(t)
+	g.Expect(w.Code).To(Equal(200))
+	g.Expect(w.Body.String()).To(Equal(`{"players":["Alice","Bob"],"rounds":[{"round":1,"mission":1,"leader":"Alice","team":["Alice","Bob"],"approvals":1,"rejections":1,"approved":false}]}`))
+	g.Expect(sessionGetter.receivedSession).To(Equal("testSession"))
+}
+
+func Test_Export_DefaultsToJson(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/game/export", nil)
+	req.Header.Set("Authorization", "Bearer testSession")
+	w := makeCall(req, &mockSessionGetter{}, &mockExporter{})
+
+	g := NewWithT(t)
+	g.Expect(w.Code).To(Equal(200))
+	g.Expect(w.Header().Get("Content-Type")).To(HavePrefix("application/json"))
+}
+
+func Test_Export_Markdown(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/game/export?format=md", nil)
+	req.AddCookie(&http.Cookie{Name: "session", Value: "testSession"})
+	w := makeCall(req, &mockSessionGetter{}, &mockExporter{})
+
+	g := NewWithT(t)
+	g.Expect(w.Code).To(Equal(200))
+	g.Expect(w.Header().Get("Content-Type")).To(Equal("text/markdown; charset=utf-8"))
+	g.Expect(w.Body.String()).To(Equal(`# Bomb Canary game
+
+Players: Alice, Bob
+
+- Round 1 (mission 1): Alice proposed Alice, Bob — rejected 1-1
+`))
+}
+
+func Test_Export_Returns400IfFormatUnknown(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/game/export?format=pdf", nil)
+	req.AddCookie(&http.Cookie{Name: "session", Value: "testSession"})
+	exporter := &mockExporter{}
+	w := makeCall(req, &mockSessionGetter{}, exporter)
+
+	g := NewWithT(t)
+	g.Expect(w.Code).To(Equal(400))
+	g.Expect(w.Body.String()).To(Equal(`{"error":"format must be json or md"}`))
+	g.Expect(exporter.called).To(BeFalse())
+}
+
+func Test_Export_Returns401IfNoSessionCookie(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/game/export", nil)
+	exporter := &mockExporter{}
+	w := makeCall(req, &mockSessionGetter{}, exporter)
+
+	g := NewWithT(t)
+	g.Expect(w.Code).To(Equal(401))
+	g.Expect(exporter.called).To(BeFalse())
+}
+
+func Test_Export_Returns403IfSessionInvalid(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/game/export", nil)
+	req.AddCookie(&http.Cookie{Name: "session", Value: "testSession"})
+	exporter := &mockExporter{}
+	w := makeCall(req, &mockSessionGetter{getError: errors.New("invalid")}, exporter)
+
+	g := NewWithT(t)
+	g.Expect(w.Code).To(Equal(403))
+	g.Expect(exporter.called).To(BeFalse())
+}
+
+func Test_Export_Returns409IfGameNotStarted(t *testing.T) {
+	req, _ := http.NewRequest("GET", "/game/export", nil)
+	req.AddCookie(&http.Cookie{Name: "session", Value: "testSession"})
+	w := makeCall(req, &mockSessionGetter{}, &mockExporter{exportError: errGameNotStarted})
+
+	g := NewWithT(t)
+	g.Expect(w.Code).To(Equal(409))
+	g.Expect(w.Body.String()).To(Equal(`{"error":"game hasn't started"}`))
+}
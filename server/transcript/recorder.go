@@ -0,0 +1,106 @@
+package transcript
+
+import (
+	"errors"
+	"sort"
+	"sync"
+
+	"github.com/damien-springuel/bomb-canary/server/messagebus"
+)
+
+var errGameNotStarted = errors.New("game hasn't started")
+
+type recorder struct {
+	mut        *sync.RWMutex
+	transcript Transcript
+	leader     string
+	team       []string
+	nbMissions int
+}
+
+func NewRecorder() *recorder {
+	return &recorder{
+		mut: &sync.RWMutex{},
+	}
+}
+
+func (r *recorder) Consume(m messagebus.Message) {
+	r.mut.Lock()
+	defer r.mut.Unlock()
+
+	switch m := m.(type) {
+	case messagebus.AllegianceRevealed:
+		players := make([]string, 0, len(m.AllegianceByPlayer))
+		for name := range m.AllegianceByPlayer {
+			players = append(players, name)
+		}
+		sort.Strings(players)
+		r.transcript = Transcript{Players: players, Rounds: []Round{}}
+		r.nbMissions = 0
+
+	case messagebus.ReplayRewound:
+		r.transcript = Transcript{}
+		r.leader = ""
+		r.team = nil
+		r.nbMissions = 0
+
+	case messagebus.LeaderStartedToSelectMembers:
+		r.leader = m.Leader
+		r.team = nil
+
+	case messagebus.LeaderSelectedMember:
+		r.team = append(r.team, m.SelectedMember)
+
+	case messagebus.LeaderDeselectedMember:
+		for i, member := range r.team {
+			if member == m.DeselectedMember {
+				r.team = append(r.team[:i:i], r.team[i+1:]...)
+				break
+			}
+		}
+
+	case messagebus.AllPlayerVotedOnTeam:
+		round := Round{
+			Round:    len(r.transcript.Rounds) + 1,
+			Mission:  r.nbMissions + 1,
+			Leader:   r.leader,
+			Team:     append([]string{}, r.team...),
+			Approved: m.Approved,
+		}
+		for _, approved := range m.PlayerVotes {
+			if approved {
+				round.Approvals += 1
+			} else {
+				round.Rejections += 1
+			}
+		}
+		r.transcript.Rounds = append(r.transcript.Rounds, round)
+
+	case messagebus.MissionCompleted:
+		r.nbMissions += 1
+		if len(r.transcript.Rounds) > 0 {
+			r.transcript.Rounds[len(r.transcript.Rounds)-1].Result = &MissionResult{
+				Success: m.Success,
+				NbFails: m.Outcomes[false],
+			}
+		}
+
+	case messagebus.GameEnded:
+		r.transcript.Winner = string(m.Winner)
+		r.transcript.Spies = append([]string{}, m.Spies...)
+		sort.Strings(r.transcript.Spies)
+	}
+}
+
+func (r *recorder) Transcript() (Transcript, error) {
+	r.mut.RLock()
+	defer r.mut.RUnlock()
+
+	if r.transcript.Players == nil {
+		return Transcript{}, errGameNotStarted
+	}
+
+	transcript := r.transcript
+	transcript.Rounds = append([]Round{}, r.transcript.Rounds...)
+	return transcript, nil
+}
@@ -0,0 +1,105 @@
+package transcript
+
+import (
+	"testing"
+
+	"github.com/damien-springuel/bomb-canary/server/messagebus"
+	. "github.com/onsi/gomega"
+)
+
+func playShortGame(r *recorder) {
+	r.Consume(messagebus.AllegianceRevealed{AllegianceByPlayer: map[string]messagebus.Allegiance{
+		"Bob":   messagebus.Resistance,
+		"Alice": messagebus.Spy,
+		"Carol": messagebus.Resistance,
+	}})
+
+	r.Consume(messagebus.LeaderStartedToSelectMembers{Leader: "Alice"})
+	r.Consume(messagebus.LeaderSelectedMember{SelectedMember: "Alice"})
+	r.Consume(messagebus.LeaderSelectedMember{SelectedMember: "Carol"})
+	r.Consume(messagebus.LeaderDeselectedMember{DeselectedMember: "Carol"})
+	r.Consume(messagebus.LeaderSelectedMember{SelectedMember: "Bob"})
+	r.Consume(messagebus.LeaderConfirmedSelection{})
+	r.Consume(messagebus.AllPlayerVotedOnTeam{Approved: false, VoteFailures: 1, PlayerVotes: map[string]bool{"Alice": true, "Bob": false, "Carol": false}})
+
+	r.Consume(messagebus.LeaderStartedToSelectMembers{Leader: "Bob"})
+	r.Consume(messagebus.LeaderSelectedMember{SelectedMember: "Bob"})
+	r.Consume(messagebus.LeaderSelectedMember{SelectedMember: "Alice"})
+	r.Consume(messagebus.LeaderConfirmedSelection{})
+	r.Consume(messagebus.AllPlayerVotedOnTeam{Approved: true, PlayerVotes: map[string]bool{"Alice": true, "Bob": true, "Carol": false}})
+	r.Consume(messagebus.MissionStarted{})
+	r.Consume(messagebus.MissionCompleted{Success: false, Outcomes: map[bool]int{true: 1, false: 1}})
+
+	r.Consume(messagebus.LeaderStartedToSelectMembers{Leader: "Carol"})
+	r.Consume(messagebus.LeaderSelectedMember{SelectedMember: "Carol"})
+	r.Consume(messagebus.LeaderSelectedMember{SelectedMember: "Bob"})
+	r.Consume(messagebus.LeaderConfirmedSelection{})
+	r.Consume(messagebus.AllPlayerVotedOnTeam{Approved: true, PlayerVotes: map[string]bool{"Alice": false, "Bob": true, "Carol": true}})
+	r.Consume(messagebus.MissionStarted{})
+	r.Consume(messagebus.MissionCompleted{Success: true, Outcomes: map[bool]int{true: 2}})
+
+	r.Consume(messagebus.GameEnded{Winner: messagebus.Spy, Spies: []string{"Alice"}})
+}
+
+func Test_Transcript(t *testing.T) {
+	r := NewRecorder()
+	playShortGame(r)
+
+	transcript, err := r.Transcript()
+
+	g := NewWithT(t)
+	g.Expect(err).To(BeNil())
+	g.Expect(transcript).To(Equal(Transcript{
+		Players: []string{"Alice", "Bob", "Carol"},
+		Rounds: []Round{
+			{Round: 1, Mission: 1, Leader: "Alice", Team: []string{"Alice", "Bob"}, Approvals: 1, Rejections: 2, Approved: false},
+			{Round: 2, Mission: 1, Leader: "Bob", Team: []string{"Bob", "Alice"}, Approvals: 2, Rejections: 1, Approved: true, Result: &MissionResult{Success: false, NbFails: 1}},
+			{Round: 3, Mission: 2, Leader: "Carol", Team: []string{"Carol", "Bob"}, Approvals: 2, Rejections: 1, Approved: true, Result: &MissionResult{Success: true, NbFails: 0}},
+		},
+		Winner: "spy",
+		Spies:  []string{"Alice"},
+	}))
+}
+
+func Test_Transcript_ShouldErrorIfGameNotStarted(t *testing.T) {
+	r := NewRecorder()
+	r.Consume(messagebus.PlayerJoined{Player: "Alice"})
+
+	_, err := r.Transcript()
+
+	g := NewWithT(t)
+	g.Expect(err).To(Equal(errGameNotStarted))
+}
+
+func Test_Transcript_ShouldOnlyContainTheLatestGame(t *testing.T) {
+	r := NewRecorder()
+	playShortGame(r)
+	r.Consume(messagebus.RematchStarted{Players: []string{"Bob", "Carol", "Alice"}})
+	r.Consume(messagebus.AllegianceRevealed{AllegianceByPlayer: map[string]messagebus.Allegiance{
+		"Alice": messagebus.Resistance,
+		"Bob":   messagebus.Spy,
+		"Carol": messagebus.Resistance,
+	}})
+	r.Consume(messagebus.LeaderStartedToSelectMembers{Leader: "Bob"})
+
+	transcript, err := r.Transcript()
+
+	g := NewWithT(t)
+	g.Expect(err).To(BeNil())
+	g.Expect(transcript).To(Equal(Transcript{
+		Players: []string{"Alice", "Bob", "Carol"},
+		Rounds:  []Round{},
+	}))
+}
+
+func Test_Transcript_ShouldErrorAfterARewindBeforeTheGameStarted(t *testing.T) {
+	r := NewRecorder()
+	playShortGame(r)
+	r.Consume(messagebus.ReplayRewound{})
+	r.Consume(messagebus.PlayerJoined{Player: "Alice"})
+
+	_, err := r.Transcript()
+
+	g := NewWithT(t)
+	g.Expect(err).To(Equal(errGameNotStarted))
+}
@@ -0,0 +1,79 @@
+package transcript
+
+import (
+	"fmt"
+	"strings"
+)
+
+type MissionResult struct {
+	Success bool `json:"success"`
+	NbFails int  `json:"nbFails"`
+}
+
+type Round struct {
+	Round      int            `json:"round"`
+	Mission    int            `json:"mission"`
+	Leader     string         `json:"leader"`
+	Team       []string       `json:"team"`
+	Approvals  int            `json:"approvals"`
+	Rejections int            `json:"rejections"`
+	Approved   bool           `json:"approved"`
+	Result     *MissionResult `json:"result,omitempty"`
+}
+
+type Transcript struct {
+	Players []string `json:"players"`
+	Rounds  []Round  `json:"rounds"`
+	Winner  string   `json:"winner,omitempty"`
+	Spies   []string `json:"spies,omitempty"`
+}
+
+func (t Transcript) Markdown() string {
+	var b strings.Builder
+	b.WriteString("# Bomb Canary game\n\n")
+	fmt.Fprintf(&b, "Players: %s\n\n", strings.Join(t.Players, ", "))
+
+	for _, round := range t.Rounds {
+		fmt.Fprintf(&b, "- %s\n", round.line())
+	}
+
+	if t.Winner != "" {
+		fmt.Fprintf(&b, "\n**%s won.** Spies were %s.\n", winnerName(t.Winner), strings.Join(t.Spies, ", "))
+	}
+	return b.String()
+}
+
+func (r Round) line() string {
+	vote := "rejected"
+	if r.Approved {
+		vote = "approved"
+	}
+	line := fmt.Sprintf("Round %d (mission %d): %s proposed %s — %s %d-%d",
+		r.Round, r.Mission, r.Leader, strings.Join(r.Team, ", "), vote, r.Approvals, r.Rejections)
+
+	if r.Result != nil {
+		outcome := "succeeded"
+		if !r.Result.Success {
+			outcome = "failed"
+		}
+		line += " — mission " + outcome
+		if r.Result.NbFails > 0 {
+			line += fmt.Sprintf(" with %s", plural(r.Result.NbFails, "fail"))
+		}
+	}
+	return line
+}
+
+func winnerName(winner string) string {
+	if winner == "spy" {
+		return "Spies"
+	}
+	return "Resistance"
+}
+
+func plural(n int, word string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, word)
+	}
+	return fmt.Sprintf("%d %ss", n, word)
+}
@@ -0,0 +1,51 @@
+package transcript
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func Test_Markdown(t *testing.T) {
+	transcript := Transcript{
+		Players: []string{"Alice", "Bob", "Carol", "Dan", "Edith"},
+		Rounds: []Round{
+			{Round: 1, Mission: 1, Leader: "Alice", Team: []string{"Alice", "Bob"}, Approvals: 1, Rejections: 4, Approved: false},
+			{Round: 2, Mission: 1, Leader: "Bob", Team: []string{"Bob", "Carol"}, Approvals: 4, Rejections: 1, Approved: true, Result: &MissionResult{Success: true}},
+			{Round: 3, Mission: 2, Leader: "Carol", Team: []string{"Bob", "Carol", "Dan"}, Approvals: 4, Rejections: 1, Approved: true, Result: &MissionResult{Success: false, NbFails: 1}},
+			{Round: 4, Mission: 3, Leader: "Dan", Team: []string{"Alice", "Dan"}, Approvals: 3, Rejections: 2, Approved: true, Result: &MissionResult{Success: false, NbFails: 2}},
+		},
+		Winner: "resistance",
+		Spies:  []string{"Alice", "Dan"},
+	}
+
+	g := NewWithT(t)
+	g.Expect(transcript.Markdown()).To(Equal(`# Bomb Canary game
+
+Players: Alice, Bob, Carol, Dan, Edith
+
+- Round 1 (mission 1): Alice proposed Alice, Bob — rejected 1-4
+- Round 2 (mission 1): Bob proposed Bob, Carol — approved 4-1 — mission succeeded
+- Round 3 (mission 2): Carol proposed Bob, Carol, Dan — approved 4-1 — mission failed with 1 fail
+- Round 4 (mission 3): Dan proposed Alice, Dan — approved 3-2 — mission failed with 2 fails
+
+**Resistance won.** Spies were Alice, Dan.
+`))
+}
+
+func Test_Markdown_GameInProgress(t *testing.T) {
+	transcript := Transcript{
+		Players: []string{"Alice", "Bob"},
+		Rounds: []Round{
+			{Round: 1, Mission: 1, Leader: "Alice", Team: []string{"Alice", "Bob"}, Approvals: 2, Rejections: 0, Approved: true},
+		},
+	}
+
+	g := NewWithT(t)
+	g.Expect(transcript.Markdown()).To(Equal(`# Bomb Canary game
+
+Players: Alice, Bob
+
+- Round 1 (mission 1): Alice proposed Alice, Bob — approved 2-0
+`))
+}